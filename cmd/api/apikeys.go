@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"order-pack-calculator/internal/app"
+	"order-pack-calculator/internal/domain/dto"
+	"strconv"
+	"strings"
+)
+
+const apiKeysUsage = "usage: apikeys list | create -name n -roles admin,calculator [-products 1,2] | revoke -id n"
+
+// runAPIKeys manages the API keys clients authenticate with, writing the results to out as JSON.
+// A created key is only ever printed once, the storage only keeps its hash.
+func runAPIKeys(a *app.App, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(apiKeysUsage)
+	}
+	ctx := context.Background()
+
+	var (
+		response any
+		err      error
+	)
+	switch args[0] {
+	case "list":
+		flags := flag.NewFlagSet("apikeys list", flag.ContinueOnError)
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		response, err = a.APIKeyService.List(ctx)
+	case "create":
+		var request dto.CreateAPIKeyRequest
+		flags := flag.NewFlagSet("apikeys create", flag.ContinueOnError)
+		flags.StringVar(&request.Name, "name", "", "name telling who or what the key is for")
+		flags.Func("roles", "comma separated roles of the key: admin, calculator", func(value string) error {
+			for _, role := range strings.Split(value, ",") {
+				request.Roles = append(request.Roles, strings.TrimSpace(role))
+			}
+			return nil
+		})
+		flags.Func("products", "comma separated products the key is restricted to, all when unset", func(value string) error {
+			for _, field := range strings.Split(value, ",") {
+				id, err := strconv.Atoi(strings.TrimSpace(field))
+				if err != nil {
+					return fmt.Errorf("invalid product id %q", field)
+				}
+				request.ProductIDs = append(request.ProductIDs, id)
+			}
+			return nil
+		})
+		if err := parseRequest(flags, args[1:], &request); err != nil {
+			return err
+		}
+		response, err = a.APIKeyService.Create(ctx, request)
+	case "revoke":
+		var id int64
+		flags := flag.NewFlagSet("apikeys revoke", flag.ContinueOnError)
+		flags.Int64Var(&id, "id", 0, "key to revoke")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if id == 0 {
+			return errors.New(apiKeysUsage)
+		}
+		if err := a.APIKeyService.Revoke(ctx, id); err != nil {
+			return err
+		}
+		response = map[string]any{"id": id, "revoked": true}
+	default:
+		return fmt.Errorf("unknown apikeys command %q. %s", args[0], apiKeysUsage)
+	}
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(response)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"order-pack-calculator/internal/app"
+	"order-pack-calculator/internal/domain/dto"
+	errs "order-pack-calculator/internal/domain/errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunAPIKeys(t *testing.T) {
+	a, err := app.New(app.Config{Storage: app.StorageConfig{Kind: "memory"}})
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	var created dto.CreatedAPIKeyResponse
+	t.Run("create", func(t *testing.T) {
+		var out bytes.Buffer
+		err := runAPIKeys(a, []string{"create", "-name", "ci", "-roles", "calculator", "-products", "1, 2"}, &out)
+		assert.NoError(t, err)
+
+		assert.NoError(t, json.Unmarshal(out.Bytes(), &created))
+		assert.Equal(t, "ci", created.Name)
+		assert.NotEmpty(t, created.Key)
+
+		principal, err := a.Authenticator.Authenticate(ctx, created.Key)
+		assert.NoError(t, err)
+		assert.Equal(t, "ci", principal.Name)
+		assert.Equal(t, []string{"calculator"}, principal.Roles)
+		assert.Equal(t, []int{1, 2}, principal.ProductIDs)
+	})
+
+	t.Run("list does not show keys", func(t *testing.T) {
+		var out bytes.Buffer
+		err := runAPIKeys(a, []string{"list"}, &out)
+		assert.NoError(t, err)
+		assert.NotContains(t, out.String(), created.Key)
+
+		var keys []dto.APIKeyResponse
+		assert.NoError(t, json.Unmarshal(out.Bytes(), &keys))
+		assert.Len(t, keys, 1)
+		assert.Equal(t, created.Prefix, keys[0].Prefix)
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		err := runAPIKeys(a, []string{"revoke", "-id", "1"}, &bytes.Buffer{})
+		assert.NoError(t, err)
+
+		_, err = a.Authenticator.Authenticate(ctx, created.Key)
+		assert.ErrorIs(t, err, errs.ErrUnauthenticated)
+
+		err = runAPIKeys(a, []string{"revoke", "-id", "1"}, &bytes.Buffer{})
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("invalid flags", func(t *testing.T) {
+		assert.ErrorContains(t, runAPIKeys(a, []string{"create", "-roles", "admin"}, &bytes.Buffer{}), "invalid apikeys create flags")
+		assert.ErrorContains(t, runAPIKeys(a, []string{"create", "-name", "ci"}, &bytes.Buffer{}), "invalid apikeys create flags")
+		assert.ErrorIs(t, runAPIKeys(a, []string{"create", "-name", "ci", "-roles", "root"}, &bytes.Buffer{}), errs.ErrValidation)
+		assert.Error(t, runAPIKeys(a, []string{"create", "-name", "ci", "-roles", "admin", "-products", "one"}, &bytes.Buffer{}))
+		assert.ErrorContains(t, runAPIKeys(a, []string{"revoke"}, &bytes.Buffer{}), "usage")
+		assert.ErrorContains(t, runAPIKeys(a, []string{"rotate"}, &bytes.Buffer{}), "unknown apikeys command")
+	})
+}
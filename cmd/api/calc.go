@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"order-pack-calculator/internal/domain/dto"
+	"order-pack-calculator/internal/domain/services"
+	"strconv"
+	"strings"
+)
+
+// calcResult is written as one JSON line per calculated quantity
+type calcResult struct {
+	OrderQuantity int `json:"order_quantity"`
+	*dto.OptimalPackSizesResponse
+}
+
+// runCalc solves the quantities given as arguments, or read one per line from in, against the
+// pack sizes of the -packs flag. Nothing is read from or stored in the database.
+func runCalc(args []string, in io.Reader, out io.Writer) error {
+	var packSizes []int
+	flags := flag.NewFlagSet("calc", flag.ContinueOnError)
+	flags.Func("packs", "comma separated pack sizes, e.g. 23,31,53", func(value string) error {
+		for _, field := range strings.Split(value, ",") {
+			size, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil || size < 1 {
+				return fmt.Errorf("invalid pack size %q", field)
+			}
+			packSizes = append(packSizes, size)
+		}
+		return nil
+	})
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if len(packSizes) == 0 {
+		return errors.New("usage: calc -packs 23,31,53 [quantity...]")
+	}
+
+	encoder := json.NewEncoder(out)
+	solve := func(value string) error {
+		quantity, err := strconv.Atoi(value)
+		if err != nil || quantity < 1 {
+			return fmt.Errorf("invalid order quantity %q", value)
+		}
+		solution, err := services.SolvePacks(quantity, packSizes)
+		if err != nil {
+			return err
+		}
+		return encoder.Encode(calcResult{OrderQuantity: quantity, OptimalPackSizesResponse: solution})
+	}
+
+	if flags.NArg() > 0 {
+		for _, value := range flags.Args() {
+			if err := solve(value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if err := solve(line); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunCalc(t *testing.T) {
+	t.Run("quantities from arguments", func(t *testing.T) {
+		var out bytes.Buffer
+		err := runCalc([]string{"-packs", "23,31,53", "263"}, strings.NewReader(""), &out)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"order_quantity":263,"pack_combination":[{"size":31,"count":7},{"size":23,"count":2}],"total_items":263,"total_packs":9}`, out.String())
+	})
+
+	t.Run("quantities from stdin", func(t *testing.T) {
+		var out bytes.Buffer
+		err := runCalc([]string{"-packs", "250, 500"}, strings.NewReader("1\n\n501\n"), &out)
+		assert.NoError(t, err)
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		assert.Len(t, lines, 2)
+		assert.Contains(t, lines[0], `"total_items":250`)
+		assert.Contains(t, lines[1], `"total_items":750`)
+	})
+
+	t.Run("invalid quantity", func(t *testing.T) {
+		err := runCalc([]string{"-packs", "250"}, strings.NewReader("ten\n"), &bytes.Buffer{})
+		assert.ErrorContains(t, err, `invalid order quantity "ten"`)
+	})
+
+	t.Run("missing pack sizes", func(t *testing.T) {
+		err := runCalc([]string{"10"}, strings.NewReader(""), &bytes.Buffer{})
+		assert.ErrorContains(t, err, "usage")
+	})
+}
@@ -2,54 +2,86 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
-	"net/http"
-	"order-pack-calculator/internal/server"
-	"os/signal"
-	"syscall"
-	"time"
+	"log/slog"
+	"order-pack-calculator/internal/app"
+	"order-pack-calculator/internal/logging"
+	"order-pack-calculator/internal/tracing"
+	"os"
 )
 
-func gracefulShutdown(apiServer *http.Server, done chan bool) {
-	// Create context that listens for the interrupt signal from the OS.
-	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
-	defer stop()
+const usage = `usage: order-pack-calculator-api [command] [arguments]
 
-	// Listen for the interrupt signal.
-	<-ctx.Done()
+commands:
+  serve [-port n] [-migrate]          start the HTTP server (default)
+  migrate up | down [steps] | status  manage the database schema
+  seed [file]                         load pack sizes from a YAML or JSON seed file
+  calc -packs 23,31,53 [quantity...]  calculate pack combinations offline, reading quantities from stdin when none are given
+  packsizes list | create | update    administer pack sizes, run with -h for their flags
+  apikeys list | create | revoke      manage the API keys clients authenticate with
 
-	log.Println("shutting down gracefully, press Ctrl+C again to force")
+The storage and the rest of the configuration are read from the environment and the .env file.
+`
 
-	// The context is used to inform the server it has 5 seconds to finish
-	// the request it is currently handling
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if err := apiServer.Shutdown(ctx); err != nil {
-		log.Printf("Server forced to shutdown with error: %v", err)
-	}
-
-	log.Println("Server exiting")
-
-	// Notify the main goroutine that the shutdown is complete
-	done <- true
-}
+// @securityDefinitions.apikey  APIKey
+// @in                          header
+// @name                        X-API-Key
+// @description                 API key created with the apikeys command
 
+// @securityDefinitions.apikey  BearerToken
+// @in                          header
+// @name                        Authorization
+// @description                 JWT sent as "Bearer <token>"
 func main() {
-	server := server.NewServer()
+	logging.Setup()
+	flushTraces := tracing.Setup()
 
-	// Create a done channel to signal when the shutdown is complete
-	done := make(chan bool, 1)
+	command, args := "serve", os.Args[1:]
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
 
-	// Run graceful shutdown in a separate goroutine
-	go gracefulShutdown(server, done)
+	var err error
+	switch command {
+	case "serve":
+		err = runServe(args)
+	case "migrate":
+		err = runMigrate(args, os.Stdout)
+	case "seed":
+		err = runSeed(args, os.Stdout)
+	case "calc":
+		err = runCalc(args, os.Stdin, os.Stdout)
+	case "packsizes":
+		err = withApp(func(a *app.App) error { return runPackSizes(a, args, os.Stdout) })
+	case "apikeys":
+		err = withApp(func(a *app.App) error { return runAPIKeys(a, args, os.Stdout) })
+	case "help", "-h", "-help", "--help":
+		fmt.Print(usage)
+	default:
+		err = fmt.Errorf("unknown command %q\n\n%s", command, usage)
+	}
+	flushTraces()
 
-	err := server.ListenAndServe()
-	if err != nil && err != http.ErrServerClosed {
-		panic(fmt.Sprintf("http server error: %s", err))
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		slog.Error("command failed", "command", command, "error", err)
+		os.Exit(1)
 	}
+}
 
-	// Wait for the graceful shutdown to complete
-	<-done
-	log.Println("Graceful shutdown complete.")
+// withApp runs fn with the services of the storage configured in the environment,
+// once its schema is known to be up to date
+func withApp(fn func(a *app.App) error) error {
+	a, err := app.New(app.ConfigFromEnv())
+	if err != nil {
+		return err
+	}
+	if err := a.CheckSchema(context.Background()); err != nil {
+		return err
+	}
+	return fn(a)
 }
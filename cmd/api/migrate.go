@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"order-pack-calculator/internal/app"
+	"strconv"
+)
+
+const migrateUsage = "usage: migrate up | down [steps] | status"
+
+// runMigrate manages the schema of the configured storage with the migrations embedded in the binary,
+// writing the migrations applied, reverted or pending to out
+func runMigrate(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	a, err := app.New(app.ConfigFromEnv())
+	if err != nil {
+		return err
+	}
+	if a.Migrator == nil {
+		return fmt.Errorf("storage %q has no schema to migrate", a.Config.Storage.Kind)
+	}
+	migrator, ctx := a.Migrator, context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Fprintf(out, "applied %d_%s\n", migration.Version, migration.Name)
+		}
+		return err
+	case "down":
+		// A single migration is reverted unless told otherwise, 0 reverting them all
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 0 {
+				return fmt.Errorf("invalid number of steps %q. %s", args[1], migrateUsage)
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, migration := range reverted {
+			fmt.Fprintf(out, "reverted %d_%s\n", migration.Version, migration.Name)
+		}
+		return err
+	case "status":
+		status, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "version: %d (latest %d)\n", status.Version, status.Latest)
+		if status.Dirty {
+			fmt.Fprintln(out, "dirty: the last migration did not complete")
+		}
+		for _, migration := range status.Pending {
+			fmt.Fprintf(out, "pending %d_%s\n", migration.Version, migration.Name)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate command %q. %s", args[0], migrateUsage)
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunMigrate(t *testing.T) {
+	t.Setenv("STORAGE", "sqlite")
+	t.Setenv("SQLITE_PATH", filepath.Join(t.TempDir(), "test.db"))
+
+	t.Run("status of an empty database", func(t *testing.T) {
+		var out bytes.Buffer
+		assert.NoError(t, runMigrate([]string{"status"}, &out))
+		assert.True(t, strings.HasPrefix(out.String(), "version: 0 (latest 10)\npending 1_create_table_pack_sizes\n"), out.String())
+	})
+
+	t.Run("up", func(t *testing.T) {
+		var out bytes.Buffer
+		assert.NoError(t, runMigrate([]string{"up"}, &out))
+		assert.Len(t, strings.Split(strings.TrimSpace(out.String()), "\n"), 10)
+		assert.True(t, strings.HasPrefix(out.String(), "applied 1_create_table_pack_sizes\n"), out.String())
+	})
+
+	t.Run("down", func(t *testing.T) {
+		var out bytes.Buffer
+		assert.NoError(t, runMigrate([]string{"down", "2"}, &out))
+		assert.True(t, strings.HasPrefix(out.String(), "reverted 10_"), out.String())
+		assert.Len(t, strings.Split(strings.TrimSpace(out.String()), "\n"), 2)
+
+		out.Reset()
+		assert.NoError(t, runMigrate([]string{"status"}, &out))
+		assert.True(t, strings.HasPrefix(out.String(), "version: 8 (latest 10)\npending 9_"), out.String())
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		assert.ErrorContains(t, runMigrate(nil, &bytes.Buffer{}), "usage")
+		assert.ErrorContains(t, runMigrate([]string{"down", "-1"}, &bytes.Buffer{}), "invalid number of steps")
+		assert.ErrorContains(t, runMigrate([]string{"redo"}, &bytes.Buffer{}), "unknown migrate command")
+	})
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"order-pack-calculator/internal/app"
+	"order-pack-calculator/internal/domain/dto"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+const packSizesUsage = "usage: packsizes list | create | update [flags]"
+
+// runPackSizes administers pack sizes through the same service and validation as the HTTP API,
+// writing the results to out as JSON
+func runPackSizes(a *app.App, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(packSizesUsage)
+	}
+	ctx := context.Background()
+
+	var (
+		response any
+		err      error
+	)
+	switch args[0] {
+	case "list":
+		var request dto.ListPackSizesRequest
+		flags := flag.NewFlagSet("packsizes list", flag.ContinueOnError)
+		flags.IntVar(&request.ProductID, "product", 0, "only list the pack sizes of the product")
+		flags.Func("active", "only list active (true) or inactive (false) pack sizes", boolFlag(&request.Active))
+		flags.IntVar(&request.MinSize, "min-size", 0, "smallest size listed")
+		flags.IntVar(&request.MaxSize, "max-size", 0, "largest size listed")
+		flags.StringVar(&request.Sort, "sort", "", "id, product_id or size")
+		flags.StringVar(&request.Order, "order", "", "asc or desc")
+		flags.IntVar(&request.Limit, "limit", 0, "page size, at most 100")
+		flags.StringVar(&request.Cursor, "cursor", "", "next_cursor of the previous page")
+		if err := parseRequest(flags, args[1:], &request); err != nil {
+			return err
+		}
+		response, err = a.PackSizeService.GetAll(ctx, request)
+	case "create":
+		var request dto.CreatePackSizeRequest
+		flags := flag.NewFlagSet("packsizes create", flag.ContinueOnError)
+		flags.IntVar(&request.ProductID, "product", 0, "product of the pack size")
+		flags.IntVar(&request.Size, "size", 0, "number of items in the pack")
+		flags.Func("valid-from", "RFC 3339 instant the pack size becomes available", timeFlag(&request.ValidFrom))
+		flags.Func("valid-to", "RFC 3339 instant the pack size stops being available", timeFlag(&request.ValidTo))
+		if err := parseRequest(flags, args[1:], &request); err != nil {
+			return err
+		}
+		response, err = a.PackSizeService.Create(ctx, request)
+	case "update":
+		var request dto.UpdatePackSizeRequest
+		flags := flag.NewFlagSet("packsizes update", flag.ContinueOnError)
+		flags.Int64Var(&request.ID, "id", 0, "pack size to update")
+		flags.Func("size", "number of items in the pack", intFlag(&request.Size))
+		flags.Func("active", "whether the pack size can be used", boolFlag(&request.Active))
+		flags.Func("valid-from", "RFC 3339 instant the pack size becomes available, empty to clear it", nullableTimeFlag(&request.ValidFrom))
+		flags.Func("valid-to", "RFC 3339 instant the pack size stops being available, empty to clear it", nullableTimeFlag(&request.ValidTo))
+		flags.Func("version", "reject the update unless the pack size is still at this version, repeated to accept any of several", int64sFlag(&request.Versions))
+		if err := parseRequest(flags, args[1:], &request); err != nil {
+			return err
+		}
+		response, err = a.PackSizeService.Update(ctx, request)
+	default:
+		return fmt.Errorf("unknown packsizes command %q. %s", args[0], packSizesUsage)
+	}
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(response)
+}
+
+// parseRequest parses the flags into request and validates it as the HTTP API does
+func parseRequest(flags *flag.FlagSet, args []string, request any) error {
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := binding.Validator.ValidateStruct(request); err != nil {
+		return fmt.Errorf("invalid %s flags: %w", flags.Name(), err)
+	}
+	return nil
+}
+
+func intFlag(target **int) func(string) error {
+	return func(value string) error {
+		n, err := strconv.Atoi(value)
+		*target = &n
+		return err
+	}
+}
+
+func int64sFlag(target *[]int64) func(string) error {
+	return func(value string) error {
+		n, err := strconv.ParseInt(value, 10, 64)
+		*target = append(*target, n)
+		return err
+	}
+}
+
+func boolFlag(target **bool) func(string) error {
+	return func(value string) error {
+		b, err := strconv.ParseBool(value)
+		*target = &b
+		return err
+	}
+}
+
+func timeFlag(target **time.Time) func(string) error {
+	return func(value string) error {
+		t, err := time.Parse(time.RFC3339, value)
+		*target = &t
+		return err
+	}
+}
+
+func nullableTimeFlag(target *dto.NullableTime) func(string) error {
+	return func(value string) error {
+		if value == "" {
+			*target = dto.SetTime(nil)
+			return nil
+		}
+		var t *time.Time
+		err := timeFlag(&t)(value)
+		*target = dto.SetTime(t)
+		return err
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"order-pack-calculator/internal/app"
+	"order-pack-calculator/internal/domain/dto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunPackSizes(t *testing.T) {
+	a, err := app.New(app.Config{Storage: app.StorageConfig{Kind: "memory", SeedPath: "../../seeds/pack_sizes.yaml"}})
+	assert.NoError(t, err)
+
+	t.Run("create", func(t *testing.T) {
+		var out bytes.Buffer
+		err := runPackSizes(a, []string{"create", "-product", "2", "-size", "10", "-valid-from", "2026-11-01T00:00:00Z"}, &out)
+		assert.NoError(t, err)
+
+		var created dto.PackSizeResponse
+		assert.NoError(t, json.Unmarshal(out.Bytes(), &created))
+		assert.Equal(t, 10, created.Size)
+		assert.NotNil(t, created.ValidFrom)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		var out bytes.Buffer
+		err := runPackSizes(a, []string{"list", "-product", "1", "-sort", "size", "-order", "desc"}, &out)
+		assert.NoError(t, err)
+
+		var page dto.PackSizePageResponse
+		assert.NoError(t, json.Unmarshal(out.Bytes(), &page))
+		assert.Equal(t, int64(3), page.Total)
+		assert.Equal(t, 53, page.Items[0].Size)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		var out bytes.Buffer
+		err := runPackSizes(a, []string{"update", "-id", "1", "-size", "24", "-active", "false", "-version", "1"}, &out)
+		assert.NoError(t, err)
+
+		var updated dto.PackSizeResponse
+		assert.NoError(t, json.Unmarshal(out.Bytes(), &updated))
+		assert.Equal(t, 24, updated.Size)
+		assert.False(t, updated.Active)
+		assert.Equal(t, int64(2), updated.Version)
+	})
+
+	t.Run("flags are validated like requests", func(t *testing.T) {
+		err := runPackSizes(a, []string{"create", "-product", "2"}, &bytes.Buffer{})
+		assert.ErrorContains(t, err, "invalid packsizes create flags")
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		err := runPackSizes(a, []string{"delete"}, &bytes.Buffer{})
+		assert.ErrorContains(t, err, "unknown packsizes command")
+	})
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"order-pack-calculator/internal/app"
+	"order-pack-calculator/internal/domain/repositories"
+)
+
+// runSeed loads the pack sizes of a seed file, STORAGE_SEED by default, into the configured storage,
+// writing the file loaded to out
+func runSeed(args []string, out io.Writer) error {
+	config := app.ConfigFromEnv()
+	path := config.Storage.SeedPath
+	if len(args) > 0 {
+		path = args[0]
+	}
+	if path == "" {
+		return errors.New("usage: seed <file>")
+	}
+	if config.Storage.Kind == "memory" {
+		return errors.New("the memory storage does not outlive the command, set STORAGE_SEED to seed it on start")
+	}
+
+	a, err := app.New(config)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	if err := a.CheckSchema(ctx); err != nil {
+		return err
+	}
+	if err := repositories.LoadSeed(ctx, path, a.PackSizeRepository); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "loaded %s\n", path)
+	return nil
+}
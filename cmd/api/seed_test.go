@@ -0,0 +1,25 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunSeed(t *testing.T) {
+	t.Setenv("STORAGE", "sqlite")
+	t.Setenv("SQLITE_PATH", filepath.Join(t.TempDir(), "test.db"))
+	t.Setenv("STORAGE_SEED", "")
+	assert.NoError(t, runMigrate([]string{"up"}, &bytes.Buffer{}))
+
+	var out bytes.Buffer
+	assert.NoError(t, runSeed([]string{"../../seeds/pack_sizes.yaml"}, &out))
+	assert.Equal(t, "loaded ../../seeds/pack_sizes.yaml\n", out.String())
+
+	assert.ErrorContains(t, runSeed(nil, &bytes.Buffer{}), "usage")
+
+	t.Setenv("STORAGE", "memory")
+	assert.ErrorContains(t, runSeed([]string{"../../seeds/pack_sizes.yaml"}, &bytes.Buffer{}), "does not outlive the command")
+}
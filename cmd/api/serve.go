@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"order-pack-calculator/internal/app"
+	"order-pack-calculator/internal/health"
+	"order-pack-calculator/internal/server"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// gracefulShutdown stops the server once the process is interrupted. Readiness fails first, and the server keeps
+// serving for the delay so load balancers stop sending it requests before it stops accepting connections.
+func gracefulShutdown(apiServer *http.Server, readiness *health.Registry, delay time.Duration, done chan bool) {
+	// Create context that listens for the interrupt signal from the OS.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Listen for the interrupt signal.
+	<-ctx.Done()
+	// Restore the default behavior of the signals, so a second one kills the process during the delay
+	stop()
+
+	slog.Info("shutting down gracefully, press Ctrl+C again to force", "delay", delay.String())
+	readiness.Shutdown()
+	time.Sleep(delay)
+
+	// The context is used to inform the server it has 5 seconds to finish
+	// the request it is currently handling
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := apiServer.Shutdown(ctx); err != nil {
+		slog.Error("server forced to shut down", "error", err)
+	}
+
+	slog.Info("server exiting")
+
+	// Notify the main goroutine that the shutdown is complete
+	done <- true
+}
+
+// runServe starts the HTTP server and blocks until it is shut down
+func runServe(args []string) error {
+	config := app.ConfigFromEnv()
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	flags.IntVar(&config.Port, "port", config.Port, "port to listen on, PORT by default")
+	flags.BoolVar(&config.MigrateOnStart, "migrate", config.MigrateOnStart, "apply the pending migrations before serving, MIGRATE_ON_START by default")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	a, err := app.New(config)
+	if err != nil {
+		return err
+	}
+	if err := a.CheckSchema(context.Background()); err != nil {
+		return fmt.Errorf("refusing to serve: %w", err)
+	}
+	server := server.NewServer(a)
+
+	// Create a done channel to signal when the shutdown is complete
+	done := make(chan bool, 1)
+
+	// Run graceful shutdown in a separate goroutine
+	go gracefulShutdown(server, a.Health, a.Config.ShutdownDelay, done)
+
+	slog.Info("serving", "addr", server.Addr, "storage", a.Config.Storage.Kind)
+	err = server.ListenAndServe()
+	if err != nil && err != http.ErrServerClosed {
+		panic(fmt.Sprintf("http server error: %s", err))
+	}
+
+	// Wait for the graceful shutdown to complete
+	<-done
+	slog.Info("graceful shutdown complete")
+	return nil
+}
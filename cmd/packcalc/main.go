@@ -0,0 +1,106 @@
+// Command packcalc calculates the optimal packs of a batch of orders, read as CSV or JSON lines,
+// with the same solver as the API. Pack sizes come from a flag, a seed file or the configured database.
+//
+//	packcalc -packs 23,31,53 < orders.csv
+//	packcalc -packs-file seeds/pack_sizes.yaml -in orders.jsonl -format table
+//	packcalc -db -in orders.csv -format jsonl > packs.jsonl
+//
+// Orders that cannot be packed are reported on stderr with their line number, the others are still
+// written, and the exit code is 1.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// Exit codes
+const (
+	exitOK = iota
+	// exitInfeasible means some orders could not be packed
+	exitInfeasible
+	// exitUsage means the command could not run at all
+	exitUsage
+)
+
+// run executes the command and returns its exit code
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	var (
+		packs, packsFile, in, inFormat, format string
+		fromDB                                 bool
+	)
+	flags := flag.NewFlagSet("packcalc", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&packs, "packs", "", "comma separated pack sizes used for every order, e.g. 23,31,53")
+	flags.StringVar(&packsFile, "packs-file", "", "YAML or JSON seed file holding the pack sizes of each product")
+	flags.BoolVar(&fromDB, "db", false, "read the pack sizes of each product from the storage configured in the environment")
+	flags.StringVar(&in, "in", "", "file of orders, stdin by default")
+	flags.StringVar(&inFormat, "in-format", "", "csv or jsonl, guessed from the -in extension and csv by default")
+	flags.StringVar(&format, "format", formatCSV, "output format: csv, jsonl or table")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	fail := func(err error) int {
+		fmt.Fprintf(stderr, "packcalc: %v\n", err)
+		return exitUsage
+	}
+
+	ctx := context.Background()
+	sizes, err := newPackSizeSource(ctx, packs, packsFile, fromDB)
+	if err != nil {
+		return fail(err)
+	}
+
+	input := stdin
+	if in != "" {
+		file, err := os.Open(in)
+		if err != nil {
+			return fail(err)
+		}
+		defer file.Close()
+		input = file
+		if inFormat == "" {
+			inFormat = strings.TrimPrefix(filepath.Ext(in), ".")
+		}
+	}
+	orders, err := newOrderReader(input, inFormat)
+	if err != nil {
+		return fail(err)
+	}
+	results, err := newResultWriter(stdout, format)
+	if err != nil {
+		return fail(err)
+	}
+
+	code := exitOK
+	for {
+		order, err := orders.Next()
+		if err == io.EOF {
+			break
+		}
+		if err == nil {
+			var result *result
+			if result, err = solve(ctx, sizes, order); err == nil {
+				err = results.Write(*result)
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "line %d: %v\n", order.Line, err)
+			code = exitInfeasible
+		}
+	}
+	if err := results.Flush(); err != nil {
+		return fail(err)
+	}
+	return code
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	t.Run("csv to csv with fixed pack sizes", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		orders := "reference,order_quantity\nA1,263\nA2,1\n"
+
+		code := run([]string{"-packs", "23,31,53"}, strings.NewReader(orders), &stdout, &stderr)
+		assert.Equal(t, exitOK, code)
+		assert.Empty(t, stderr.String())
+		assert.Equal(t, "line,reference,product_id,order_quantity,total_items,total_packs,packs\n"+
+			"2,A1,0,263,263,9,7x31 2x23\n"+
+			"3,A2,0,1,23,1,1x23\n", stdout.String())
+	})
+
+	t.Run("jsonl file to jsonl with pack sizes per product", func(t *testing.T) {
+		in := filepath.Join(t.TempDir(), "orders.jsonl")
+		orders := `{"reference":"B1","product_id":1,"order_quantity":500000}` + "\n" + `{"reference":"B2","product_id":2,"order_quantity":10}` + "\n"
+		assert.NoError(t, os.WriteFile(in, []byte(orders), 0o600))
+
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"-packs-file", "../../seeds/pack_sizes.yaml", "-in", in, "-format", "jsonl"}, nil, &stdout, &stderr)
+		assert.Equal(t, exitInfeasible, code)
+		assert.JSONEq(t, `{"line":1,"reference":"B1","product_id":1,"order_quantity":500000,"pack_combination":[{"size":53,"count":9429},{"size":31,"count":7},{"size":23,"count":2}],"total_items":500000,"total_packs":9438}`, stdout.String())
+		assert.Contains(t, stderr.String(), "line 2: no pack sizes available for product_id=2")
+	})
+
+	t.Run("table", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"-packs", "250,500", "-format", "table"}, strings.NewReader("order_quantity\n751\n"), &stdout, &stderr)
+		assert.Equal(t, exitOK, code)
+		assert.Equal(t, "line  reference  product_id  order_quantity  total_items  total_packs  packs\n"+
+			"2                0           751             1000         2            2x500\n", stdout.String())
+	})
+
+	t.Run("invalid lines are reported and skipped", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		orders := "order_quantity,product_id\nten,1\n0,1\n5,x\n5,1\n"
+
+		code := run([]string{"-packs", "5"}, strings.NewReader(orders), &stdout, &stderr)
+		assert.Equal(t, exitInfeasible, code)
+		assert.Equal(t, "line 2: invalid order_quantity \"ten\"\n"+
+			"line 3: order_quantity must be positive, got 0\n"+
+			"line 4: invalid product_id \"x\"\n", stderr.String())
+		assert.Contains(t, stdout.String(), "5,,1,5,5,1,1x5")
+	})
+
+	t.Run("usage errors", func(t *testing.T) {
+		tests := []struct {
+			name string
+			args []string
+			in   string
+		}{
+			{"no pack sizes", nil, "order_quantity\n1\n"},
+			{"several pack size sources", []string{"-packs", "5", "-packs-file", "seed.yaml"}, "order_quantity\n1\n"},
+			{"invalid pack size", []string{"-packs", "5,-1"}, "order_quantity\n1\n"},
+			{"missing quantity column", []string{"-packs", "5"}, "quantity\n1\n"},
+			{"unknown input format", []string{"-packs", "5", "-in-format", "xml"}, ""},
+			{"unknown output format", []string{"-packs", "5", "-format", "xml"}, "order_quantity\n1\n"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var stdout, stderr bytes.Buffer
+				code := run(tt.args, strings.NewReader(tt.in), &stdout, &stderr)
+				assert.Equal(t, exitUsage, code)
+				assert.Empty(t, stdout.String())
+				assert.NotEmpty(t, stderr.String())
+			})
+		}
+	})
+}
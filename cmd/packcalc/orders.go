@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Input and output formats
+const (
+	formatCSV   = "csv"
+	formatJSONL = "jsonl"
+	formatTable = "table"
+)
+
+// order is a line of the input. Only the quantity is required.
+type order struct {
+	// Line is the line of the input the order was read from
+	Line          int        `json:"-"`
+	Reference     string     `json:"reference"`
+	ProductID     int        `json:"product_id"`
+	OrderQuantity int        `json:"order_quantity"`
+	AsOf          *time.Time `json:"as_of"`
+}
+
+// orderReader reads orders one at a time. An error on one line leaves the following ones readable;
+// Next returns io.EOF once the input is exhausted.
+type orderReader interface {
+	Next() (order, error)
+}
+
+func newOrderReader(in io.Reader, format string) (orderReader, error) {
+	switch format {
+	case "", formatCSV:
+		return newCSVOrderReader(in)
+	case formatJSONL, "json", "ndjson":
+		return &jsonlOrderReader{scanner: bufio.NewScanner(in)}, nil
+	default:
+		return nil, fmt.Errorf("unknown input format %q, expected %s or %s", format, formatCSV, formatJSONL)
+	}
+}
+
+// csvOrderReader reads CSV with a header naming the reference, product_id, order_quantity and as_of columns.
+// Other columns are ignored.
+type csvOrderReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+	// failed is set once the input cannot be read any further
+	failed bool
+}
+
+func newCSVOrderReader(in io.Reader) (*csvOrderReader, error) {
+	reader := csv.NewReader(in)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the CSV header: %w", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["order_quantity"]; !ok {
+		return nil, errors.New("the CSV header has no order_quantity column")
+	}
+	return &csvOrderReader{reader: reader, columns: columns}, nil
+}
+
+func (c *csvOrderReader) Next() (order, error) {
+	if c.failed {
+		return order{}, io.EOF
+	}
+	record, err := c.reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		switch {
+		case errors.Is(err, io.EOF):
+			return order{}, io.EOF
+		case errors.As(err, &parseErr):
+			return order{Line: parseErr.StartLine}, err
+		default:
+			c.failed = true
+			return order{}, err
+		}
+	}
+	line, _ := c.reader.FieldPos(0)
+
+	field := func(name string) string {
+		if i, ok := c.columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	o := order{Line: line, Reference: field("reference")}
+	if o.OrderQuantity, err = strconv.Atoi(field("order_quantity")); err != nil {
+		return o, fmt.Errorf("invalid order_quantity %q", field("order_quantity"))
+	}
+	if value := field("product_id"); value != "" {
+		if o.ProductID, err = strconv.Atoi(value); err != nil {
+			return o, fmt.Errorf("invalid product_id %q", value)
+		}
+	}
+	if value := field("as_of"); value != "" {
+		asOf, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return o, fmt.Errorf("invalid as_of %q", value)
+		}
+		o.AsOf = &asOf
+	}
+	return o, nil
+}
+
+// jsonlOrderReader reads one JSON object per line. Blank lines are skipped.
+type jsonlOrderReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (j *jsonlOrderReader) Next() (order, error) {
+	for j.scanner.Scan() {
+		j.line++
+		text := strings.TrimSpace(j.scanner.Text())
+		if text == "" {
+			continue
+		}
+		o := order{}
+		if err := json.Unmarshal([]byte(text), &o); err != nil {
+			return order{Line: j.line}, fmt.Errorf("invalid order: %w", err)
+		}
+		o.Line = j.line
+		return o, nil
+	}
+	if err := j.scanner.Err(); err != nil {
+		// The input cannot be read any further, so the error is reported on the next line
+		j.line++
+		line := j.line
+		j.scanner = bufio.NewScanner(strings.NewReader(""))
+		return order{Line: line}, err
+	}
+	return order{}, io.EOF
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"order-pack-calculator/internal/domain/dto"
+	"order-pack-calculator/internal/domain/services"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// result is the pack combination calculated for an order
+type result struct {
+	order
+	*dto.OptimalPackSizesResponse
+}
+
+// solve packs an order with the sizes its product has at the order's as_of instant, now by default
+func solve(ctx context.Context, sizes packSizeSource, o order) (*result, error) {
+	if o.OrderQuantity < 1 {
+		return nil, fmt.Errorf("order_quantity must be positive, got %d", o.OrderQuantity)
+	}
+	asOf := time.Now()
+	if o.AsOf != nil {
+		asOf = *o.AsOf
+	}
+
+	packSizes, err := sizes.GetSizesByProductID(ctx, int64(o.ProductID), asOf)
+	if err != nil {
+		return nil, err
+	}
+	if len(packSizes) == 0 {
+		return nil, fmt.Errorf("no pack sizes available for product_id=%d as of %s", o.ProductID, asOf.Format(time.RFC3339))
+	}
+
+	solution, err := services.SolvePacks(o.OrderQuantity, packSizes)
+	if err != nil {
+		return nil, err
+	}
+	return &result{order: o, OptimalPackSizesResponse: solution}, nil
+}
+
+// resultWriter writes results in one of the output formats
+type resultWriter interface {
+	Write(result) error
+	// Flush writes anything still buffered
+	Flush() error
+}
+
+func newResultWriter(out io.Writer, format string) (resultWriter, error) {
+	switch format {
+	case formatCSV:
+		writer := csv.NewWriter(out)
+		return &rowResultWriter{write: writer.Write, flush: func() error {
+			writer.Flush()
+			return writer.Error()
+		}}, nil
+	case formatJSONL:
+		return jsonlResultWriter{encoder: json.NewEncoder(out)}, nil
+	case formatTable:
+		writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		return &rowResultWriter{write: func(fields []string) error {
+			_, err := fmt.Fprintln(writer, strings.Join(fields, "\t"))
+			return err
+		}, flush: writer.Flush}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q, expected %s, %s or %s", format, formatCSV, formatJSONL, formatTable)
+	}
+}
+
+// rowResultWriter writes results as rows under a header, either CSV or an aligned table
+type rowResultWriter struct {
+	// write writes the fields of a row
+	write       func(fields []string) error
+	flush       func() error
+	wroteHeader bool
+}
+
+var resultColumns = []string{"line", "reference", "product_id", "order_quantity", "total_items", "total_packs", "packs"}
+
+func (c *rowResultWriter) Write(r result) error {
+	if !c.wroteHeader {
+		c.wroteHeader = true
+		if err := c.write(resultColumns); err != nil {
+			return err
+		}
+	}
+
+	// Packs are written as count x size, largest size first, e.g. 9429x53 7x31 2x23
+	packs := make([]string, 0, len(r.PackCombination))
+	for _, pack := range r.PackCombination {
+		packs = append(packs, fmt.Sprintf("%dx%d", pack.Count, pack.Size))
+	}
+	return c.write([]string{
+		strconv.Itoa(r.Line),
+		r.Reference,
+		strconv.Itoa(r.ProductID),
+		strconv.Itoa(r.OrderQuantity),
+		strconv.Itoa(r.TotalItems),
+		strconv.Itoa(r.TotalPacks),
+		strings.Join(packs, " "),
+	})
+}
+
+func (c *rowResultWriter) Flush() error {
+	return c.flush()
+}
+
+// jsonlResultWriter writes one JSON object per result
+type jsonlResultWriter struct {
+	encoder *json.Encoder
+}
+
+func (j jsonlResultWriter) Write(r result) error {
+	return j.encoder.Encode(struct {
+		Line            int              `json:"line"`
+		Reference       string           `json:"reference,omitempty"`
+		ProductID       int              `json:"product_id"`
+		OrderQuantity   int              `json:"order_quantity"`
+		PackCombination []dto.PackDetail `json:"pack_combination"`
+		TotalItems      int              `json:"total_items"`
+		TotalPacks      int              `json:"total_packs"`
+	}{r.Line, r.Reference, r.ProductID, r.OrderQuantity, r.PackCombination, r.TotalItems, r.TotalPacks})
+}
+
+func (j jsonlResultWriter) Flush() error {
+	return nil
+}
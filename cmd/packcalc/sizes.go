@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"order-pack-calculator/internal/app"
+	"order-pack-calculator/internal/domain/repositories"
+	"order-pack-calculator/internal/domain/repositories/memory"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// packSizeSource returns the sizes the orders of a product are packed with.
+// Every PackSizeRepository is one.
+type packSizeSource interface {
+	GetSizesByProductID(ctx context.Context, productID int64, asOf time.Time) ([]int, error)
+}
+
+// fixedSizes packs the orders of every product with the same sizes
+type fixedSizes []int
+
+func (f fixedSizes) GetSizesByProductID(ctx context.Context, productID int64, asOf time.Time) ([]int, error) {
+	return f, nil
+}
+
+// newPackSizeSource returns the source selected by the flags, exactly one of which must be set
+func newPackSizeSource(ctx context.Context, packs, packsFile string, fromDB bool) (packSizeSource, error) {
+	selected := 0
+	for _, set := range []bool{packs != "", packsFile != "", fromDB} {
+		if set {
+			selected++
+		}
+	}
+	if selected != 1 {
+		return nil, errors.New("set exactly one of -packs, -packs-file or -db")
+	}
+
+	switch {
+	case packs != "":
+		var sizes fixedSizes
+		for _, field := range strings.Split(packs, ",") {
+			size, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil || size < 1 {
+				return nil, fmt.Errorf("invalid pack size %q", field)
+			}
+			sizes = append(sizes, size)
+		}
+		return sizes, nil
+	case packsFile != "":
+		repository := memory.NewPackSizeRepository()
+		if err := repositories.LoadSeed(ctx, packsFile, repository); err != nil {
+			return nil, err
+		}
+		return repository, nil
+	default:
+		a, err := app.New(app.ConfigFromEnv())
+		if err != nil {
+			return nil, err
+		}
+		if err := a.CheckSchema(ctx); err != nil {
+			return nil, err
+		}
+		return a.PackSizeRepository, nil
+	}
+}
@@ -0,0 +1,262 @@
+// Package app loads the configuration of the service and wires its storage and services.
+// Every command of the binary starts from an App, so they all share the same setup.
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"order-pack-calculator/internal/auth"
+	"order-pack-calculator/internal/database/migrate"
+	"order-pack-calculator/internal/domain/repositories"
+	"order-pack-calculator/internal/domain/services"
+	"order-pack-calculator/internal/health"
+	"order-pack-calculator/internal/ratelimit"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/joho/godotenv/autoload"
+)
+
+// Default time a response is kept for replay under an idempotency key
+const defaultIdempotencyTTL = 24 * time.Hour
+
+// Default size limit of request bodies, far above what the API's requests need
+const defaultMaxRequestBodyBytes = 1 << 20
+
+// Default time the server keeps serving while failing readiness once asked to stop, enough for load balancers
+// probing every few seconds to take it out of rotation
+const defaultShutdownDelay = 5 * time.Second
+
+// Default largest order quantity, far above real orders and small enough to be solved in milliseconds
+const defaultMaxOrderQuantity = 1_000_000_000
+
+// Config is the configuration of the service, read from the environment and the .env file
+type Config struct {
+	// Port the HTTP server listens on
+	Port    int
+	Storage StorageConfig
+	// MigrateOnStart applies the pending migrations before serving
+	MigrateOnStart bool
+	// IdempotencyTTL is how long a response is kept for replay under an idempotency key
+	IdempotencyTTL time.Duration
+	// Limits bounds the order quantities and pack sizes the services accept
+	Limits services.Limits
+	// MaxRequestBodyBytes is the largest request body the server accepts
+	MaxRequestBodyBytes int64
+	// Auth configures how API clients authenticate
+	Auth auth.Config
+	// RateLimitFile is the rate limit policy, reloaded when it changes. Clients are not limited without one.
+	RateLimitFile string
+	// TrustedProxies are the addresses or CIDRs of the proxies whose X-Forwarded-For header gives the client address.
+	// The address of the peer is used when empty.
+	TrustedProxies []string
+	// ShutdownDelay is how long the server keeps serving once asked to stop while reporting itself not ready,
+	// so load balancers take it out of rotation before it stops accepting connections
+	ShutdownDelay time.Duration
+}
+
+// ConfigFromEnv reads the configuration from the environment
+func ConfigFromEnv() Config {
+	port, _ := strconv.Atoi(os.Getenv("PORT"))
+	migrateOnStart, _ := strconv.ParseBool(os.Getenv("MIGRATE_ON_START"))
+	idempotencyTTL, err := time.ParseDuration(os.Getenv("IDEMPOTENCY_TTL"))
+	if err != nil {
+		idempotencyTTL = defaultIdempotencyTTL
+	}
+	maxRequestBodyBytes := int64(positiveIntFromEnv("MAX_REQUEST_BODY_BYTES"))
+	if maxRequestBodyBytes == 0 {
+		maxRequestBodyBytes = defaultMaxRequestBodyBytes
+	}
+	return Config{
+		Port: port,
+		Storage: StorageConfig{
+			Kind:       os.Getenv("STORAGE"),
+			SQLitePath: os.Getenv("SQLITE_PATH"),
+			SeedPath:   os.Getenv("STORAGE_SEED"),
+		},
+		MigrateOnStart:      migrateOnStart,
+		IdempotencyTTL:      idempotencyTTL,
+		Limits:              limitsFromEnv(),
+		MaxRequestBodyBytes: maxRequestBodyBytes,
+		Auth:                authFromEnv(),
+		RateLimitFile:       os.Getenv("RATE_LIMIT_FILE"),
+		TrustedProxies:      listFromEnv("TRUSTED_PROXIES"),
+		ShutdownDelay:       shutdownDelayFromEnv(),
+	}
+}
+
+// shutdownDelayFromEnv reads SHUTDOWN_DELAY, defaultShutdownDelay when unset or invalid. 0s stops right away.
+func shutdownDelayFromEnv() time.Duration {
+	value := os.Getenv("SHUTDOWN_DELAY")
+	if value == "" {
+		return defaultShutdownDelay
+	}
+	delay, err := time.ParseDuration(value)
+	if err != nil || delay < 0 {
+		slog.Warn("ignoring invalid SHUTDOWN_DELAY", "value", value, "default", defaultShutdownDelay.String())
+		return defaultShutdownDelay
+	}
+	return delay
+}
+
+// authFromEnv reads the authentication settings from the environment
+func authFromEnv() auth.Config {
+	disabled, _ := strconv.ParseBool(os.Getenv("AUTH_DISABLED"))
+	return auth.Config{
+		Disabled: disabled,
+		JWT: auth.JWTConfig{
+			Secret:   os.Getenv("AUTH_JWT_SECRET"),
+			JWKSPath: os.Getenv("AUTH_JWKS_FILE"),
+			Issuer:   os.Getenv("AUTH_JWT_ISSUER"),
+			Audience: os.Getenv("AUTH_JWT_AUDIENCE"),
+		},
+	}
+}
+
+// limitsFromEnv reads the limits from the environment. Invalid values are logged and not enforced,
+// except for the order quantity which falls back to defaultMaxOrderQuantity.
+func limitsFromEnv() services.Limits {
+	limits := services.Limits{
+		MaxOrderQuantity: positiveIntFromEnv("MAX_ORDER_QUANTITY"),
+		MaxPackSize:      positiveIntFromEnv("MAX_PACK_SIZE"),
+	}
+	if limits.MaxOrderQuantity == 0 {
+		limits.MaxOrderQuantity = defaultMaxOrderQuantity
+	}
+	if value := os.Getenv("PRODUCT_MAX_PACK_SIZES"); value != "" {
+		productLimits, err := parseProductLimits(value)
+		if err != nil {
+			slog.Warn("ignoring invalid PRODUCT_MAX_PACK_SIZES", "error", err)
+		}
+		limits.ProductMaxPackSizes = productLimits
+	}
+	if value := os.Getenv("PRODUCT_MAX_ORDER_QUANTITIES"); value != "" {
+		productLimits, err := parseProductLimits(value)
+		if err != nil {
+			slog.Warn("ignoring invalid PRODUCT_MAX_ORDER_QUANTITIES", "error", err)
+		}
+		limits.ProductMaxOrderQuantities = productLimits
+	}
+	return limits
+}
+
+// positiveIntFromEnv reads a positive integer from the environment, 0 when it is unset or invalid
+func positiveIntFromEnv(key string) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		slog.Warn("ignoring invalid "+key, "value", value)
+		return 0
+	}
+	return n
+}
+
+// listFromEnv reads a comma separated list from the environment, nil when it is unset
+func listFromEnv(key string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// parseProductLimits parses limits given per product as a comma separated list of product_id:limit pairs, e.g. 1:1000,2:500
+func parseProductLimits(value string) (map[int]int, error) {
+	limits := map[int]int{}
+	for _, pair := range strings.Split(value, ",") {
+		productID, limit, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok {
+			return nil, fmt.Errorf("expected product_id:limit, got %q", pair)
+		}
+		id, err := strconv.Atoi(productID)
+		if err != nil || id < 1 {
+			return nil, fmt.Errorf("invalid product id in %q", pair)
+		}
+		max, err := strconv.Atoi(limit)
+		if err != nil || max < 1 {
+			return nil, fmt.Errorf("invalid limit in %q", pair)
+		}
+		limits[id] = max
+	}
+	return limits, nil
+}
+
+// App holds the services of the configured storage backend
+type App struct {
+	Config             Config
+	PackSizeService    services.PackSizeService
+	OrderService       services.OrderService
+	IdempotencyService services.IdempotencyService
+	APIKeyService      services.APIKeyService
+	// Authenticator checks the credentials of API requests, nil when authentication is disabled
+	Authenticator *auth.Authenticator
+	// RateLimiter limits the requests and compute of each client, nil without a rate limit file
+	RateLimiter *ratelimit.Limiter
+	// PackSizeRepository is exposed for loading seed files
+	PackSizeRepository repositories.PackSizeRepository
+	// Migrator manages the schema of SQL backends, nil for the others
+	Migrator *migrate.Migrator
+	// Health checks the dependencies the readiness of the service depends on
+	Health *health.Registry
+}
+
+// New opens the configured storage backend and builds the services on top of it
+func New(config Config) (*App, error) {
+	storage, err := newStorage(config.Storage)
+	if err != nil {
+		return nil, err
+	}
+	var authenticator *auth.Authenticator
+	if !config.Auth.Disabled {
+		authenticator, err = auth.NewAuthenticator(config.Auth, storage.apiKeyRepository)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure authentication: %w", err)
+		}
+	}
+	var rateLimiter *ratelimit.Limiter
+	if config.RateLimitFile != "" {
+		policy, err := ratelimit.NewPolicy(config.RateLimitFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure rate limits: %w", err)
+		}
+		rateLimiter = ratelimit.NewLimiter(policy)
+	}
+	return &App{
+		Config:             config,
+		PackSizeService:    services.NewPackSizeService(storage.packSizeRepository, storage.orderRepository, storage.unitOfWork, config.Limits),
+		OrderService:       services.NewOrderService(storage.orderRepository, storage.packSizeRepository, config.Limits),
+		IdempotencyService: services.NewIdempotencyService(storage.idempotencyKeyRepository, config.IdempotencyTTL),
+		APIKeyService:      services.NewAPIKeyService(storage.apiKeyRepository),
+		Authenticator:      authenticator,
+		RateLimiter:        rateLimiter,
+		PackSizeRepository: storage.packSizeRepository,
+		Migrator:           storage.migrator,
+		Health:             newHealth(storage),
+	}, nil
+}
+
+// CheckSchema fails when the schema of a SQL backend is dirty or behind the latest migration.
+// With MigrateOnStart set, the pending migrations are applied first.
+func (a *App) CheckSchema(ctx context.Context) error {
+	if a.Migrator == nil {
+		return nil
+	}
+	if a.Config.MigrateOnStart {
+		applied, err := a.Migrator.Up(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to migrate the database: %w", err)
+		}
+		for _, migration := range applied {
+			slog.InfoContext(ctx, "applied migration", "version", migration.Version, "name", migration.Name)
+		}
+	}
+	return a.Migrator.Check(ctx)
+}
@@ -0,0 +1,166 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"order-pack-calculator/internal/auth"
+	"order-pack-calculator/internal/database/migrate"
+	"order-pack-calculator/internal/domain/dto"
+	errs "order-pack-calculator/internal/domain/errors"
+	"order-pack-calculator/internal/domain/services"
+	"order-pack-calculator/internal/health"
+	"order-pack-calculator/mocks"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("memory", func(t *testing.T) {
+		app, err := New(Config{Storage: StorageConfig{Kind: storageMemory, SeedPath: "../../seeds/pack_sizes.yaml"}})
+		assert.NoError(t, err)
+		report := app.Health.Run(ctx)
+		assert.Equal(t, health.StatusUp, report.Status)
+		assert.ElementsMatch(t, []string{"cache"}, slices.Collect(maps.Keys(report.Checks)), "data in memory is on no disk")
+		assert.Nil(t, app.Migrator)
+		assert.NoError(t, app.CheckSchema(ctx))
+
+		packSizes, err := app.PackSizeService.GetAll(ctx, dto.ListPackSizesRequest{})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), packSizes.Total)
+	})
+
+	t.Run("sqlite", func(t *testing.T) {
+		config := Config{Storage: StorageConfig{Kind: storageSQLite, SQLitePath: filepath.Join(t.TempDir(), "test.db")}}
+		app, err := New(config)
+		assert.NoError(t, err)
+		assert.ErrorIs(t, app.CheckSchema(ctx), migrate.ErrSchemaMismatch)
+
+		// The service is not ready until the migrations are applied
+		report := app.Health.Run(ctx)
+		assert.Equal(t, health.StatusDown, report.Status)
+		assert.Equal(t, health.StatusUp, report.Checks["database"].Status)
+		assert.Equal(t, health.StatusDown, report.Checks["migrations"].Status)
+
+		app.Config.MigrateOnStart = true
+		assert.NoError(t, app.CheckSchema(ctx))
+
+		report = app.Health.Run(ctx)
+		assert.Equal(t, health.StatusUp, report.Status)
+		assert.ElementsMatch(t, []string{"database", "migrations", "cache", "disk"}, slices.Collect(maps.Keys(report.Checks)))
+		assert.Equal(t, filepath.Dir(config.Storage.SQLitePath), report.Checks["disk"].Details["path"])
+	})
+
+	t.Run("authentication", func(t *testing.T) {
+		app, err := New(Config{Storage: StorageConfig{Kind: storageMemory}})
+		assert.NoError(t, err)
+		assert.NotNil(t, app.Authenticator)
+
+		app, err = New(Config{Storage: StorageConfig{Kind: storageMemory}, Auth: auth.Config{Disabled: true}})
+		assert.NoError(t, err)
+		assert.Nil(t, app.Authenticator)
+
+		_, err = New(Config{Storage: StorageConfig{Kind: storageMemory}, Auth: auth.Config{JWT: auth.JWTConfig{JWKSPath: "missing.json"}}})
+		assert.Error(t, err)
+	})
+
+	t.Run("rate limits", func(t *testing.T) {
+		app, err := New(Config{Storage: StorageConfig{Kind: storageMemory}})
+		assert.NoError(t, err)
+		assert.Nil(t, app.RateLimiter)
+
+		app, err = New(Config{Storage: StorageConfig{Kind: storageMemory}, RateLimitFile: "../../ratelimits.yaml"})
+		assert.NoError(t, err)
+		assert.NotNil(t, app.RateLimiter)
+
+		_, err = New(Config{Storage: StorageConfig{Kind: storageMemory}, RateLimitFile: "missing.yaml"})
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown storage", func(t *testing.T) {
+		_, err := New(Config{Storage: StorageConfig{Kind: "cassandra"}})
+		assert.Error(t, err)
+	})
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("PORT", "9090")
+	t.Setenv("STORAGE", "sqlite")
+	t.Setenv("SQLITE_PATH", "test.db")
+	t.Setenv("STORAGE_SEED", "")
+	t.Setenv("MIGRATE_ON_START", "true")
+	t.Setenv("IDEMPOTENCY_TTL", "invalid")
+	t.Setenv("MAX_ORDER_QUANTITY", "1000000")
+	t.Setenv("MAX_PACK_SIZE", "-5")
+	t.Setenv("PRODUCT_MAX_PACK_SIZES", "1:500, 2:100")
+	t.Setenv("PRODUCT_MAX_ORDER_QUANTITIES", "1:5000")
+	t.Setenv("MAX_REQUEST_BODY_BYTES", "")
+	t.Setenv("AUTH_DISABLED", "")
+	t.Setenv("AUTH_JWT_SECRET", "secret")
+	t.Setenv("AUTH_JWKS_FILE", "")
+	t.Setenv("AUTH_JWT_ISSUER", "https://issuer.example")
+	t.Setenv("AUTH_JWT_AUDIENCE", "")
+	t.Setenv("RATE_LIMIT_FILE", "ratelimits.yaml")
+	t.Setenv("SHUTDOWN_DELAY", "15s")
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.1, 192.168.0.0/16")
+
+	config := ConfigFromEnv()
+	assert.Equal(t, 9090, config.Port)
+	assert.Equal(t, StorageConfig{Kind: "sqlite", SQLitePath: "test.db"}, config.Storage)
+	assert.True(t, config.MigrateOnStart)
+	assert.Equal(t, defaultIdempotencyTTL, config.IdempotencyTTL)
+	assert.Equal(t, services.Limits{MaxOrderQuantity: 1000000, ProductMaxOrderQuantities: map[int]int{1: 5000}, ProductMaxPackSizes: map[int]int{1: 500, 2: 100}}, config.Limits)
+	assert.Equal(t, int64(defaultMaxRequestBodyBytes), config.MaxRequestBodyBytes)
+	assert.Equal(t, auth.Config{JWT: auth.JWTConfig{Secret: "secret", Issuer: "https://issuer.example"}}, config.Auth)
+	assert.Equal(t, "ratelimits.yaml", config.RateLimitFile)
+	assert.Equal(t, 15*time.Second, config.ShutdownDelay)
+	assert.Equal(t, []string{"10.0.0.1", "192.168.0.0/16"}, config.TrustedProxies)
+}
+
+func TestShutdownDelayFromEnv(t *testing.T) {
+	for value, want := range map[string]time.Duration{"": defaultShutdownDelay, "invalid": defaultShutdownDelay, "-1s": defaultShutdownDelay, "0s": 0, "30s": 30 * time.Second} {
+		t.Setenv("SHUTDOWN_DELAY", value)
+		assert.Equal(t, want, shutdownDelayFromEnv(), value)
+	}
+}
+
+func TestDefaultMaxOrderQuantity(t *testing.T) {
+	t.Setenv("MAX_ORDER_QUANTITY", "")
+	assert.Equal(t, defaultMaxOrderQuantity, limitsFromEnv().MaxOrderQuantity)
+
+	t.Setenv("MAX_ORDER_QUANTITY", "invalid")
+	assert.Equal(t, defaultMaxOrderQuantity, limitsFromEnv().MaxOrderQuantity)
+}
+
+func TestParseProductLimits(t *testing.T) {
+	limits, err := parseProductLimits("1:500,3:20")
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]int{1: 500, 3: 20}, limits)
+
+	for _, value := range []string{"1", "a:5", "1:0", "0:5", "1:5,"} {
+		_, err := parseProductLimits(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestIdempotencyCheck(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repository := mocks.NewMockIdempotencyKeyRepository(ctrl)
+	ctx := context.Background()
+
+	repository.EXPECT().GetByKey(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errs.ErrNotFound)
+	assert.Equal(t, health.StatusUp, idempotencyCheck(repository)(ctx).Status)
+
+	repository.EXPECT().GetByKey(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("connection refused"))
+	result := idempotencyCheck(repository)(ctx)
+	assert.Equal(t, health.StatusDown, result.Status)
+	assert.Equal(t, "failed to read the idempotency keys", result.Message, "the message does not reveal the error")
+	assert.EqualError(t, result.Err, "connection refused")
+}
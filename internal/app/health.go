@@ -0,0 +1,68 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"order-pack-calculator/internal/database/migrate"
+	errs "order-pack-calculator/internal/domain/errors"
+	"order-pack-calculator/internal/domain/repositories"
+	"order-pack-calculator/internal/health"
+)
+
+// The disk holding the data of the service is degraded below 10% of free space, and down below 2%
+const (
+	diskDegradedBelow = 0.10
+	diskDownBelow     = 0.02
+)
+
+// newHealth registers the checks the readiness of the service depends on: the database of SQL backends
+// and its schema, the idempotency store replaying responses and, for backends storing their data locally,
+// the disk holding it. The disk of a remote database is not the service's to check.
+func newHealth(storage *storage) *health.Registry {
+	registry := health.NewRegistry(health.DefaultTimeout)
+	if storage.health != nil {
+		registry.Register("database", storage.health)
+	}
+	if storage.migrator != nil {
+		registry.Register("migrations", migrationsCheck(storage.migrator))
+	}
+	registry.Register("cache", idempotencyCheck(storage.idempotencyKeyRepository))
+	if storage.dataDir != "" {
+		registry.Register("disk", health.Disk(storage.dataDir, diskDegradedBelow, diskDownBelow))
+	}
+	return registry
+}
+
+// migrationsCheck is down while the schema is dirty or behind the migrations, and degraded when the
+// schema is ahead of them, as while a newer release is being rolled out
+func migrationsCheck(migrator *migrate.Migrator) health.Check {
+	return func(ctx context.Context) health.Result {
+		status, err := migrator.Status(ctx)
+		if err != nil {
+			return health.Failed("failed to read the schema version", err)
+		}
+		details := map[string]any{"version": status.Version, "latest": status.Latest}
+		switch {
+		case status.Dirty:
+			return health.Down("schema is dirty", details)
+		case status.Version < status.Latest:
+			return health.Down("migrations are pending", details)
+		case status.Version > status.Latest:
+			return health.Degraded("schema is ahead of the migrations", details)
+		default:
+			return health.Up(details)
+		}
+	}
+}
+
+// idempotencyCheck is down when the idempotency store cannot be read. It looks a key up under the
+// readiness route, which never stores responses as only POST and PATCH routes are idempotent.
+func idempotencyCheck(repository repositories.IdempotencyKeyRepository) health.Check {
+	return func(ctx context.Context) health.Result {
+		_, err := repository.GetByKey(ctx, "health-check", "GET /api/health/ready")
+		if err != nil && !errors.Is(err, errs.ErrNotFound) {
+			return health.Failed("failed to read the idempotency keys", err)
+		}
+		return health.Up(nil)
+	}
+}
@@ -0,0 +1,112 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"order-pack-calculator/internal/database"
+	"order-pack-calculator/internal/database/migrate"
+	"order-pack-calculator/internal/domain/repositories"
+	"order-pack-calculator/internal/domain/repositories/memory"
+	"order-pack-calculator/internal/health"
+	"order-pack-calculator/internal/metrics"
+	"order-pack-calculator/migrations"
+	"path/filepath"
+)
+
+// Storage backends selectable with the STORAGE environment variable
+const (
+	storagePostgres = "postgres"
+	storageSQLite   = "sqlite"
+	storageMemory   = "memory"
+)
+
+// storage holds the repositories of the selected backend
+type storage struct {
+	packSizeRepository       repositories.PackSizeRepository
+	orderRepository          repositories.OrderRepository
+	idempotencyKeyRepository repositories.IdempotencyKeyRepository
+	apiKeyRepository         repositories.APIKeyRepository
+	unitOfWork               repositories.UnitOfWork
+	// migrator manages the schema of SQL backends, nil for the others
+	migrator *migrate.Migrator
+	// health checks the database of SQL backends, nil for the others
+	health health.Check
+	// dataDir is the directory a local backend stores its data in, whose disk is checked.
+	// It is empty for remote and in-memory backends.
+	dataDir string
+}
+
+// StorageConfig selects and configures the storage backend
+type StorageConfig struct {
+	// Kind is one of postgres, sqlite or memory. Postgres is used when empty.
+	Kind string
+	// SQLitePath is the database file of the sqlite backend
+	SQLitePath string
+	// SeedPath is a file the memory backend is loaded from, if set
+	SeedPath string
+}
+
+// Database file used by the sqlite backend when none is configured
+const defaultSQLitePath = "order-pack-calculator.db"
+
+// newStorage builds the repositories of the configured backend
+func newStorage(config StorageConfig) (*storage, error) {
+	switch config.Kind {
+	case "", storagePostgres:
+		dbService, err := database.New()
+		if err != nil {
+			return nil, err
+		}
+		return newSQLStorage(storagePostgres, repositories.DialectPostgres, dbService, migrations.Postgres(), "")
+	case storageSQLite:
+		path := config.SQLitePath
+		if path == "" {
+			path = defaultSQLitePath
+		}
+		dbService, err := database.NewSQLite(path)
+		if err != nil {
+			return nil, err
+		}
+		return newSQLStorage(storageSQLite, repositories.DialectSQLite, dbService, migrations.SQLite(), filepath.Dir(path))
+	case storageMemory:
+		packSizeRepository := memory.NewPackSizeRepository()
+		if config.SeedPath != "" {
+			if err := repositories.LoadSeed(context.Background(), config.SeedPath, packSizeRepository); err != nil {
+				return nil, err
+			}
+		}
+		orderRepository := memory.NewOrderRepository()
+		idempotencyKeyRepository := memory.NewIdempotencyKeyRepository()
+		apiKeyRepository := memory.NewAPIKeyRepository()
+		return &storage{
+			packSizeRepository:       packSizeRepository,
+			orderRepository:          orderRepository,
+			idempotencyKeyRepository: idempotencyKeyRepository,
+			apiKeyRepository:         apiKeyRepository,
+			unitOfWork:               memory.NewUnitOfWork(packSizeRepository, orderRepository, idempotencyKeyRepository, apiKeyRepository),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown storage %q, expected %s, %s or %s", config.Kind, storagePostgres, storageSQLite, storageMemory)
+	}
+}
+
+// newSQLStorage builds the repositories backed by a SQL database of the dialect, whose schema is defined by the
+// given migrations and whose data is stored in dataDir. The statistics of its connection pool are exported as metrics.
+func newSQLStorage(kind string, dialect repositories.Dialect, dbService database.Service, schema fs.FS, dataDir string) (*storage, error) {
+	migrator, err := migrate.New(dbService.GetDB(), schema)
+	if err != nil {
+		return nil, err
+	}
+	metrics.ObserveDB(kind, dbService.GetDB())
+	return &storage{
+		packSizeRepository:       repositories.NewPackSizeRepository(dbService.GetDB(), dialect),
+		orderRepository:          repositories.NewOrderRepository(dbService.GetDB(), dialect),
+		idempotencyKeyRepository: repositories.NewIdempotencyKeyRepository(dbService.GetDB(), dialect),
+		apiKeyRepository:         repositories.NewAPIKeyRepository(dbService.GetDB(), dialect),
+		unitOfWork:               repositories.NewUnitOfWork(dbService.GetDB(), dialect),
+		migrator:                 migrator,
+		health:                   dbService.Health,
+		dataDir:                  dataDir,
+	}, nil
+}
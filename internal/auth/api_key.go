@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	// APIKeyPrefix starts every API key, telling them apart from JWTs in the Authorization header
+	APIKeyPrefix = "opc_"
+
+	apiKeyBytes = 32
+	// Characters of a key kept as its displayed prefix, including APIKeyPrefix
+	displayedPrefixLength = len(APIKeyPrefix) + 8
+)
+
+// NewAPIKey generates a random API key. It returns the key, to be handed to the client once,
+// the prefix displayed to tell keys apart and the hash stored in its place.
+func NewAPIKey() (key, prefix, hash string, err error) {
+	b := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", "", err
+	}
+	key = APIKeyPrefix + hex.EncodeToString(b)
+	return key, key[:displayedPrefixLength], HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the hash an API key is stored and looked up by.
+// Keys are random and long, so a fast unsalted hash is enough.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsAPIKey reports whether a credential looks like an API key rather than a JWT
+func IsAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, APIKeyPrefix)
+}
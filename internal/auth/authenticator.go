@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	errs "order-pack-calculator/internal/domain/errors"
+	"order-pack-calculator/internal/domain/repositories"
+	"strconv"
+	"strings"
+)
+
+// Config configures the authentication of the API
+type Config struct {
+	// Disabled lets every request through without credentials
+	Disabled bool
+	JWT      JWTConfig
+}
+
+// Authenticator resolves the credential of a request to the principal it belongs to
+type Authenticator struct {
+	apiKeys repositories.APIKeyRepository
+	// jwt is nil when bearer tokens are not accepted
+	jwt *JWTVerifier
+}
+
+// NewAuthenticator returns an authenticator accepting the API keys of the repository,
+// and bearer tokens when a secret or a key set is configured.
+func NewAuthenticator(config Config, apiKeys repositories.APIKeyRepository) (*Authenticator, error) {
+	authenticator := &Authenticator{apiKeys: apiKeys}
+	if config.JWT.Enabled() {
+		verifier, err := NewJWTVerifier(config.JWT)
+		if err != nil {
+			return nil, err
+		}
+		authenticator.jwt = verifier
+	}
+	return authenticator, nil
+}
+
+// Authenticate returns the principal of an API key or a JWT. Failures match ErrUnauthenticated,
+// unless the key store itself fails.
+func (a *Authenticator) Authenticate(ctx context.Context, credential string) (Principal, error) {
+	if credential == "" {
+		return Principal{}, fmt.Errorf("%w: missing credentials", errs.ErrUnauthenticated)
+	}
+	if IsAPIKey(credential) {
+		return a.authenticateAPIKey(ctx, credential)
+	}
+	return a.authenticateToken(credential)
+}
+
+func (a *Authenticator) authenticateAPIKey(ctx context.Context, key string) (Principal, error) {
+	stored, err := a.apiKeys.GetByHash(ctx, HashAPIKey(key))
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			return Principal{}, fmt.Errorf("%w: unknown api key", errs.ErrUnauthenticated)
+		}
+		return Principal{}, fmt.Errorf("could not look up api key. %w", err)
+	}
+	if stored.RevokedAt != nil {
+		return Principal{}, fmt.Errorf("%w: api key %s was revoked", errs.ErrUnauthenticated, stored.Prefix)
+	}
+	return Principal{
+		Subject:    "apikey:" + strconv.FormatInt(stored.ID, 10),
+		Method:     MethodAPIKey,
+		Name:       stored.Name,
+		Roles:      stored.Roles,
+		ProductIDs: stored.ProductIDs,
+	}, nil
+}
+
+func (a *Authenticator) authenticateToken(token string) (Principal, error) {
+	if a.jwt == nil {
+		return Principal{}, fmt.Errorf("%w: bearer tokens are not accepted", errs.ErrUnauthenticated)
+	}
+	claims, err := a.jwt.Verify(token)
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: %w", errs.ErrUnauthenticated, err)
+	}
+	return Principal{
+		Subject:    claims.Subject,
+		Method:     MethodJWT,
+		Name:       claims.Name,
+		Roles:      claims.Roles,
+		Scopes:     strings.Fields(claims.Scope),
+		ProductIDs: claims.ProductIDs,
+	}, nil
+}
@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"order-pack-calculator/internal/domain/entities"
+	errs "order-pack-calculator/internal/domain/errors"
+	"order-pack-calculator/internal/domain/repositories/memory"
+	"order-pack-calculator/mocks"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthenticator(t *testing.T) {
+	ctx := context.Background()
+	keys := memory.NewAPIKeyRepository()
+
+	key, prefix, hash, err := NewAPIKey()
+	assert.NoError(t, err)
+	assert.True(t, IsAPIKey(key))
+	assert.Equal(t, key[:len(prefix)], prefix)
+	assert.Equal(t, HashAPIKey(key), hash)
+	stored, _ := keys.Create(ctx, entities.APIKey{Name: "ci", Prefix: prefix, Hash: hash, Roles: []string{RoleCalculator}, ProductIDs: []int{1}, CreatedAt: time.Now()})
+
+	authenticator, err := NewAuthenticator(Config{JWT: JWTConfig{Secret: "secret"}}, keys)
+	assert.NoError(t, err)
+
+	t.Run("api key", func(t *testing.T) {
+		principal, err := authenticator.Authenticate(ctx, key)
+		assert.NoError(t, err)
+		assert.Equal(t, Principal{Subject: "apikey:1", Method: MethodAPIKey, Name: "ci", Roles: []string{RoleCalculator}, ProductIDs: []int{1}}, principal)
+	})
+
+	t.Run("jwt", func(t *testing.T) {
+		token := signToken(t, "HS256", "", map[string]any{
+			"sub":         "user-1",
+			"exp":         time.Now().Add(time.Hour).Unix(),
+			"roles":       []string{RoleAdmin},
+			"scope":       "orders:read orders:write",
+			"product_ids": []int{2, 3},
+		}, []byte("secret"))
+		principal, err := authenticator.Authenticate(ctx, token)
+		assert.NoError(t, err)
+		assert.Equal(t, Principal{
+			Subject:    "user-1",
+			Method:     MethodJWT,
+			Roles:      []string{RoleAdmin},
+			Scopes:     []string{"orders:read", "orders:write"},
+			ProductIDs: []int{2, 3},
+		}, principal)
+	})
+
+	t.Run("rejected credentials", func(t *testing.T) {
+		for name, credential := range map[string]string{
+			"missing":     "",
+			"unknown key": APIKeyPrefix + "unknown",
+			"bad token":   "a.b.c",
+		} {
+			_, err := authenticator.Authenticate(ctx, credential)
+			assert.ErrorIs(t, err, errs.ErrUnauthenticated, name)
+		}
+	})
+
+	t.Run("revoked key", func(t *testing.T) {
+		assert.NoError(t, keys.Revoke(ctx, stored.ID, time.Now()))
+		_, err := authenticator.Authenticate(ctx, key)
+		assert.ErrorIs(t, err, errs.ErrUnauthenticated)
+	})
+
+	t.Run("tokens not accepted", func(t *testing.T) {
+		authenticator, err := NewAuthenticator(Config{}, keys)
+		assert.NoError(t, err)
+		_, err = authenticator.Authenticate(ctx, "a.b.c")
+		assert.ErrorIs(t, err, errs.ErrUnauthenticated)
+	})
+
+	t.Run("key store failure", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockAPIKeyRepository(ctrl)
+		repo.EXPECT().GetByHash(gomock.Any(), HashAPIKey(key)).Return(nil, errors.New("connection refused"))
+
+		authenticator, _ := NewAuthenticator(Config{}, repo)
+		_, err := authenticator.Authenticate(ctx, key)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, errs.ErrUnauthenticated)
+	})
+}
+
+func TestPrincipalContext(t *testing.T) {
+	_, ok := PrincipalFrom(context.Background())
+	assert.False(t, ok)
+
+	principal := Principal{Subject: "user-1", Method: MethodJWT}
+	res, ok := PrincipalFrom(WithPrincipal(context.Background(), principal))
+	assert.True(t, ok)
+	assert.Equal(t, principal, res)
+}
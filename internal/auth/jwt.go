@@ -0,0 +1,350 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrInvalidToken is returned for tokens that are malformed, badly signed, expired or not meant for this service
+var ErrInvalidToken = errors.New("invalid token")
+
+// Clock skew tolerated when checking the validity window of a token
+const jwtLeeway = time.Minute
+
+// JWTConfig configures how bearer tokens are verified. Tokens signed with HMAC are verified with the secret,
+// tokens signed with RSA or ECDSA with the key set whose key id matches the kid header of the token.
+type JWTConfig struct {
+	// Secret verifies tokens signed with HS256, HS384 or HS512
+	Secret string
+	// JWKSPath is a JSON Web Key Set file whose keys verify tokens signed with RS256, RS384, RS512, ES256, ES384 or ES512
+	JWKSPath string
+	// Issuer is the iss claim tokens must have, if set
+	Issuer string
+	// Audience must be one of the aud claims of tokens, if set
+	Audience string
+}
+
+// Enabled reports whether tokens can be verified at all
+func (c JWTConfig) Enabled() bool {
+	return c.Secret != "" || c.JWKSPath != ""
+}
+
+// Claims are the claims of a verified token the service relies on
+type Claims struct {
+	Subject   string   `json:"sub"`
+	Name      string   `json:"name"`
+	Issuer    string   `json:"iss"`
+	Audience  audience `json:"aud"`
+	ExpiresAt *float64 `json:"exp"`
+	NotBefore *float64 `json:"nbf"`
+	// Roles and Scope, a space separated list of permissions, grant the permissions of the token
+	Roles []string `json:"roles"`
+	Scope string   `json:"scope"`
+	// ProductIDs restricts the token to these products, when not empty
+	ProductIDs []int `json:"product_ids"`
+}
+
+// audience is the aud claim, either a single string or a list of them
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+// signingAlgorithm describes how a JWS algorithm signs the token
+type signingAlgorithm struct {
+	hash  crypto.Hash
+	curve elliptic.Curve
+	hmac  bool
+}
+
+var signingAlgorithms = map[string]signingAlgorithm{
+	"HS256": {hash: crypto.SHA256, hmac: true},
+	"HS384": {hash: crypto.SHA384, hmac: true},
+	"HS512": {hash: crypto.SHA512, hmac: true},
+	"RS256": {hash: crypto.SHA256},
+	"RS384": {hash: crypto.SHA384},
+	"RS512": {hash: crypto.SHA512},
+	"ES256": {hash: crypto.SHA256, curve: elliptic.P256()},
+	"ES384": {hash: crypto.SHA384, curve: elliptic.P384()},
+	"ES512": {hash: crypto.SHA512, curve: elliptic.P521()},
+}
+
+// JWTVerifier verifies the signature and the claims of bearer tokens
+type JWTVerifier struct {
+	secret   []byte
+	keys     map[string]crypto.PublicKey
+	issuer   string
+	audience string
+	now      func() time.Time
+}
+
+// NewJWTVerifier returns a verifier for the configured secret and key set
+func NewJWTVerifier(config JWTConfig) (*JWTVerifier, error) {
+	verifier := &JWTVerifier{
+		secret:   []byte(config.Secret),
+		issuer:   config.Issuer,
+		audience: config.Audience,
+		now:      time.Now,
+	}
+	if config.JWKSPath != "" {
+		keys, err := loadJWKS(config.JWKSPath)
+		if err != nil {
+			return nil, err
+		}
+		verifier.keys = keys
+	}
+	return verifier, nil
+}
+
+// Verify checks the signature of a token and its exp, nbf, iss and aud claims, and returns its claims.
+// Tokens must expire and name their subject.
+func (v *JWTVerifier) Verify(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: expected 3 segments, got %d", ErrInvalidToken, len(parts))
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: header: %w", ErrInvalidToken, err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: signature: %w", ErrInvalidToken, err)
+	}
+	if err := v.verifySignature(header.Alg, header.Kid, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: claims: %w", ErrInvalidToken, err)
+	}
+	if err := v.checkClaims(claims); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	return &claims, nil
+}
+
+func (v *JWTVerifier) verifySignature(alg, kid, signed string, signature []byte) error {
+	algorithm, ok := signingAlgorithms[alg]
+	if !ok {
+		return fmt.Errorf("unsupported algorithm %q", alg)
+	}
+	if algorithm.hmac {
+		if len(v.secret) == 0 {
+			return fmt.Errorf("no secret configured for %s", alg)
+		}
+		mac := hmac.New(algorithm.hash.New, v.secret)
+		mac.Write([]byte(signed))
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return errors.New("signature mismatch")
+		}
+		return nil
+	}
+
+	key, err := v.key(kid)
+	if err != nil {
+		return err
+	}
+	h := algorithm.hash.New()
+	h.Write([]byte(signed))
+	digest := h.Sum(nil)
+
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		if algorithm.curve != nil {
+			return fmt.Errorf("key %q cannot verify %s", kid, alg)
+		}
+		if err := rsa.VerifyPKCS1v15(key, algorithm.hash, digest, signature); err != nil {
+			return errors.New("signature mismatch")
+		}
+	case *ecdsa.PublicKey:
+		if algorithm.curve != key.Curve {
+			return fmt.Errorf("key %q cannot verify %s", kid, alg)
+		}
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return errors.New("signature mismatch")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(key, digest, r, s) {
+			return errors.New("signature mismatch")
+		}
+	}
+	return nil
+}
+
+// key returns the key of the set with the given id. Tokens without kid are accepted when the set holds a single key.
+func (v *JWTVerifier) key(kid string) (crypto.PublicKey, error) {
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, nil
+		}
+	}
+	key, ok := v.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+	return key, nil
+}
+
+func (v *JWTVerifier) checkClaims(claims Claims) error {
+	now := v.now()
+	if claims.ExpiresAt == nil {
+		return errors.New("missing exp claim")
+	}
+	if now.After(unixTime(*claims.ExpiresAt).Add(jwtLeeway)) {
+		return errors.New("token expired")
+	}
+	if claims.NotBefore != nil && now.Add(jwtLeeway).Before(unixTime(*claims.NotBefore)) {
+		return errors.New("token not valid yet")
+	}
+	if claims.Subject == "" {
+		return errors.New("missing sub claim")
+	}
+	if v.issuer != "" && claims.Issuer != v.issuer {
+		return fmt.Errorf("unexpected issuer %q", claims.Issuer)
+	}
+	if v.audience != "" && !slices.Contains(claims.Audience, v.audience) {
+		return fmt.Errorf("token is not meant for audience %q", v.audience)
+	}
+	return nil
+}
+
+func unixTime(seconds float64) time.Time {
+	return time.Unix(0, int64(seconds*float64(time.Second)))
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// jsonWebKey is a key of a JSON Web Key Set. Only RSA and EC public keys are supported.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// loadJWKS reads the signing keys of a JSON Web Key Set file, by key id
+func loadJWKS(path string) (map[string]crypto.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key set: %w", err)
+	}
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse key set %s: %w", path, err)
+	}
+
+	keys := map[string]crypto.PublicKey{}
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q in %s: %w", jwk.Kid, path, err)
+		}
+		keys[jwk.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no signing keys in %s", path)
+	}
+	return keys, nil
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("n: %w", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("e: %w", err)
+		}
+		if !e.IsInt64() || e.Int64() < 2 || e.Int64() > 1<<31-1 {
+			return nil, errors.New("e: out of range")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("x: %w", err)
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("y: %w", err)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty value")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
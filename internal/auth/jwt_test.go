@@ -0,0 +1,197 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var b64 = base64.RawURLEncoding
+
+// signToken builds a token signed with key, a []byte secret, an RSA or an ECDSA private key
+func signToken(t *testing.T, alg, kid string, claims map[string]any, key any) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": alg, "typ": "JWT", "kid": kid})
+	payload, _ := json.Marshal(claims)
+	signed := b64.EncodeToString(header) + "." + b64.EncodeToString(payload)
+
+	algorithm := signingAlgorithms[alg]
+	h := algorithm.hash.New()
+	h.Write([]byte(signed))
+	digest := h.Sum(nil)
+
+	var signature []byte
+	switch key := key.(type) {
+	case []byte:
+		mac := hmac.New(algorithm.hash.New, key)
+		mac.Write([]byte(signed))
+		signature = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		var err error
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, algorithm.hash, digest)
+		assert.NoError(t, err)
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest)
+		assert.NoError(t, err)
+		size := (key.Curve.Params().BitSize + 7) / 8
+		signature = append(r.FillBytes(make([]byte, size)), s.FillBytes(make([]byte, size))...)
+	}
+	return signed + "." + b64.EncodeToString(signature)
+}
+
+// writeJWKS writes the public keys to a key set file and returns its path
+func writeJWKS(t *testing.T, keys map[string]crypto.PublicKey) string {
+	t.Helper()
+	var set struct {
+		Keys []map[string]string `json:"keys"`
+	}
+	for kid, key := range keys {
+		switch key := key.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, map[string]string{"kty": "RSA", "kid": kid, "use": "sig",
+				"n": b64.EncodeToString(key.N.Bytes()), "e": b64.EncodeToString(big.NewInt(int64(key.E)).Bytes())})
+		case *ecdsa.PublicKey:
+			set.Keys = append(set.Keys, map[string]string{"kty": "EC", "kid": kid, "crv": key.Curve.Params().Name,
+				"x": b64.EncodeToString(key.X.Bytes()), "y": b64.EncodeToString(key.Y.Bytes())})
+		}
+	}
+	data, _ := json.Marshal(set)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	assert.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func TestJWTVerifier(t *testing.T) {
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	secret := []byte("secret")
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	verifier, err := NewJWTVerifier(JWTConfig{
+		Secret:   string(secret),
+		JWKSPath: writeJWKS(t, map[string]crypto.PublicKey{"rsa": &rsaKey.PublicKey, "ec": &ecKey.PublicKey}),
+		Issuer:   "https://issuer.example",
+		Audience: "order-pack-calculator",
+	})
+	assert.NoError(t, err)
+	verifier.now = func() time.Time { return now }
+
+	claims := func(overrides map[string]any) map[string]any {
+		c := map[string]any{
+			"sub":  "user-1",
+			"name": "Jane",
+			"iss":  "https://issuer.example",
+			"aud":  []string{"other", "order-pack-calculator"},
+			"exp":  now.Add(time.Hour).Unix(),
+		}
+		for k, v := range overrides {
+			if v == nil {
+				delete(c, k)
+				continue
+			}
+			c[k] = v
+		}
+		return c
+	}
+
+	t.Run("valid tokens", func(t *testing.T) {
+		tokens := map[string]string{
+			"HS256": signToken(t, "HS256", "", claims(nil), secret),
+			"HS512": signToken(t, "HS512", "", claims(nil), secret),
+			"RS256": signToken(t, "RS256", "rsa", claims(nil), rsaKey),
+			"RS384": signToken(t, "RS384", "rsa", claims(nil), rsaKey),
+			"ES256": signToken(t, "ES256", "ec", claims(map[string]any{"aud": "order-pack-calculator"}), ecKey),
+		}
+		for alg, token := range tokens {
+			res, err := verifier.Verify(token)
+			if assert.NoError(t, err, alg) {
+				assert.Equal(t, "user-1", res.Subject)
+				assert.Equal(t, "Jane", res.Name)
+			}
+		}
+	})
+
+	t.Run("invalid tokens", func(t *testing.T) {
+		otherKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		valid := signToken(t, "HS256", "", claims(nil), secret)
+		tokens := map[string]string{
+			"malformed":        "not-a-token",
+			"tampered":         valid[:len(valid)-2] + "xx",
+			"wrong secret":     signToken(t, "HS256", "", claims(nil), []byte("other")),
+			"wrong key":        signToken(t, "ES256", "ec", claims(nil), otherKey),
+			"unknown kid":      signToken(t, "RS256", "missing", claims(nil), rsaKey),
+			"key of other alg": signToken(t, "ES256", "rsa", claims(nil), ecKey),
+			"none algorithm":   b64.EncodeToString([]byte(`{"alg":"none"}`)) + "." + b64.EncodeToString([]byte(`{"sub":"x"}`)) + ".",
+			"expired":          signToken(t, "HS256", "", claims(map[string]any{"exp": now.Add(-time.Hour).Unix()}), secret),
+			"no expiry":        signToken(t, "HS256", "", claims(map[string]any{"exp": nil}), secret),
+			"not valid yet":    signToken(t, "HS256", "", claims(map[string]any{"nbf": now.Add(time.Hour).Unix()}), secret),
+			"no subject":       signToken(t, "HS256", "", claims(map[string]any{"sub": nil}), secret),
+			"wrong issuer":     signToken(t, "HS256", "", claims(map[string]any{"iss": "https://evil.example"}), secret),
+			"wrong audience":   signToken(t, "HS256", "", claims(map[string]any{"aud": "other"}), secret),
+		}
+		for name, token := range tokens {
+			_, err := verifier.Verify(token)
+			assert.ErrorIs(t, err, ErrInvalidToken, name)
+		}
+	})
+
+	t.Run("leeway", func(t *testing.T) {
+		token := signToken(t, "HS256", "", claims(map[string]any{"exp": now.Add(-30 * time.Second).Unix()}), secret)
+		_, err := verifier.Verify(token)
+		assert.NoError(t, err)
+	})
+
+	t.Run("hmac needs a secret", func(t *testing.T) {
+		verifier, err := NewJWTVerifier(JWTConfig{JWKSPath: writeJWKS(t, map[string]crypto.PublicKey{"rsa": &rsaKey.PublicKey})})
+		assert.NoError(t, err)
+		verifier.now = func() time.Time { return now }
+
+		_, err = verifier.Verify(signToken(t, "HS256", "", claims(nil), []byte{}))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+
+		// A single key is used for tokens without kid
+		_, err = verifier.Verify(signToken(t, "RS256", "", claims(nil), rsaKey))
+		assert.NoError(t, err)
+	})
+}
+
+func TestLoadJWKS(t *testing.T) {
+	dir := t.TempDir()
+	write := func(content string) string {
+		path := filepath.Join(dir, "jwks.json")
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	_, err := loadJWKS(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+
+	for _, content := range []string{
+		`not json`,
+		`{"keys":[]}`,
+		`{"keys":[{"kty":"oct","kid":"a","k":"c2VjcmV0"}]}`,
+		`{"keys":[{"kty":"RSA","kid":"a","n":"","e":"AQAB"}]}`,
+		`{"keys":[{"kty":"EC","kid":"a","crv":"P-192","x":"AQ","y":"AQ"}]}`,
+	} {
+		_, err := loadJWKS(write(content))
+		assert.Error(t, err, content)
+	}
+
+	// Encryption keys are skipped
+	_, err = loadJWKS(write(`{"keys":[{"kty":"RSA","kid":"a","use":"enc","n":"AQ","e":"AQAB"}]}`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,52 @@
+package auth
+
+import "slices"
+
+// Permission allows calling a group of routes. Permissions are granted by roles, or directly as token scopes.
+type Permission string
+
+const (
+	PermissionCalculate      Permission = "orders:calculate"
+	PermissionReadOrders     Permission = "orders:read"
+	PermissionWriteOrders    Permission = "orders:write"
+	PermissionReadPackSizes  Permission = "packsizes:read"
+	PermissionWritePackSizes Permission = "packsizes:write"
+)
+
+// Roles a principal can have
+const (
+	// RoleAdmin may call every route, including the administration of pack sizes
+	RoleAdmin = "admin"
+	// RoleCalculator may only calculate orders
+	RoleCalculator = "calculator"
+)
+
+// rolePermissions lists the permissions each role grants
+var rolePermissions = map[string][]Permission{
+	RoleAdmin:      {PermissionCalculate, PermissionReadOrders, PermissionWriteOrders, PermissionReadPackSizes, PermissionWritePackSizes},
+	RoleCalculator: {PermissionCalculate},
+}
+
+// IsRole reports whether role is one of the known roles
+func IsRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// Can reports whether one of the roles or scopes of the principal grants the permission
+func (p Principal) Can(permission Permission) bool {
+	if slices.Contains(p.Scopes, string(permission)) {
+		return true
+	}
+	for _, role := range p.Roles {
+		if slices.Contains(rolePermissions[role], permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// CanAccessProduct reports whether the principal may act on the product. Principals not restricted to a set of products may act on all of them.
+func (p Principal) CanAccessProduct(productID int) bool {
+	return len(p.ProductIDs) == 0 || slices.Contains(p.ProductIDs, productID)
+}
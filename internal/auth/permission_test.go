@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrincipalCan(t *testing.T) {
+	admin := Principal{Roles: []string{RoleAdmin}}
+	calculator := Principal{Roles: []string{RoleCalculator}}
+	scoped := Principal{Scopes: []string{string(PermissionReadOrders)}}
+	unknown := Principal{Roles: []string{"root"}}
+
+	for _, permission := range []Permission{PermissionCalculate, PermissionReadOrders, PermissionWriteOrders, PermissionReadPackSizes, PermissionWritePackSizes} {
+		assert.True(t, admin.Can(permission), permission)
+		assert.Equal(t, permission == PermissionCalculate, calculator.Can(permission), permission)
+		assert.Equal(t, permission == PermissionReadOrders, scoped.Can(permission), permission)
+		assert.False(t, unknown.Can(permission), permission)
+	}
+
+	assert.True(t, IsRole(RoleAdmin))
+	assert.False(t, IsRole("root"))
+}
+
+func TestPrincipalCanAccessProduct(t *testing.T) {
+	assert.True(t, Principal{}.CanAccessProduct(1))
+	assert.True(t, Principal{ProductIDs: []int{1, 2}}.CanAccessProduct(2))
+	assert.False(t, Principal{ProductIDs: []int{1, 2}}.CanAccessProduct(3))
+}
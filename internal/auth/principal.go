@@ -0,0 +1,39 @@
+// Package auth authenticates the clients of the API, either with an API key or with a JWT bearer token,
+// and carries the authenticated principal in the request context.
+package auth
+
+import "context"
+
+// Methods a principal can be authenticated with
+const (
+	MethodAPIKey = "apikey"
+	MethodJWT    = "jwt"
+)
+
+// Principal is the authenticated client of a request, recorded for audit purposes
+type Principal struct {
+	// Subject identifies the client: apikey:<id> for API keys, the sub claim for tokens
+	Subject string `json:"subject"`
+	// Method is how the client authenticated, apikey or jwt
+	Method string `json:"method"`
+	// Name is the name of the API key or the name claim of the token, if any
+	Name string `json:"name,omitempty"`
+	// Roles and Scopes grant the permissions of the principal
+	Roles  []string `json:"roles,omitempty"`
+	Scopes []string `json:"scopes,omitempty"`
+	// ProductIDs restricts the principal to these products, when not empty
+	ProductIDs []int `json:"product_ids,omitempty"`
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the principal
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFrom returns the principal carried by ctx, if any
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
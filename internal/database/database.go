@@ -4,10 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"log"
+	"log/slog"
+	"order-pack-calculator/internal/health"
 	"os"
-	"strconv"
-	"time"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
 	_ "github.com/joho/godotenv/autoload"
@@ -15,9 +14,9 @@ import (
 
 // Service represents a service that interacts with a database.
 type Service interface {
-	// Health returns a map of health status information.
-	// The keys and values in the map are service-specific.
-	Health() map[string]string
+	// Health checks the database can be reached, without ever failing the process.
+	// It is the database check of the health registry.
+	Health(ctx context.Context) health.Result
 
 	// Close terminates the database connection.
 	// It returns an error if the connection cannot be closed.
@@ -39,71 +38,55 @@ var (
 	dbInstance *service
 )
 
-func New() Service {
+// New opens the Postgres database configured by the DB_* environment variables.
+// It returns an error instead of stopping the process, for the caller to report.
+func New() (Service, error) {
 	// Reuse Connection
 	if dbInstance != nil {
-		return dbInstance
+		return dbInstance, nil
 	}
 	connStr := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable&search_path=%s", username, password, host, port, database, schema)
 	db, err := sql.Open("pgx", connStr)
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("failed to open postgres database %s: %w", database, err)
 	}
 	dbInstance = &service{
 		db: db,
 	}
-	return dbInstance
+	return dbInstance, nil
 }
 
-// Health checks the health of the database connection by pinging the database.
-// It returns a map with keys indicating various health statistics.
-func (s *service) Health() map[string]string {
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
-	defer cancel()
-
-	stats := make(map[string]string)
+// Health pings the database and reports the statistics of its connection pool.
+// A database that cannot be reached is reported down, and one under heavy load degraded.
+func (s *service) Health(ctx context.Context) health.Result {
+	return poolHealth(ctx, s.db)
+}
 
-	// Ping the database
-	err := s.db.PingContext(ctx)
-	if err != nil {
-		stats["status"] = "down"
-		stats["error"] = fmt.Sprintf("db down: %v", err)
-		log.Fatalf("db down: %v", err) // Log the error and terminate the program
-		return stats
+// poolHealth pings db and describes its connection pool
+func poolHealth(ctx context.Context, db *sql.DB) health.Result {
+	if err := db.PingContext(ctx); err != nil {
+		return health.Failed("database unreachable", err)
 	}
 
-	// Database is up, add more statistics
-	stats["status"] = "up"
-	stats["message"] = "It's healthy"
-
-	// Get database stats (like open connections, in use, idle, etc.)
-	dbStats := s.db.Stats()
-	stats["open_connections"] = strconv.Itoa(dbStats.OpenConnections)
-	stats["in_use"] = strconv.Itoa(dbStats.InUse)
-	stats["idle"] = strconv.Itoa(dbStats.Idle)
-	stats["wait_count"] = strconv.FormatInt(dbStats.WaitCount, 10)
-	stats["wait_duration"] = dbStats.WaitDuration.String()
-	stats["max_idle_closed"] = strconv.FormatInt(dbStats.MaxIdleClosed, 10)
-	stats["max_lifetime_closed"] = strconv.FormatInt(dbStats.MaxLifetimeClosed, 10)
-
-	// Evaluate stats to provide a health message
-	if dbStats.OpenConnections > 40 { // Assuming 50 is the max for this example
-		stats["message"] = "The database is experiencing heavy load."
+	dbStats := db.Stats()
+	details := map[string]any{
+		"open_connections":    dbStats.OpenConnections,
+		"in_use":              dbStats.InUse,
+		"idle":                dbStats.Idle,
+		"wait_count":          dbStats.WaitCount,
+		"wait_duration":       dbStats.WaitDuration.String(),
+		"max_idle_closed":     dbStats.MaxIdleClosed,
+		"max_lifetime_closed": dbStats.MaxLifetimeClosed,
 	}
 
-	if dbStats.WaitCount > 1000 {
-		stats["message"] = "The database has a high number of wait events, indicating potential bottlenecks."
-	}
-
-	if dbStats.MaxIdleClosed > int64(dbStats.OpenConnections)/2 {
-		stats["message"] = "Many idle connections are being closed, consider revising the connection pool settings."
+	// Evaluate stats to provide a health message
+	if dbStats.MaxOpenConnections > 0 && dbStats.InUse >= dbStats.MaxOpenConnections {
+		return health.Degraded("Every connection of the pool is in use, requests wait for one.", details)
 	}
-
-	if dbStats.MaxLifetimeClosed > int64(dbStats.OpenConnections)/2 {
-		stats["message"] = "Many connections are being closed due to max lifetime, consider increasing max lifetime or revising the connection usage pattern."
+	if dbStats.OpenConnections > 40 { // Assuming 50 is the max for this example
+		return health.Degraded("The database is experiencing heavy load.", details)
 	}
-
-	return stats
+	return health.Up(details)
 }
 
 // Close closes the database connection.
@@ -111,7 +94,7 @@ func (s *service) Health() map[string]string {
 // If the connection is successfully closed, it returns nil.
 // If an error occurs while closing the connection, it returns the error.
 func (s *service) Close() error {
-	log.Printf("Disconnected from database: %s", database)
+	slog.Info("disconnected from database", "database", database)
 	return s.db.Close()
 }
 
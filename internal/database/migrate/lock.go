@@ -0,0 +1,25 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Key of the postgres advisory lock held while migrating, shared by every replica of the service
+const advisoryLockID int64 = 0x6f7063_6d696772
+
+// advisoryLock takes the postgres advisory lock, waiting for other migrators to release it.
+// The lock belongs to the session, so it is taken and released on the same connection.
+func advisoryLock(ctx context.Context, conn *sql.Conn) (func(), error) {
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, advisoryLockID); err != nil {
+		return nil, fmt.Errorf("failed to take migration lock: %w", err)
+	}
+	return func() {
+		conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, advisoryLockID)
+	}, nil
+}
+
+func noLock(ctx context.Context, conn *sql.Conn) (func(), error) {
+	return func() {}, nil
+}
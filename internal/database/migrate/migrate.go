@@ -0,0 +1,302 @@
+// Package migrate applies the SQL migrations embedded in the binary.
+//
+// Migrations are pairs of files named <version>_<name>.up.sql and <version>_<name>.down.sql,
+// as created by golang-migrate. The applied version is recorded in the same schema_migrations
+// table the migrate CLI uses, so databases migrated with either tool stay interchangeable.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"modernc.org/sqlite"
+)
+
+var (
+	// ErrDirty is returned when a migration run by the migrate CLI failed half way.
+	// The schema has to be repaired by hand before migrating again.
+	ErrDirty = errors.New("database schema is dirty")
+	// ErrSchemaMismatch is returned by Check when the database is behind the latest migration.
+	ErrSchemaMismatch = errors.New("database schema does not match the migrations")
+)
+
+// Migration is a schema change and the script reverting it
+type Migration struct {
+	Version int64
+	Name    string
+	up      string
+	down    string
+}
+
+// Status describes the schema of the database compared to the known migrations
+type Status struct {
+	// Version is the last applied migration, 0 when none is
+	Version int64
+	Dirty   bool
+	// Latest is the version of the last known migration
+	Latest int64
+	// Pending are the migrations not applied yet, in order
+	Pending []Migration
+}
+
+// Migrator applies migrations to a database, one transaction per migration.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+	// lock serializes migrators sharing the database until the returned function is called
+	lock func(ctx context.Context, conn *sql.Conn) (func(), error)
+	// versionTableExists is the query telling whether the schema_migrations table exists
+	versionTableExists string
+}
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// New returns a Migrator applying the migrations found at the root of source to db.
+// db must be a postgres or sqlite database opened by the database package.
+func New(db *sql.DB, source fs.FS) (*Migrator, error) {
+	files, err := fs.Glob(source, "*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, file := range files {
+		match := migrationFileName.FindStringSubmatch(file)
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %s", file)
+		}
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		script, err := fs.ReadFile(source, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", file, err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if match[3] == "up" {
+			migration.up = string(script)
+		} else {
+			migration.down = string(script)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int { return int(a.Version - b.Version) })
+
+	m := &Migrator{db: db, migrations: migrations, lock: advisoryLock, versionTableExists: postgresVersionTableExists}
+	// SQLite transactions take the write lock as they begin, so they are serialized already
+	if _, ok := db.Driver().(*sqlite.Driver); ok {
+		m.lock = noLock
+		m.versionTableExists = sqliteVersionTableExists
+	}
+	return m, nil
+}
+
+// Up applies every pending migration and returns the ones applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		for {
+			migration, err := step(ctx, conn, m.next)
+			if err != nil || migration == nil {
+				return err
+			}
+			applied = append(applied, *migration)
+		}
+	})
+	return applied, err
+}
+
+// Down reverts the given number of applied migrations, all of them when steps <= 0, and returns the ones reverted.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		for steps <= 0 || len(reverted) < steps {
+			migration, err := step(ctx, conn, m.previous)
+			if err != nil || migration == nil {
+				return err
+			}
+			reverted = append(reverted, *migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// next returns the migration following the current version, its up script and the version it migrates to
+func (m *Migrator) next(current int64) (*Migration, string, int64) {
+	i := slices.IndexFunc(m.migrations, func(migration Migration) bool { return migration.Version > current })
+	if i < 0 {
+		return nil, "", 0
+	}
+	return &m.migrations[i], m.migrations[i].up, m.migrations[i].Version
+}
+
+// previous returns the migration at the current version, its down script and the version it reverts to
+func (m *Migrator) previous(current int64) (*Migration, string, int64) {
+	i := slices.IndexFunc(m.migrations, func(migration Migration) bool { return migration.Version == current })
+	if i < 0 {
+		return nil, "", 0
+	}
+	var version int64
+	if i > 0 {
+		version = m.migrations[i-1].Version
+	}
+	return &m.migrations[i], m.migrations[i].down, version
+}
+
+// Status compares the schema of the database with the known migrations.
+// It only reads the database: a database without a schema_migrations table is at version 0.
+func (m *Migrator) Status(ctx context.Context) (*Status, error) {
+	var exists bool
+	if err := m.db.QueryRowContext(ctx, m.versionTableExists).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to look up the schema_migrations table: %w", err)
+	}
+	var (
+		version int64
+		dirty   bool
+	)
+	if exists {
+		var err error
+		version, dirty, err = currentVersion(ctx, m.db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	status := Status{Version: version, Dirty: dirty}
+	for _, migration := range m.migrations {
+		status.Latest = migration.Version
+		if migration.Version > version {
+			status.Pending = append(status.Pending, migration)
+		}
+	}
+	return &status, nil
+}
+
+// Check fails with ErrSchemaMismatch when the database is behind the latest migration,
+// or with ErrDirty when the last migration did not complete. A database ahead of the migrations,
+// as when a newer release migrated it during a rolling deploy, is only logged.
+func (m *Migrator) Check(ctx context.Context) error {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if status.Dirty {
+		return fmt.Errorf("%w: version %d", ErrDirty, status.Version)
+	}
+	if status.Version < status.Latest {
+		return fmt.Errorf("%w: database is at version %d, expected %d", ErrSchemaMismatch, status.Version, status.Latest)
+	}
+	if status.Version > status.Latest {
+		slog.WarnContext(ctx, "database schema is ahead of the migrations", "version", status.Version, "latest", status.Latest)
+	}
+	return nil
+}
+
+// locked runs fn on a connection holding the migration lock
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to the database: %w", err)
+	}
+	defer conn.Close()
+
+	unlock, err := m.lock(ctx, conn)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := createVersionTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// step runs the script chosen by choose in a transaction that also records the new version.
+// The current version is read inside the transaction, so a script is never applied twice.
+func step(ctx context.Context, conn *sql.Conn, choose func(current int64) (*Migration, string, int64)) (*Migration, error) {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	current, dirty, err := currentVersion(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if dirty {
+		return nil, fmt.Errorf("%w: version %d", ErrDirty, current)
+	}
+	migration, script, version := choose(current)
+	if migration == nil {
+		return nil, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return nil, fmt.Errorf("failed to run migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations`); err != nil {
+		return nil, fmt.Errorf("failed to record schema version: %w", err)
+	}
+	if version > 0 {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)`, version); err != nil {
+			return nil, fmt.Errorf("failed to record schema version: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+	return migration, nil
+}
+
+// execer is implemented by *sql.DB, *sql.Conn and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// The queries telling whether the schema_migrations table exists, in the schema tables are created in
+const (
+	postgresVersionTableExists = `SELECT EXISTS (SELECT 1 FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = 'schema_migrations')`
+	sqliteVersionTableExists   = `SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations')`
+)
+
+func createVersionTable(ctx context.Context, db execer) error {
+	query := `CREATE TABLE IF NOT EXISTS schema_migrations (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)`
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+	return nil
+}
+
+// currentVersion returns the applied version, 0 when the table is empty
+func currentVersion(ctx context.Context, db execer) (int64, bool, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+	err := db.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, false, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, dirty, nil
+}
@@ -0,0 +1,166 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"order-pack-calculator/internal/database"
+	"order-pack-calculator/migrations"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func openSQLite(t *testing.T) *sql.DB {
+	dbService, err := database.NewSQLite(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { dbService.Close() })
+	return dbService.GetDB()
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	migrator, err := New(db, migrations.SQLite())
+	assert.NoError(t, err)
+
+	t.Run("status of an empty database", func(t *testing.T) {
+		status, err := migrator.Status(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), status.Version)
+		assert.Equal(t, int64(10), status.Latest)
+		assert.Len(t, status.Pending, 10)
+		assert.ErrorIs(t, migrator.Check(ctx), ErrSchemaMismatch)
+
+		// Reading the status creates nothing
+		var tables int
+		assert.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name = 'schema_migrations'`).Scan(&tables))
+		assert.Zero(t, tables)
+	})
+
+	t.Run("up applies every pending migration", func(t *testing.T) {
+		applied, err := migrator.Up(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, applied, 10)
+		assert.Equal(t, "create_table_pack_sizes", applied[0].Name)
+		assert.NoError(t, migrator.Check(ctx))
+
+		var count int
+		assert.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM pack_sizes`).Scan(&count))
+		assert.NotZero(t, count)
+	})
+
+	t.Run("up is a no-op at the latest version", func(t *testing.T) {
+		applied, err := migrator.Up(ctx)
+		assert.NoError(t, err)
+		assert.Empty(t, applied)
+	})
+
+	t.Run("down reverts the given number of migrations", func(t *testing.T) {
+		reverted, err := migrator.Down(ctx, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{10, 9}, []int64{reverted[0].Version, reverted[1].Version})
+
+		status, err := migrator.Status(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(8), status.Version)
+		assert.Len(t, status.Pending, 2)
+		assert.ErrorIs(t, migrator.Check(ctx), ErrSchemaMismatch)
+	})
+
+	t.Run("down reverts everything", func(t *testing.T) {
+		reverted, err := migrator.Down(ctx, 0)
+		assert.NoError(t, err)
+		assert.Len(t, reverted, 8)
+
+		status, err := migrator.Status(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), status.Version)
+
+		_, err = db.Exec(`SELECT 1 FROM pack_sizes`)
+		assert.Error(t, err)
+	})
+
+	t.Run("schema ahead of the migrations", func(t *testing.T) {
+		_, err := db.Exec(`INSERT INTO schema_migrations (version, dirty) VALUES (11, false)`)
+		assert.NoError(t, err)
+		defer db.Exec(`DELETE FROM schema_migrations`)
+
+		assert.NoError(t, migrator.Check(ctx))
+	})
+
+	t.Run("dirty schema", func(t *testing.T) {
+		_, err := db.Exec(`INSERT INTO schema_migrations (version, dirty) VALUES (3, true)`)
+		assert.NoError(t, err)
+		defer db.Exec(`DELETE FROM schema_migrations`)
+
+		_, err = migrator.Up(ctx)
+		assert.ErrorIs(t, err, ErrDirty)
+		assert.ErrorIs(t, migrator.Check(ctx), ErrDirty)
+	})
+}
+
+func TestFailingMigrationIsRolledBack(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	migrator, err := New(db, fstest.MapFS{
+		"000001_create_a.up.sql":   {Data: []byte(`CREATE TABLE a (id integer)`)},
+		"000001_create_a.down.sql": {Data: []byte(`DROP TABLE a`)},
+		"000002_broken.up.sql":     {Data: []byte(`CREATE TABLE b (id integer); INSERT INTO missing VALUES (1)`)},
+	})
+	assert.NoError(t, err)
+
+	applied, err := migrator.Up(ctx)
+	assert.ErrorContains(t, err, "failed to run migration 2_broken")
+	assert.Len(t, applied, 1)
+
+	status, err := migrator.Status(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), status.Version)
+	assert.False(t, status.Dirty)
+
+	_, err = db.Exec(`SELECT 1 FROM b`)
+	assert.Error(t, err)
+}
+
+func TestConcurrentMigrators(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		total int
+	)
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			migrator, _ := New(db, migrations.SQLite())
+			applied, err := migrator.Up(ctx)
+			assert.NoError(t, err)
+
+			mu.Lock()
+			defer mu.Unlock()
+			total += len(applied)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 10, total)
+}
+
+func TestInvalidSource(t *testing.T) {
+	db := openSQLite(t)
+
+	_, err := New(db, fstest.MapFS{"create_a.up.sql": {Data: []byte(`CREATE TABLE a (id integer)`)}})
+	assert.ErrorContains(t, err, "invalid migration file name")
+
+	_, err = New(db, fstest.MapFS{"000001_create_a.down.sql": {Data: []byte(`DROP TABLE a`)}})
+	assert.ErrorContains(t, err, "has no up script")
+}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"order-pack-calculator/internal/health"
+
+	_ "modernc.org/sqlite"
+)
+
+type sqliteService struct {
+	path string
+	db   *sql.DB
+}
+
+// NewSQLite opens the SQLite database file at path, creating it when missing.
+// Foreign keys are enforced and writers wait for each other instead of failing.
+func NewSQLite(path string) (Service, error) {
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Set("_time_format", "sqlite")
+	// Transactions take the write lock when they begin, so rows read in one cannot change before it ends
+	params.Set("_txlock", "immediate")
+
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?%s", path, params.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database %s: %w", path, err)
+	}
+	return &sqliteService{path: path, db: db}, nil
+}
+
+// Health checks the database file can be read by pinging it.
+func (s *sqliteService) Health(ctx context.Context) health.Result {
+	result := poolHealth(ctx, s.db)
+	if result.Details != nil {
+		result.Details["path"] = s.path
+	}
+	return result
+}
+
+func (s *sqliteService) Close() error {
+	slog.Info("disconnected from database", "path", s.path)
+	return s.db.Close()
+}
+
+func (s *sqliteService) GetDB() *sql.DB {
+	return s.db
+}
@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"order-pack-calculator/internal/domain/entities"
+	"time"
+)
+
+type CreateAPIKeyRequest struct {
+	Name  string   `json:"name" binding:"required,max=255"`
+	Roles []string `json:"roles" binding:"required,min=1"`
+	// ProductIDs restricts the key to these products, all products when empty
+	ProductIDs []int `json:"product_ids" binding:"dive,min=1"`
+}
+
+type APIKeyResponse struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Roles      []string   `json:"roles"`
+	ProductIDs []int      `json:"product_ids,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// CreatedAPIKeyResponse carries the key itself, which is only returned when it is created
+type CreatedAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+func APIKeyResponseFromEntity(key entities.APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Roles:      key.Roles,
+		ProductIDs: key.ProductIDs,
+		CreatedAt:  key.CreatedAt,
+		RevokedAt:  key.RevokedAt,
+	}
+}
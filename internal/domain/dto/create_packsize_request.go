@@ -1,6 +1,10 @@
 package dto
 
+import "time"
+
 type CreatePackSizeRequest struct {
-	ProductID int `json:"product_id" binding:"required"`
-	Size      int `json:"size" binding:"required,min=1"`
+	ProductID int        `json:"product_id" binding:"required"`
+	Size      int        `json:"size" binding:"required,min=1"`
+	ValidFrom *time.Time `json:"valid_from,omitempty" example:"2026-11-01T00:00:00Z"`
+	ValidTo   *time.Time `json:"valid_to,omitempty" example:"2027-01-01T00:00:00Z"`
 }
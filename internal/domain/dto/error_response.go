@@ -1,6 +1,25 @@
 package dto
 
+// ErrorResponse is an RFC 7807 problem details body, sent as application/problem+json
 type ErrorResponse struct {
-	Message string `json:"message"`
-	Details string `json:"details"`
+	// Type is a URI reference identifying the problem, /problems/{code}
+	Type   string `json:"type" example:"/problems/not_found"`
+	Title  string `json:"title" example:"Resource not found"`
+	Status int    `json:"status" example:"404"`
+	// Detail explains this occurrence of the problem. Internal errors are not detailed.
+	Detail   string `json:"detail,omitempty" example:"resource not found: order id=5"`
+	Instance string `json:"instance,omitempty" example:"/api/v1/orders/5"`
+	// Code identifies the problem. Codes are stable, clients should match them rather than the title or detail.
+	Code string `json:"code" example:"not_found"`
+	// Errors lists the invalid fields of a request that failed validation
+	Errors []FieldError `json:"errors,omitempty"`
+	// RequestID identifies the request in the logs of the service
+	RequestID string `json:"request_id,omitempty" example:"5f0c6a3e9b1d4e7f8a2b3c4d5e6f7a8b"`
+}
+
+// FieldError describes why the value of a request field is invalid
+type FieldError struct {
+	Field   string `json:"field" example:"order_quantity"`
+	Rule    string `json:"rule" example:"min"`
+	Message string `json:"message" example:"must be at least 1"`
 }
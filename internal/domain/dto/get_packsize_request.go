@@ -0,0 +1,5 @@
+package dto
+
+type GetPackSizeRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
@@ -0,0 +1,30 @@
+package dto
+
+import "order-pack-calculator/internal/health"
+
+// HealthResponse is the readiness of the service: the worst status of its checks, and the result of each.
+// The probe needs no credentials, so the errors and statistics of the checks are only logged.
+type HealthResponse struct {
+	Status string `json:"status" example:"up" enums:"up,degraded,down"`
+	// Message tells why the service is down when no check is to blame, e.g. it is shutting down
+	Message string                         `json:"message,omitempty"`
+	Checks  map[string]HealthCheckResponse `json:"checks,omitempty"`
+}
+
+// HealthCheckResponse is the result of a check of a dependency of the service
+type HealthCheckResponse struct {
+	Status string `json:"status" example:"degraded" enums:"up,degraded,down"`
+	// Message explains a degraded or down status
+	Message string `json:"message,omitempty" example:"8.5% of the disk is free"`
+}
+
+func HealthResponseFromReport(report health.Report) HealthResponse {
+	response := HealthResponse{Status: string(report.Status), Message: report.Message}
+	if len(report.Checks) > 0 {
+		response.Checks = make(map[string]HealthCheckResponse, len(report.Checks))
+	}
+	for name, result := range report.Checks {
+		response.Checks[name] = HealthCheckResponse{Status: string(result.Status), Message: result.Message}
+	}
+	return response
+}
@@ -0,0 +1,8 @@
+package dto
+
+// IdempotentResponse is a response stored under an idempotency key to be replayed on retries.
+type IdempotentResponse struct {
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
@@ -0,0 +1,13 @@
+package dto
+
+type ListPackSizesRequest struct {
+	ProductID int    `form:"product_id" binding:"omitempty,min=1"`
+	Active    *bool  `form:"active"`
+	MinSize   int    `form:"min_size" binding:"omitempty,min=1"`
+	MaxSize   int    `form:"max_size" binding:"omitempty,min=1,gtefield=MinSize"`
+	Sort      string `form:"sort" binding:"omitempty,oneof=id product_id size"`
+	Order     string `form:"order" binding:"omitempty,oneof=asc desc"`
+	Limit     int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	// Cursor is the next_cursor of the previous page
+	Cursor string `form:"cursor"`
+}
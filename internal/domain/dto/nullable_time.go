@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// NullableTime is an optional instant of a partial update that can also be cleared.
+// It is left unset when the field is absent, and set with a nil Time when the field is null.
+type NullableTime struct {
+	Set  bool
+	Time *time.Time
+}
+
+// SetTime is a NullableTime updating the field to t, or clearing it when t is nil
+func SetTime(t *time.Time) NullableTime {
+	return NullableTime{Set: true, Time: t}
+}
+
+func (n *NullableTime) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	if bytes.Equal(data, []byte("null")) {
+		n.Time = nil
+		return nil
+	}
+	var t time.Time
+	if err := json.Unmarshal(data, &t); err != nil {
+		return err
+	}
+	n.Time = &t
+	return nil
+}
+
+func (n NullableTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.Time)
+}
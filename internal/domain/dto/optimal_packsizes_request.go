@@ -1,6 +1,10 @@
 package dto
 
+import "time"
+
 type CalculatePackSizesRequest struct {
 	ProductID     int `json:"product_id" binding:"required"`
 	OrderQuantity int `json:"order_quantity" binding:"required,min=1"`
+	// AsOf selects the pack sizes effective at the given instant. Defaults to now.
+	AsOf *time.Time `json:"as_of,omitempty" example:"2026-11-01T00:00:00Z"`
 }
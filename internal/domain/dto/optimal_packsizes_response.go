@@ -1,6 +1,7 @@
 package dto
 
 type OptimalPackSizesResponse struct {
+	OrderID         int64        `json:"order_id,omitempty"`
 	PackCombination []PackDetail `json:"pack_combination"`
 	TotalItems      int          `json:"total_items"`
 	TotalPacks      int          `json:"total_packs"`
@@ -0,0 +1,23 @@
+package dto
+
+import "time"
+
+type GetOrderRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type ListOrdersRequest struct {
+	ProductID   int        `form:"product_id" binding:"omitempty,min=1"`
+	Status      string     `form:"status" binding:"omitempty,oneof=draft confirmed packed shipped cancelled"`
+	CreatedFrom *time.Time `form:"created_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedTo   *time.Time `form:"created_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit       int        `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset      int        `form:"offset" binding:"omitempty,min=0"`
+}
+
+type RecalculateOrderRequest struct {
+	// OrderQuantity replaces the quantity of the order when set.
+	OrderQuantity *int `json:"order_quantity,omitempty" binding:"omitempty,min=1"`
+	// AsOf selects the pack sizes effective at the given instant. Defaults to now.
+	AsOf *time.Time `json:"as_of,omitempty" example:"2026-11-01T00:00:00Z"`
+}
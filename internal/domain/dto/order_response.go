@@ -0,0 +1,58 @@
+package dto
+
+import (
+	"order-pack-calculator/internal/domain/entities"
+	"time"
+)
+
+type OrderResponse struct {
+	ID              int64        `json:"id"`
+	ProductID       int          `json:"product_id"`
+	OrderQuantity   int          `json:"order_quantity"`
+	PackCombination []PackDetail `json:"pack_combination"`
+	TotalItems      int          `json:"total_items"`
+	TotalPacks      int          `json:"total_packs"`
+	Objective       string       `json:"objective"`
+	PackSizes       []int        `json:"pack_sizes"`
+	AsOf            time.Time    `json:"as_of"`
+	Status          string       `json:"status"`
+	CreatedAt       time.Time    `json:"created_at"`
+	ConfirmedAt     *time.Time   `json:"confirmed_at,omitempty"`
+	PackedAt        *time.Time   `json:"packed_at,omitempty"`
+	ShippedAt       *time.Time   `json:"shipped_at,omitempty"`
+	CancelledAt     *time.Time   `json:"cancelled_at,omitempty"`
+}
+
+func OrderResponseFromEntity(order entities.Order) OrderResponse {
+	combination := make([]PackDetail, 0, len(order.Packs))
+	for _, p := range order.Packs {
+		combination = append(combination, PackDetail{Size: p.Size, Count: p.Count})
+	}
+
+	return OrderResponse{
+		ID:              order.ID,
+		ProductID:       order.ProductID,
+		OrderQuantity:   order.OrderQuantity,
+		PackCombination: combination,
+		TotalItems:      order.TotalItems,
+		TotalPacks:      order.TotalPacks,
+		Objective:       order.Objective,
+		PackSizes:       order.PackSizes,
+		AsOf:            order.AsOf,
+		Status:          string(order.Status),
+		CreatedAt:       order.CreatedAt,
+		ConfirmedAt:     order.ConfirmedAt,
+		PackedAt:        order.PackedAt,
+		ShippedAt:       order.ShippedAt,
+		CancelledAt:     order.CancelledAt,
+	}
+}
+
+func OrderResponseFromEntities(orders []entities.Order) []OrderResponse {
+	responses := make([]OrderResponse, 0, len(orders))
+
+	for _, o := range orders {
+		responses = append(responses, OrderResponseFromEntity(o))
+	}
+	return responses
+}
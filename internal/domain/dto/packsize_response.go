@@ -1,12 +1,25 @@
 package dto
 
-import "order-pack-calculator/internal/domain/entities"
+import (
+	"order-pack-calculator/internal/domain/entities"
+	"time"
+)
 
 type PackSizeResponse struct {
-	ID        int64 `json:"id"`
-	ProductID int   `json:"product_id"`
-	Size      int   `json:"size"`
-	Active    bool  `json:"active"`
+	ID        int64      `json:"id"`
+	ProductID int        `json:"product_id"`
+	Size      int        `json:"size"`
+	Active    bool       `json:"active"`
+	ValidFrom *time.Time `json:"valid_from,omitempty"`
+	ValidTo   *time.Time `json:"valid_to,omitempty"`
+	Version   int64      `json:"version"`
+}
+
+// PackSizePageResponse is a page of pack sizes. NextCursor is empty on the last page.
+type PackSizePageResponse struct {
+	Items      []PackSizeResponse `json:"items"`
+	Total      int64              `json:"total"`
+	NextCursor string             `json:"next_cursor,omitempty"`
 }
 
 func PackSizeResponseFromEntity(pack entities.PackSize) PackSizeResponse {
@@ -15,6 +28,9 @@ func PackSizeResponseFromEntity(pack entities.PackSize) PackSizeResponse {
 		ProductID: pack.ProductID,
 		Size:      pack.Size,
 		Active:    pack.Active,
+		ValidFrom: pack.ValidFrom,
+		ValidTo:   pack.ValidTo,
+		Version:   pack.Version,
 	}
 
 }
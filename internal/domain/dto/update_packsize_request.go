@@ -1,7 +1,33 @@
 package dto
 
+import "encoding/json"
+
 type UpdatePackSizeRequest struct {
 	ID     int64 `json:"id" binding:"required"`
-	Size   *int  `json:"size" binding:"min=1"`
+	Size   *int  `json:"size" binding:"omitempty,min=1"`
 	Active *bool `json:"active"`
+	// ValidFrom and ValidTo are left as they are when absent, and cleared when null
+	ValidFrom NullableTime `json:"valid_from" swaggertype:"string" format:"date-time" extensions:"x-nullable" example:"2026-11-01T00:00:00Z"`
+	ValidTo   NullableTime `json:"valid_to" swaggertype:"string" format:"date-time" extensions:"x-nullable" example:"2027-01-01T00:00:00Z"`
+	// Versions are the versions the client expects to update, any of which is accepted, taken from the If-Match header.
+	// When empty the version read before the update is used.
+	Versions []int64 `json:"-"`
+}
+
+// MarshalJSON leaves out the fields that are not updated, so that only the validity bounds set to nil are sent as null
+func (r UpdatePackSizeRequest) MarshalJSON() ([]byte, error) {
+	fields := map[string]any{"id": r.ID}
+	if r.Size != nil {
+		fields["size"] = *r.Size
+	}
+	if r.Active != nil {
+		fields["active"] = *r.Active
+	}
+	if r.ValidFrom.Set {
+		fields["valid_from"] = r.ValidFrom
+	}
+	if r.ValidTo.Set {
+		fields["valid_to"] = r.ValidTo
+	}
+	return json.Marshal(fields)
 }
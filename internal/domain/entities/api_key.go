@@ -0,0 +1,19 @@
+package entities
+
+import "time"
+
+// APIKey authenticates a client of the API. Only a hash of the key is stored,
+// the key itself is shown once, when it is created.
+type APIKey struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+	// Prefix is the start of the key, shown to tell keys apart
+	Prefix string `db:"prefix"`
+	Hash   string `db:"key_hash"`
+	// Roles grant the permissions of the key
+	Roles []string `db:"roles"`
+	// ProductIDs restricts the key to these products, when not empty
+	ProductIDs []int      `db:"product_ids"`
+	CreatedAt  time.Time  `db:"created_at"`
+	RevokedAt  *time.Time `db:"revoked_at"`
+}
@@ -0,0 +1,17 @@
+package entities
+
+import "time"
+
+// IdempotencyKey records the outcome of a write request so that retries carrying
+// the same Idempotency-Key header can be answered without executing it again.
+// A zero StatusCode means the first request is still being processed.
+type IdempotencyKey struct {
+	Key          string    `db:"idempotency_key"`
+	Route        string    `db:"route"`
+	RequestHash  string    `db:"request_hash"`
+	StatusCode   int       `db:"status_code"`
+	ContentType  string    `db:"content_type"`
+	ResponseBody []byte    `db:"response_body"`
+	CreatedAt    time.Time `db:"created_at"`
+	ExpiresAt    time.Time `db:"expires_at"`
+}
@@ -0,0 +1,74 @@
+package entities
+
+import "time"
+
+// ObjectiveFewestItems is the optimisation objective used by the calculator:
+// ship the fewest items possible and, among those, the fewest packs.
+const ObjectiveFewestItems = "fewest_items_then_fewest_packs"
+
+type OrderStatus string
+
+const (
+	OrderStatusDraft     OrderStatus = "draft"
+	OrderStatusConfirmed OrderStatus = "confirmed"
+	OrderStatusPacked    OrderStatus = "packed"
+	OrderStatusShipped   OrderStatus = "shipped"
+	OrderStatusCancelled OrderStatus = "cancelled"
+)
+
+// orderTransitions lists the statuses an order may move to from each status.
+// Shipped and cancelled orders are final.
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusDraft:     {OrderStatusConfirmed, OrderStatusCancelled},
+	OrderStatusConfirmed: {OrderStatusPacked, OrderStatusCancelled},
+	OrderStatusPacked:    {OrderStatusShipped, OrderStatusCancelled},
+}
+
+// CanTransitionTo reports whether the lifecycle allows moving from s to next.
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+type Order struct {
+	ID            int64       `db:"id"`
+	ProductID     int         `db:"product_id"`
+	OrderQuantity int         `db:"order_quantity"`
+	TotalItems    int         `db:"total_items"`
+	TotalPacks    int         `db:"total_packs"`
+	Objective     string      `db:"objective"`
+	PackSizes     []int       `db:"pack_sizes"`
+	AsOf          time.Time   `db:"as_of"`
+	Status        OrderStatus `db:"status"`
+	CreatedAt     time.Time   `db:"created_at"`
+	ConfirmedAt   *time.Time  `db:"confirmed_at"`
+	PackedAt      *time.Time  `db:"packed_at"`
+	ShippedAt     *time.Time  `db:"shipped_at"`
+	CancelledAt   *time.Time  `db:"cancelled_at"`
+	Packs         []OrderPack
+}
+
+// SetStatus moves the order to status and records when it happened.
+// It does not check the lifecycle, see OrderStatus.CanTransitionTo.
+func (o *Order) SetStatus(status OrderStatus, at time.Time) {
+	o.Status = status
+	switch status {
+	case OrderStatusConfirmed:
+		o.ConfirmedAt = &at
+	case OrderStatusPacked:
+		o.PackedAt = &at
+	case OrderStatusShipped:
+		o.ShippedAt = &at
+	case OrderStatusCancelled:
+		o.CancelledAt = &at
+	}
+}
+
+type OrderPack struct {
+	Size  int `db:"size"`
+	Count int `db:"count"`
+}
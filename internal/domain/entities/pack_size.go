@@ -1,8 +1,14 @@
 package entities
 
+import "time"
+
 type PackSize struct {
-	ID        int64 `db:"id"`
-	ProductID int   `db:"product_id"`
-	Size      int   `db:"size"`
-	Active    bool  `db:"active"`
+	ID        int64      `db:"id"`
+	ProductID int        `db:"product_id"`
+	Size      int        `db:"size"`
+	Active    bool       `db:"active"`
+	ValidFrom *time.Time `db:"valid_from"`
+	ValidTo   *time.Time `db:"valid_to"`
+	// Version is incremented on every update and used for optimistic concurrency control.
+	Version int64 `db:"version"`
 }
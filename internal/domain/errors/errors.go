@@ -1,8 +1,71 @@
 package errors
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 var (
-	ErrInternalServer = errors.New("internal error")
-	ErrNotFound       = errors.New("resource not found")
+	ErrInternalServer        = errors.New("internal error")
+	ErrInvalidRequest        = errors.New("invalid request")
+	ErrValidation            = errors.New("validation failed")
+	ErrNotFound              = errors.New("resource not found")
+	ErrInvalidValidityPeriod = errors.New("valid_to must be after valid_from")
+	ErrConflict              = errors.New("resource was modified concurrently")
+	ErrPreconditionFailed    = errors.New("resource version does not match")
+	ErrInvalidTransition     = errors.New("invalid order status transition")
+	ErrOrderNotDraft         = errors.New("order can only be recalculated while in draft")
+	ErrInvalidCursor         = errors.New("invalid pagination cursor")
+	ErrUnauthenticated       = errors.New("authentication required")
+	ErrForbidden             = errors.New("permission denied")
+	ErrRateLimited           = errors.New("rate limit exceeded")
+	ErrQuotaExceeded         = errors.New("compute quota exceeded")
+	ErrRequestTooLarge       = errors.New("request body too large")
+
+	ErrInvalidIdempotencyKey    = errors.New("invalid idempotency key")
+	ErrIdempotencyKeyReused     = errors.New("idempotency key was already used with a different request")
+	ErrIdempotencyKeyInProgress = errors.New("a request with this idempotency key is still being processed")
 )
+
+// InvalidTransitionError is returned when an order is asked to move to a status
+// its lifecycle does not allow from the current one. It matches ErrInvalidTransition.
+type InvalidTransitionError struct {
+	OrderID int64
+	From    string
+	To      string
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("%s: order id=%d cannot move from %s to %s", ErrInvalidTransition, e.OrderID, e.From, e.To)
+}
+
+func (e *InvalidTransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// FieldError describes why the value of a request field is invalid
+type FieldError struct {
+	// Field is the name of the field in the request, e.g. order_quantity
+	Field string
+	// Rule is the rule the value breaks, e.g. required or max
+	Rule    string
+	Message string
+}
+
+// ValidationError lists the invalid fields of a request. It matches ErrValidation.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		fields = append(fields, f.Field+" "+f.Message)
+	}
+	return fmt.Sprintf("%s: %s", ErrValidation, strings.Join(fields, ", "))
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
@@ -3,12 +3,100 @@ package repositories
 import (
 	"context"
 	"order-pack-calculator/internal/domain/entities"
+	"time"
 )
 
+// UnitOfWork runs several repository calls in one transaction.
+// The calls made with the context passed to fn take part in the transaction,
+// which is committed when fn returns nil and rolled back otherwise.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 type PackSizeRepository interface {
 	Create(ctx context.Context, pack entities.PackSize) (*entities.PackSize, error)
 	Update(ctx context.Context, pack entities.PackSize) error
 	GetByID(ctx context.Context, ID int64) (*entities.PackSize, error)
-	GetAll(ctx context.Context) ([]entities.PackSize, error)
-	GetSizesByProductID(ctx context.Context, productID int64) ([]int, error)
+	// GetByIDForUpdate is GetByID locking the pack size against concurrent writes
+	// until the unit of work carried by ctx ends.
+	GetByIDForUpdate(ctx context.Context, ID int64) (*entities.PackSize, error)
+	// GetAll returns a page of the pack sizes matching the filter, in the requested order.
+	GetAll(ctx context.Context, filter PackSizeFilter) ([]entities.PackSize, error)
+	// Count returns how many pack sizes match the filter, ignoring its paging fields.
+	Count(ctx context.Context, filter PackSizeFilter) (int64, error)
+	GetSizesByProductID(ctx context.Context, productID int64, asOf time.Time) ([]int, error)
+}
+
+// PackSizeSort is a column pack sizes can be listed by. Ties are broken by id.
+type PackSizeSort string
+
+const (
+	PackSizeSortID        PackSizeSort = "id"
+	PackSizeSortProductID PackSizeSort = "product_id"
+	PackSizeSortSize      PackSizeSort = "size"
+)
+
+// PackSizeCursor is the position of the last pack size of a page:
+// the value of the sort column and the id of the pack size.
+type PackSizeCursor struct {
+	Key int64
+	ID  int64
+}
+
+// PackSizeFilter narrows down and orders the pack sizes returned by PackSizeRepository.GetAll.
+// Zero values are ignored; pack sizes are sorted by id when SortBy is empty.
+type PackSizeFilter struct {
+	ProductID  int
+	Active     *bool
+	MinSize    int
+	MaxSize    int
+	SortBy     PackSizeSort
+	Descending bool
+	// After restricts the page to the pack sizes sorted after the cursor
+	After *PackSizeCursor
+	Limit int
+}
+
+// OrderFilter narrows down the orders returned by OrderRepository.List.
+// Zero values are ignored.
+type OrderFilter struct {
+	ProductID   int
+	Status      entities.OrderStatus
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Limit       int
+	Offset      int
+}
+
+type OrderRepository interface {
+	Create(ctx context.Context, order entities.Order) (*entities.Order, error)
+	GetByID(ctx context.Context, ID int64) (*entities.Order, error)
+	List(ctx context.Context, filter OrderFilter) ([]entities.Order, error)
+	// UpdateStatus moves the order from one status to another, failing with ErrConflict
+	// when the order is no longer in the expected status.
+	UpdateStatus(ctx context.Context, ID int64, from, to entities.OrderStatus, at time.Time) error
+	// UpdateCalculation replaces the calculation of a draft order, failing with ErrConflict
+	// when the order is no longer a draft.
+	UpdateCalculation(ctx context.Context, order entities.Order) error
+}
+
+type IdempotencyKeyRepository interface {
+	// Create reserves the key, reclaiming it when the previous record has expired.
+	// It fails with ErrConflict when an unexpired record already exists.
+	Create(ctx context.Context, key entities.IdempotencyKey) error
+	GetByKey(ctx context.Context, key, route string) (*entities.IdempotencyKey, error)
+	// Complete stores the response of the request holding the key.
+	Complete(ctx context.Context, key entities.IdempotencyKey) error
+	Delete(ctx context.Context, key, route string) error
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key entities.APIKey) (*entities.APIKey, error)
+	// GetByHash returns the key with the given hash, whether it is revoked or not.
+	GetByHash(ctx context.Context, hash string) (*entities.APIKey, error)
+	// List returns every key, oldest first.
+	List(ctx context.Context) ([]entities.APIKey, error)
+	// Revoke marks the key as revoked, failing with ErrNotFound when no unrevoked key has the id.
+	Revoke(ctx context.Context, ID int64, at time.Time) error
 }
@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"order-pack-calculator/internal/domain/entities"
+	errs "order-pack-calculator/internal/domain/errors"
+	"time"
+)
+
+func NewAPIKeyRepository(db *sql.DB, dialect Dialect) APIKeyRepository {
+	return apiKeyRepository{db: sqlDB{DB: db, dialect: dialect}}
+}
+
+type apiKeyRepository struct {
+	db sqlDB
+}
+
+func (a apiKeyRepository) Create(ctx context.Context, key entities.APIKey) (*entities.APIKey, error) {
+	// Empty lists are stored as such rather than as null
+	roles, err := json.Marshal(append([]string{}, key.Roles...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode roles: %w", err)
+	}
+	productIDs, err := json.Marshal(append([]int{}, key.ProductIDs...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode product ids: %w", err)
+	}
+
+	query := `
+	INSERT INTO api_keys (name, prefix, key_hash, roles, product_ids, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING id
+`
+	err = conn(ctx, a.db).QueryRowContext(ctx, query, key.Name, key.Prefix, key.Hash, string(roles), string(productIDs), key.CreatedAt.UTC()).Scan(&key.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert api key name=%s: %w", key.Name, err)
+	}
+	return &key, nil
+}
+
+func (a apiKeyRepository) GetByHash(ctx context.Context, hash string) (*entities.APIKey, error) {
+	query := `
+	SELECT id, name, prefix, key_hash, roles, product_ids, created_at, revoked_at
+	FROM api_keys
+	WHERE key_hash = $1
+`
+	key, err := scanAPIKey(conn(ctx, a.db).QueryRowContext(ctx, query, hash))
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, fmt.Errorf("%w: api key", errs.ErrNotFound)
+		default:
+			return nil, fmt.Errorf("failed to query api key: %w", err)
+		}
+	}
+	return key, nil
+}
+
+func (a apiKeyRepository) List(ctx context.Context) ([]entities.APIKey, error) {
+	query := `
+	SELECT id, name, prefix, key_hash, roles, product_ids, created_at, revoked_at
+	FROM api_keys
+	ORDER BY id
+`
+	rows, err := conn(ctx, a.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query api keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []entities.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api key row: %w", err)
+		}
+		keys = append(keys, *key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate api key rows: %w", err)
+	}
+	return keys, nil
+}
+
+func (a apiKeyRepository) Revoke(ctx context.Context, ID int64, at time.Time) error {
+	query := `
+		UPDATE api_keys
+		SET revoked_at = $1
+		WHERE id = $2 AND revoked_at IS NULL
+	`
+	rs, err := conn(ctx, a.db).ExecContext(ctx, query, at.UTC(), ID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key id=%d: %w", ID, err)
+	}
+	rowsAffected, err := rs.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key id=%d: %w", ID, err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: api key id=%d", errs.ErrNotFound, ID)
+	}
+	return nil
+}
+
+// scanAPIKey reads an api key row, decoding its roles and products
+func scanAPIKey(row interface{ Scan(dest ...any) error }) (*entities.APIKey, error) {
+	var (
+		key        entities.APIKey
+		roles      []byte
+		productIDs []byte
+	)
+	if err := row.Scan(&key.ID, &key.Name, &key.Prefix, &key.Hash, &roles, &productIDs, &key.CreatedAt, &key.RevokedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(roles, &key.Roles); err != nil {
+		return nil, fmt.Errorf("failed to decode roles of api key id=%d: %w", key.ID, err)
+	}
+	if err := json.Unmarshal(productIDs, &key.ProductIDs); err != nil {
+		return nil, fmt.Errorf("failed to decode product ids of api key id=%d: %w", key.ID, err)
+	}
+	return &key, nil
+}
@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"order-pack-calculator/internal/domain/entities"
+	errs "order-pack-calculator/internal/domain/errors"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyCreate(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	repo := NewAPIKeyRepository(db, DialectPostgres)
+
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	key := entities.APIKey{Name: "ci", Prefix: "opc_abcd", Hash: "hash", Roles: []string{"calculator"}, CreatedAt: now}
+	query := regexp.QuoteMeta(`INSERT INTO api_keys (name, prefix, key_hash, roles, product_ids, created_at)`)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs("ci", "opc_abcd", "hash", `["calculator"]`, `[]`, now).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+		res, err := repo.Create(context.Background(), key)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), res.ID)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WillReturnError(errors.New("insert error"))
+
+		_, err := repo.Create(context.Background(), key)
+		assert.Error(t, err)
+	})
+}
+
+func TestAPIKeyGetByHash(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	repo := NewAPIKeyRepository(db, DialectPostgres)
+
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	columns := []string{"id", "name", "prefix", "key_hash", "roles", "product_ids", "created_at", "revoked_at"}
+	query := regexp.QuoteMeta(`SELECT id, name, prefix, key_hash, roles, product_ids, created_at, revoked_at
+	FROM api_keys
+	WHERE key_hash = $1`)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs("hash").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "ci", "opc_abcd", "hash", `["calculator"]`, `[1]`, now, nil))
+
+		res, err := repo.GetByHash(context.Background(), "hash")
+		assert.NoError(t, err)
+		assert.Equal(t, entities.APIKey{ID: 1, Name: "ci", Prefix: "opc_abcd", Hash: "hash", Roles: []string{"calculator"}, ProductIDs: []int{1}, CreatedAt: now}, *res)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		_, err := repo.GetByHash(context.Background(), "other")
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+	})
+}
+
+func TestAPIKeyList(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	repo := NewAPIKeyRepository(db, DialectPostgres)
+
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	query := regexp.QuoteMeta(`SELECT id, name, prefix, key_hash, roles, product_ids, created_at, revoked_at
+	FROM api_keys
+	ORDER BY id`)
+
+	mock.ExpectQuery(query).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "prefix", "key_hash", "roles", "product_ids", "created_at", "revoked_at"}).
+			AddRow(1, "ci", "opc_abcd", "hash1", `["admin"]`, `[]`, now, now).
+			AddRow(2, "web", "opc_efgh", "hash2", `["calculator"]`, `[]`, now, nil))
+
+	res, err := repo.List(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, res, 2)
+	assert.Equal(t, now, *res[0].RevokedAt)
+	assert.Nil(t, res[1].RevokedAt)
+}
+
+func TestAPIKeyRevoke(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	repo := NewAPIKeyRepository(db, DialectPostgres)
+
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	query := regexp.QuoteMeta(`UPDATE api_keys
+		SET revoked_at = $1
+		WHERE id = $2 AND revoked_at IS NULL`)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec(query).
+			WithArgs(now, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.Revoke(context.Background(), 1, now))
+	})
+
+	t.Run("not found or already revoked", func(t *testing.T) {
+		mock.ExpectExec(query).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.ErrorIs(t, repo.Revoke(context.Background(), 1, now), errs.ErrNotFound)
+	})
+}
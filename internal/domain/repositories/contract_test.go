@@ -0,0 +1,114 @@
+package repositories_test
+
+import (
+	"context"
+	"database/sql"
+	"io/fs"
+	"path/filepath"
+	"testing"
+
+	"order-pack-calculator/internal/database"
+	"order-pack-calculator/internal/database/migrate"
+	"order-pack-calculator/internal/domain/repositories"
+	"order-pack-calculator/internal/domain/repositories/repositorytest"
+	"order-pack-calculator/migrations"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+)
+
+// The contract suites run against a real database of every SQL backend.
+// Postgres needs Docker and is skipped when it is not available.
+
+// backends returns a freshly migrated database per backend
+var backends = map[string]func(t *testing.T) *sql.DB{
+	"sqlite":   openSQLite,
+	"postgres": openPostgres,
+}
+
+func TestPackSizeRepositoryContract(t *testing.T) {
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			repositorytest.TestPackSizeRepository(t, repositories.NewPackSizeRepository(open(t), repositories.DialectSQLite))
+		})
+	}
+}
+
+func TestOrderRepositoryContract(t *testing.T) {
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			repositorytest.TestOrderRepository(t, repositories.NewOrderRepository(open(t), repositories.DialectSQLite))
+		})
+	}
+}
+
+func TestIdempotencyKeyRepositoryContract(t *testing.T) {
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			repositorytest.TestIdempotencyKeyRepository(t, repositories.NewIdempotencyKeyRepository(open(t), repositories.DialectSQLite))
+		})
+	}
+}
+
+func TestAPIKeyRepositoryContract(t *testing.T) {
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			repositorytest.TestAPIKeyRepository(t, repositories.NewAPIKeyRepository(open(t), repositories.DialectSQLite))
+		})
+	}
+}
+
+func TestUnitOfWorkContract(t *testing.T) {
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			db := open(t)
+			repositorytest.TestUnitOfWork(t, repositories.NewUnitOfWork(db, repositories.DialectSQLite), repositories.NewPackSizeRepository(db, repositories.DialectSQLite), repositories.NewOrderRepository(db, repositories.DialectSQLite))
+		})
+	}
+}
+
+func openSQLite(t *testing.T) *sql.DB {
+	dbService, err := database.NewSQLite(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { dbService.Close() })
+
+	applyMigrations(t, dbService.GetDB(), migrations.SQLite())
+	return dbService.GetDB()
+}
+
+func openPostgres(t *testing.T) *sql.DB {
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	ctx := context.Background()
+	container, err := postgres.Run(ctx, "postgres:14.1-alpine", postgres.BasicWaitStrategies())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { container.Terminate(context.Background()) })
+
+	connStr, err := container.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open("pgx", connStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	applyMigrations(t, db, migrations.Postgres())
+	return db
+}
+
+// applyMigrations migrates the database of a backend to the latest version
+func applyMigrations(t *testing.T, db *sql.DB, schema fs.FS) {
+	migrator, err := migrate.New(db, schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
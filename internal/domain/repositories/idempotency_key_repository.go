@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"order-pack-calculator/internal/domain/entities"
+	errs "order-pack-calculator/internal/domain/errors"
+	"time"
+)
+
+func NewIdempotencyKeyRepository(db *sql.DB, dialect Dialect) IdempotencyKeyRepository {
+	return idempotencyKeyRepository{db: sqlDB{DB: db, dialect: dialect}}
+}
+
+type idempotencyKeyRepository struct {
+	db sqlDB
+}
+
+func (i idempotencyKeyRepository) Create(ctx context.Context, key entities.IdempotencyKey) error {
+	query := `
+	INSERT INTO idempotency_keys (idempotency_key, route, request_hash, created_at, expires_at)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (idempotency_key, route) DO UPDATE
+	SET request_hash = EXCLUDED.request_hash, status_code = NULL, content_type = NULL, response_body = NULL,
+		created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at
+	WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+`
+	rs, err := conn(ctx, i.db).ExecContext(ctx, query, key.Key, key.Route, key.RequestHash, key.CreatedAt.UTC(), key.ExpiresAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to insert idempotency key=%s: %w", key.Key, err)
+	}
+	rowsAffected, err := rs.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to insert idempotency key=%s: %w", key.Key, err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: idempotency key=%s", errs.ErrConflict, key.Key)
+	}
+
+	return nil
+}
+
+func (i idempotencyKeyRepository) GetByKey(ctx context.Context, key, route string) (*entities.IdempotencyKey, error) {
+	query := `
+	SELECT idempotency_key, route, request_hash, COALESCE(status_code, 0), COALESCE(content_type, ''), response_body, created_at, expires_at
+	FROM idempotency_keys
+	WHERE idempotency_key = $1 AND route = $2
+`
+	var record entities.IdempotencyKey
+	err := conn(ctx, i.db).QueryRowContext(ctx, query, key, route).Scan(&record.Key, &record.Route, &record.RequestHash,
+		&record.StatusCode, &record.ContentType, &record.ResponseBody, &record.CreatedAt, &record.ExpiresAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, errs.ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &record, nil
+}
+
+func (i idempotencyKeyRepository) Complete(ctx context.Context, key entities.IdempotencyKey) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $1, content_type = $2, response_body = $3
+		WHERE idempotency_key = $4 AND route = $5
+	`
+	rs, err := conn(ctx, i.db).ExecContext(ctx, query, key.StatusCode, key.ContentType, key.ResponseBody, key.Key, key.Route)
+	if err != nil {
+		return fmt.Errorf("failed to update idempotency key=%s: %w", key.Key, err)
+	}
+	rowsAffected, err := rs.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update idempotency key=%s: %w", key.Key, err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: idempotency key=%s", errs.ErrNotFound, key.Key)
+	}
+
+	return nil
+}
+
+func (i idempotencyKeyRepository) Delete(ctx context.Context, key, route string) error {
+	query := `
+	DELETE FROM idempotency_keys
+	WHERE idempotency_key = $1 AND route = $2
+`
+	if _, err := conn(ctx, i.db).ExecContext(ctx, query, key, route); err != nil {
+		return fmt.Errorf("failed to delete idempotency key=%s: %w", key, err)
+	}
+	return nil
+}
+
+// DeleteExpired removes the records that expired before the given instant and returns how many were removed.
+func (i idempotencyKeyRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+	DELETE FROM idempotency_keys
+	WHERE expires_at <= $1
+`
+	rs, err := conn(ctx, i.db).ExecContext(ctx, query, before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+	return rs.RowsAffected()
+}
@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"order-pack-calculator/internal/domain/entities"
+	errs "order-pack-calculator/internal/domain/errors"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyKeyCreate(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	repo := NewIdempotencyKeyRepository(db, DialectPostgres)
+
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	key := entities.IdempotencyKey{Key: "abc", Route: "POST /api/v1/packsizes/", RequestHash: "hash", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	query := regexp.QuoteMeta(`INSERT INTO idempotency_keys (idempotency_key, route, request_hash, created_at, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (idempotency_key, route) DO UPDATE`)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec(query).
+			WithArgs("abc", "POST /api/v1/packsizes/", "hash", now, now.Add(time.Hour)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.Create(context.Background(), key)
+		assert.NoError(t, err)
+	})
+
+	t.Run("key already in use", func(t *testing.T) {
+		mock.ExpectExec(query).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.Create(context.Background(), key)
+		assert.ErrorIs(t, err, errs.ErrConflict)
+	})
+
+	t.Run("exec error", func(t *testing.T) {
+		mock.ExpectExec(query).
+			WillReturnError(errors.New("insert error"))
+
+		err := repo.Create(context.Background(), key)
+		assert.Error(t, err)
+	})
+}
+
+func TestIdempotencyKeyGetByKey(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	repo := NewIdempotencyKeyRepository(db, DialectPostgres)
+
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	query := regexp.QuoteMeta(`SELECT idempotency_key, route, request_hash, COALESCE(status_code, 0), COALESCE(content_type, ''), response_body, created_at, expires_at
+FROM idempotency_keys
+WHERE idempotency_key = $1 AND route = $2`)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs("abc", "POST /api/v1/packsizes/").
+			WillReturnRows(sqlmock.NewRows([]string{"idempotency_key", "route", "request_hash", "status_code", "content_type", "response_body", "created_at", "expires_at"}).
+				AddRow("abc", "POST /api/v1/packsizes/", "hash", 200, "application/json", []byte(`{"id":1}`), now, now.Add(time.Hour)))
+
+		res, err := repo.GetByKey(context.Background(), "abc", "POST /api/v1/packsizes/")
+		assert.NoError(t, err)
+		assert.Equal(t, 200, res.StatusCode)
+		assert.Equal(t, []byte(`{"id":1}`), res.ResponseBody)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs("missing", "POST /api/v1/packsizes/").
+			WillReturnError(sql.ErrNoRows)
+
+		_, err := repo.GetByKey(context.Background(), "missing", "POST /api/v1/packsizes/")
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+	})
+}
+
+func TestIdempotencyKeyComplete(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	repo := NewIdempotencyKeyRepository(db, DialectPostgres)
+
+	query := regexp.QuoteMeta("UPDATE idempotency_keys SET status_code = $1, content_type = $2, response_body = $3 WHERE idempotency_key = $4 AND route = $5")
+	key := entities.IdempotencyKey{Key: "abc", Route: "POST /api/v1/packsizes/", StatusCode: 200, ContentType: "application/json", ResponseBody: []byte(`{}`)}
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec(query).
+			WithArgs(200, "application/json", []byte(`{}`), "abc", "POST /api/v1/packsizes/").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.Complete(context.Background(), key)
+		assert.NoError(t, err)
+	})
+
+	t.Run("no rows affected", func(t *testing.T) {
+		mock.ExpectExec(query).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.Complete(context.Background(), key)
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+	})
+}
+
+func TestIdempotencyKeyDeleteExpired(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	repo := NewIdempotencyKeyRepository(db, DialectPostgres)
+
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM idempotency_keys WHERE expires_at <= $1")).
+		WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	deleted, err := repo.DeleteExpired(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+}
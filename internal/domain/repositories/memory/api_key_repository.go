@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"order-pack-calculator/internal/domain/entities"
+	errs "order-pack-calculator/internal/domain/errors"
+	"order-pack-calculator/internal/domain/repositories"
+	"slices"
+	"time"
+)
+
+// NewAPIKeyRepository returns an empty APIKeyRepository kept in memory.
+// It is safe for concurrent use and behaves like the Postgres implementation.
+func NewAPIKeyRepository() repositories.APIKeyRepository {
+	return &apiKeyRepository{keys: map[int64]entities.APIKey{}}
+}
+
+type apiKeyRepository struct {
+	guard
+	lastID int64
+	keys   map[int64]entities.APIKey
+}
+
+func (a *apiKeyRepository) Create(ctx context.Context, key entities.APIKey) (*entities.APIKey, error) {
+	defer a.lock(ctx)()
+
+	a.lastID++
+	key.ID = a.lastID
+	key.RevokedAt = nil
+	a.keys[key.ID] = cloneAPIKey(key)
+	return &key, nil
+}
+
+func (a *apiKeyRepository) GetByHash(ctx context.Context, hash string) (*entities.APIKey, error) {
+	defer a.rlock(ctx)()
+
+	for _, key := range a.keys {
+		if key.Hash == hash {
+			key = cloneAPIKey(key)
+			return &key, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: api key", errs.ErrNotFound)
+}
+
+func (a *apiKeyRepository) List(ctx context.Context) ([]entities.APIKey, error) {
+	defer a.rlock(ctx)()
+
+	keys := make([]entities.APIKey, 0, len(a.keys))
+	for _, key := range a.keys {
+		keys = append(keys, cloneAPIKey(key))
+	}
+	slices.SortFunc(keys, func(x, y entities.APIKey) int { return cmp.Compare(x.ID, y.ID) })
+	return keys, nil
+}
+
+func (a *apiKeyRepository) Revoke(ctx context.Context, ID int64, at time.Time) error {
+	defer a.lock(ctx)()
+
+	key, ok := a.keys[ID]
+	if !ok || key.RevokedAt != nil {
+		return fmt.Errorf("%w: api key id=%d", errs.ErrNotFound, ID)
+	}
+	key.RevokedAt = &at
+	a.keys[ID] = key
+	return nil
+}
+
+// snapshot copies the state of the repository and returns a function restoring it. The caller must hold the lock.
+func (a *apiKeyRepository) snapshot() func() {
+	lastID, keys := a.lastID, maps.Clone(a.keys)
+	return func() { a.lastID, a.keys = lastID, keys }
+}
+
+// cloneAPIKey copies the key so callers do not share its lists with the repository
+func cloneAPIKey(key entities.APIKey) entities.APIKey {
+	key.Roles = slices.Clone(key.Roles)
+	key.ProductIDs = slices.Clone(key.ProductIDs)
+	return key
+}
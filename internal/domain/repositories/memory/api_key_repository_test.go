@@ -0,0 +1,42 @@
+package memory
+
+import (
+	"context"
+	"order-pack-calculator/internal/domain/entities"
+	errs "order-pack-calculator/internal/domain/errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyRepository(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	t.Run("create and revoke", func(t *testing.T) {
+		repo := NewAPIKeyRepository()
+
+		created, err := repo.Create(ctx, entities.APIKey{Name: "ci", Prefix: "opc_abcd", Hash: "hash", CreatedAt: now})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), created.ID)
+
+		assert.NoError(t, repo.Revoke(ctx, created.ID, now))
+		res, err := repo.GetByHash(ctx, "hash")
+		assert.NoError(t, err)
+		assert.Equal(t, now, *res.RevokedAt)
+
+		assert.ErrorIs(t, repo.Revoke(ctx, created.ID, now), errs.ErrNotFound)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		repo := NewAPIKeyRepository()
+		repo.Create(ctx, entities.APIKey{Name: "a", Hash: "a"})
+		repo.Create(ctx, entities.APIKey{Name: "b", Hash: "b"})
+
+		keys, err := repo.List(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, "a", keys[0].Name)
+		assert.Equal(t, "b", keys[1].Name)
+	})
+}
@@ -0,0 +1,29 @@
+package memory_test
+
+import (
+	"order-pack-calculator/internal/domain/repositories/memory"
+	"order-pack-calculator/internal/domain/repositories/repositorytest"
+	"testing"
+)
+
+func TestPackSizeRepositoryContract(t *testing.T) {
+	repositorytest.TestPackSizeRepository(t, memory.NewPackSizeRepository())
+}
+
+func TestOrderRepositoryContract(t *testing.T) {
+	repositorytest.TestOrderRepository(t, memory.NewOrderRepository())
+}
+
+func TestIdempotencyKeyRepositoryContract(t *testing.T) {
+	repositorytest.TestIdempotencyKeyRepository(t, memory.NewIdempotencyKeyRepository())
+}
+
+func TestAPIKeyRepositoryContract(t *testing.T) {
+	repositorytest.TestAPIKeyRepository(t, memory.NewAPIKeyRepository())
+}
+
+func TestUnitOfWorkContract(t *testing.T) {
+	packSizes := memory.NewPackSizeRepository()
+	orders := memory.NewOrderRepository()
+	repositorytest.TestUnitOfWork(t, memory.NewUnitOfWork(packSizes, orders), packSizes, orders)
+}
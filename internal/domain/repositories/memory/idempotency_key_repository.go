@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"order-pack-calculator/internal/domain/entities"
+	errs "order-pack-calculator/internal/domain/errors"
+	"order-pack-calculator/internal/domain/repositories"
+	"slices"
+	"time"
+)
+
+// NewIdempotencyKeyRepository returns an empty IdempotencyKeyRepository kept in memory.
+// It is safe for concurrent use and behaves like the Postgres implementation.
+func NewIdempotencyKeyRepository() repositories.IdempotencyKeyRepository {
+	return &idempotencyKeyRepository{keys: map[idempotencyKeyID]entities.IdempotencyKey{}}
+}
+
+type idempotencyKeyID struct {
+	key   string
+	route string
+}
+
+type idempotencyKeyRepository struct {
+	guard
+	keys map[idempotencyKeyID]entities.IdempotencyKey
+}
+
+func (i *idempotencyKeyRepository) Create(ctx context.Context, key entities.IdempotencyKey) error {
+	defer i.lock(ctx)()
+
+	id := idempotencyKeyID{key: key.Key, route: key.Route}
+	if current, ok := i.keys[id]; ok && current.ExpiresAt.After(key.CreatedAt) {
+		return fmt.Errorf("%w: idempotency key=%s", errs.ErrConflict, key.Key)
+	}
+
+	key.StatusCode = 0
+	key.ContentType = ""
+	key.ResponseBody = nil
+	i.keys[id] = key
+	return nil
+}
+
+func (i *idempotencyKeyRepository) GetByKey(ctx context.Context, key, route string) (*entities.IdempotencyKey, error) {
+	defer i.lock(ctx)()
+
+	record, ok := i.keys[idempotencyKeyID{key: key, route: route}]
+	if !ok {
+		return nil, errs.ErrNotFound
+	}
+	record.ResponseBody = slices.Clone(record.ResponseBody)
+	return &record, nil
+}
+
+func (i *idempotencyKeyRepository) Complete(ctx context.Context, key entities.IdempotencyKey) error {
+	defer i.lock(ctx)()
+
+	id := idempotencyKeyID{key: key.Key, route: key.Route}
+	record, ok := i.keys[id]
+	if !ok {
+		return fmt.Errorf("%w: idempotency key=%s", errs.ErrNotFound, key.Key)
+	}
+	record.StatusCode = key.StatusCode
+	record.ContentType = key.ContentType
+	record.ResponseBody = slices.Clone(key.ResponseBody)
+	i.keys[id] = record
+	return nil
+}
+
+func (i *idempotencyKeyRepository) Delete(ctx context.Context, key, route string) error {
+	defer i.lock(ctx)()
+
+	delete(i.keys, idempotencyKeyID{key: key, route: route})
+	return nil
+}
+
+// DeleteExpired removes the records that expired before the given instant and returns how many were removed.
+func (i *idempotencyKeyRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	defer i.lock(ctx)()
+
+	var removed int64
+	for id, record := range i.keys {
+		if !record.ExpiresAt.After(before) {
+			delete(i.keys, id)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// snapshot copies the state of the repository and returns a function restoring it. The caller must hold the lock.
+func (i *idempotencyKeyRepository) snapshot() func() {
+	keys := maps.Clone(i.keys)
+	return func() { i.keys = keys }
+}
@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"context"
+	"order-pack-calculator/internal/domain/entities"
+	errs "order-pack-calculator/internal/domain/errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyKeyRepository(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	key := entities.IdempotencyKey{Key: "abc", Route: "POST /api/v1/packsizes/", RequestHash: "hash", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+
+	t.Run("create rejects unexpired key", func(t *testing.T) {
+		repo := NewIdempotencyKeyRepository()
+
+		assert.NoError(t, repo.Create(ctx, key))
+		assert.ErrorIs(t, repo.Create(ctx, key), errs.ErrConflict)
+	})
+
+	t.Run("create reclaims expired key", func(t *testing.T) {
+		repo := NewIdempotencyKeyRepository()
+		repo.Create(ctx, key)
+
+		reclaimed := key
+		reclaimed.CreatedAt = key.ExpiresAt
+		reclaimed.ExpiresAt = key.ExpiresAt.Add(time.Hour)
+		assert.NoError(t, repo.Create(ctx, reclaimed))
+	})
+
+	t.Run("complete and get", func(t *testing.T) {
+		repo := NewIdempotencyKeyRepository()
+		repo.Create(ctx, key)
+
+		completed := key
+		completed.StatusCode = 200
+		completed.ContentType = "application/json"
+		completed.ResponseBody = []byte(`{}`)
+		assert.NoError(t, repo.Complete(ctx, completed))
+
+		res, err := repo.GetByKey(ctx, key.Key, key.Route)
+		assert.NoError(t, err)
+		assert.Equal(t, completed, *res)
+
+		_, err = repo.GetByKey(ctx, "other", key.Route)
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("delete expired", func(t *testing.T) {
+		repo := NewIdempotencyKeyRepository()
+		repo.Create(ctx, key)
+		other := key
+		other.Key = "def"
+		other.ExpiresAt = now.Add(2 * time.Hour)
+		repo.Create(ctx, other)
+
+		removed, err := repo.DeleteExpired(ctx, key.ExpiresAt)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), removed)
+	})
+}
@@ -0,0 +1,135 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"order-pack-calculator/internal/domain/entities"
+	errs "order-pack-calculator/internal/domain/errors"
+	"order-pack-calculator/internal/domain/repositories"
+	"slices"
+	"time"
+)
+
+// NewOrderRepository returns an empty OrderRepository kept in memory.
+// It is safe for concurrent use and behaves like the Postgres implementation.
+func NewOrderRepository() repositories.OrderRepository {
+	return &orderRepository{orders: map[int64]entities.Order{}}
+}
+
+type orderRepository struct {
+	guard
+	lastID int64
+	orders map[int64]entities.Order
+}
+
+func (o *orderRepository) Create(ctx context.Context, order entities.Order) (*entities.Order, error) {
+	defer o.lock(ctx)()
+
+	o.lastID++
+	order.ID = o.lastID
+	order.Status = entities.OrderStatusDraft
+	order.CreatedAt = time.Now()
+	order = cloneOrder(order)
+	o.orders[order.ID] = order
+
+	created := cloneOrder(order)
+	return &created, nil
+}
+
+func (o *orderRepository) GetByID(ctx context.Context, ID int64) (*entities.Order, error) {
+	defer o.rlock(ctx)()
+
+	order, ok := o.orders[ID]
+	if !ok {
+		return nil, fmt.Errorf("%w: order id=%d", errs.ErrNotFound, ID)
+	}
+	order = cloneOrder(order)
+	return &order, nil
+}
+
+// List returns the most recent orders first.
+func (o *orderRepository) List(ctx context.Context, filter repositories.OrderFilter) ([]entities.Order, error) {
+	defer o.rlock(ctx)()
+
+	var orders []entities.Order
+	for _, order := range o.orders {
+		if filter.ProductID != 0 && order.ProductID != filter.ProductID {
+			continue
+		}
+		if filter.Status != "" && order.Status != filter.Status {
+			continue
+		}
+		if filter.CreatedFrom != nil && order.CreatedAt.Before(*filter.CreatedFrom) {
+			continue
+		}
+		if filter.CreatedTo != nil && !order.CreatedAt.Before(*filter.CreatedTo) {
+			continue
+		}
+		orders = append(orders, cloneOrder(order))
+	}
+	slices.SortFunc(orders, func(a, b entities.Order) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+
+	if filter.Offset >= len(orders) {
+		return nil, nil
+	}
+	orders = orders[filter.Offset:]
+	if filter.Limit > 0 && len(orders) > filter.Limit {
+		orders = orders[:filter.Limit]
+	}
+	return orders, nil
+}
+
+func (o *orderRepository) UpdateStatus(ctx context.Context, ID int64, from, to entities.OrderStatus, at time.Time) error {
+	if to == entities.OrderStatusDraft {
+		return fmt.Errorf("failed to update order id=%d: unsupported status %s", ID, to)
+	}
+
+	defer o.lock(ctx)()
+
+	order, ok := o.orders[ID]
+	if !ok || order.Status != from {
+		return fmt.Errorf("%w: order id=%d is no longer %s", errs.ErrConflict, ID, from)
+	}
+	order.SetStatus(to, at)
+	o.orders[ID] = order
+	return nil
+}
+
+func (o *orderRepository) UpdateCalculation(ctx context.Context, order entities.Order) error {
+	defer o.lock(ctx)()
+
+	current, ok := o.orders[order.ID]
+	if !ok || current.Status != entities.OrderStatusDraft {
+		return fmt.Errorf("%w: order id=%d is no longer a draft", errs.ErrConflict, order.ID)
+	}
+
+	current.OrderQuantity = order.OrderQuantity
+	current.TotalItems = order.TotalItems
+	current.TotalPacks = order.TotalPacks
+	current.Objective = order.Objective
+	current.PackSizes = order.PackSizes
+	current.AsOf = order.AsOf
+	current.Packs = order.Packs
+	o.orders[order.ID] = cloneOrder(current)
+	return nil
+}
+
+// cloneOrder copies the slices of the order so callers cannot modify the stored one.
+// Packs are sorted by size, largest first, as the Postgres implementation returns them.
+func cloneOrder(order entities.Order) entities.Order {
+	order.PackSizes = slices.Clone(order.PackSizes)
+	order.Packs = slices.Clone(order.Packs)
+	slices.SortFunc(order.Packs, func(a, b entities.OrderPack) int { return cmp.Compare(b.Size, a.Size) })
+	return order
+}
+
+// snapshot copies the state of the repository and returns a function restoring it. The caller must hold the lock.
+// Stored orders are replaced rather than modified, so copying the map is enough.
+func (o *orderRepository) snapshot() func() {
+	lastID, orders := o.lastID, maps.Clone(o.orders)
+	return func() { o.lastID, o.orders = lastID, orders }
+}
@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"context"
+	"order-pack-calculator/internal/domain/entities"
+	errs "order-pack-calculator/internal/domain/errors"
+	"order-pack-calculator/internal/domain/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderRepository(t *testing.T) {
+	ctx := context.Background()
+	newOrder := func(productID int) entities.Order {
+		return entities.Order{
+			ProductID:     productID,
+			OrderQuantity: 12,
+			TotalItems:    15,
+			TotalPacks:    2,
+			Objective:     entities.ObjectiveFewestItems,
+			PackSizes:     []int{5, 10},
+			Packs:         []entities.OrderPack{{Size: 5, Count: 1}, {Size: 10, Count: 1}},
+		}
+	}
+
+	t.Run("create and get by id", func(t *testing.T) {
+		repo := NewOrderRepository()
+
+		created, err := repo.Create(ctx, newOrder(1))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), created.ID)
+		assert.Equal(t, entities.OrderStatusDraft, created.Status)
+
+		res, err := repo.GetByID(ctx, created.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, []entities.OrderPack{{Size: 10, Count: 1}, {Size: 5, Count: 1}}, res.Packs)
+
+		_, err = repo.GetByID(ctx, 99)
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("list most recent first", func(t *testing.T) {
+		repo := NewOrderRepository()
+		repo.Create(ctx, newOrder(1))
+		repo.Create(ctx, newOrder(2))
+		repo.Create(ctx, newOrder(1))
+
+		res, err := repo.List(ctx, repositories.OrderFilter{ProductID: 1, Limit: 10})
+		assert.NoError(t, err)
+		assert.Len(t, res, 2)
+		assert.Equal(t, int64(3), res[0].ID)
+
+		res, err = repo.List(ctx, repositories.OrderFilter{Limit: 1, Offset: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), res[0].ID)
+	})
+
+	t.Run("update status", func(t *testing.T) {
+		repo := NewOrderRepository()
+		created, _ := repo.Create(ctx, newOrder(1))
+		at := time.Now()
+
+		assert.NoError(t, repo.UpdateStatus(ctx, created.ID, entities.OrderStatusDraft, entities.OrderStatusConfirmed, at))
+		res, _ := repo.GetByID(ctx, created.ID)
+		assert.Equal(t, entities.OrderStatusConfirmed, res.Status)
+		assert.Equal(t, &at, res.ConfirmedAt)
+
+		err := repo.UpdateStatus(ctx, created.ID, entities.OrderStatusDraft, entities.OrderStatusCancelled, at)
+		assert.ErrorIs(t, err, errs.ErrConflict)
+	})
+
+	t.Run("update calculation of a draft only", func(t *testing.T) {
+		repo := NewOrderRepository()
+		created, _ := repo.Create(ctx, newOrder(1))
+
+		created.OrderQuantity = 20
+		created.Packs = []entities.OrderPack{{Size: 10, Count: 2}}
+		assert.NoError(t, repo.UpdateCalculation(ctx, *created))
+		res, _ := repo.GetByID(ctx, created.ID)
+		assert.Equal(t, 20, res.OrderQuantity)
+		assert.Equal(t, []entities.OrderPack{{Size: 10, Count: 2}}, res.Packs)
+
+		repo.UpdateStatus(ctx, created.ID, entities.OrderStatusDraft, entities.OrderStatusConfirmed, time.Now())
+		err := repo.UpdateCalculation(ctx, *created)
+		assert.ErrorIs(t, err, errs.ErrConflict)
+	})
+}
@@ -0,0 +1,179 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"order-pack-calculator/internal/domain/entities"
+	errs "order-pack-calculator/internal/domain/errors"
+	"order-pack-calculator/internal/domain/repositories"
+	"slices"
+	"time"
+)
+
+// NewPackSizeRepository returns an empty PackSizeRepository kept in memory.
+// It is safe for concurrent use and behaves like the Postgres implementation.
+func NewPackSizeRepository() repositories.PackSizeRepository {
+	return &packSizeRepository{packSizes: map[int64]entities.PackSize{}}
+}
+
+type packSizeRepository struct {
+	guard
+	lastID    int64
+	packSizes map[int64]entities.PackSize
+}
+
+func (p *packSizeRepository) Create(ctx context.Context, pack entities.PackSize) (*entities.PackSize, error) {
+	defer p.lock(ctx)()
+
+	p.lastID++
+	pack.ID = p.lastID
+	pack.Active = true
+	pack.Version = 1
+	p.packSizes[pack.ID] = pack
+	return &pack, nil
+}
+
+// Update writes the pack size only if the stored version still matches pack.Version,
+// incrementing the version. It fails with ErrPreconditionFailed when the version is stale.
+func (p *packSizeRepository) Update(ctx context.Context, pack entities.PackSize) error {
+	defer p.lock(ctx)()
+
+	current, ok := p.packSizes[pack.ID]
+	if !ok {
+		return fmt.Errorf("%w: id=%d", errs.ErrNotFound, pack.ID)
+	}
+	if current.Version != pack.Version {
+		return fmt.Errorf("%w: pack size id=%d is at version %d, not %d", errs.ErrPreconditionFailed, pack.ID, current.Version, pack.Version)
+	}
+
+	// The product of a pack size never changes
+	pack.ProductID = current.ProductID
+	pack.Version++
+	p.packSizes[pack.ID] = pack
+	return nil
+}
+
+func (p *packSizeRepository) GetByID(ctx context.Context, ID int64) (*entities.PackSize, error) {
+	defer p.rlock(ctx)()
+
+	pack, ok := p.packSizes[ID]
+	if !ok {
+		return nil, errs.ErrNotFound
+	}
+	return &pack, nil
+}
+
+// GetByIDForUpdate behaves like GetByID. Within a unit of work the whole repository is already held
+// exclusively, so no other caller can change the pack size before the unit ends.
+func (p *packSizeRepository) GetByIDForUpdate(ctx context.Context, ID int64) (*entities.PackSize, error) {
+	return p.GetByID(ctx, ID)
+}
+
+func (p *packSizeRepository) GetAll(ctx context.Context, filter repositories.PackSizeFilter) ([]entities.PackSize, error) {
+	key, ok := packSizeSortKeys[filter.SortBy]
+	if !ok {
+		return nil, fmt.Errorf("failed to query pack sizes: unsupported sort %s", filter.SortBy)
+	}
+
+	// position compares a pack size with the cursor, negative meaning it sorts before the cursor
+	position := func(pack entities.PackSize, after repositories.PackSizeCursor) int {
+		order := cmp.Or(cmp.Compare(key(pack), after.Key), cmp.Compare(pack.ID, after.ID))
+		if filter.Descending {
+			return -order
+		}
+		return order
+	}
+
+	defer p.rlock(ctx)()
+
+	packSizes := p.matching(filter)
+	slices.SortFunc(packSizes, func(a, b entities.PackSize) int {
+		return position(a, repositories.PackSizeCursor{Key: key(b), ID: b.ID})
+	})
+
+	if filter.After != nil {
+		start := slices.IndexFunc(packSizes, func(pack entities.PackSize) bool { return position(pack, *filter.After) > 0 })
+		if start < 0 {
+			start = len(packSizes)
+		}
+		packSizes = packSizes[start:]
+	}
+	if filter.Limit > 0 && len(packSizes) > filter.Limit {
+		packSizes = packSizes[:filter.Limit]
+	}
+	return packSizes, nil
+}
+
+func (p *packSizeRepository) Count(ctx context.Context, filter repositories.PackSizeFilter) (int64, error) {
+	defer p.rlock(ctx)()
+
+	return int64(len(p.matching(filter))), nil
+}
+
+// GetSizesByProductID returns the active sizes of a product that are effective at the given instant.
+func (p *packSizeRepository) GetSizesByProductID(ctx context.Context, productID int64, asOf time.Time) ([]int, error) {
+	defer p.rlock(ctx)()
+
+	var sizes []int
+	for _, pack := range p.sorted() {
+		if int64(pack.ProductID) != productID || !pack.Active {
+			continue
+		}
+		if pack.ValidFrom != nil && pack.ValidFrom.After(asOf) {
+			continue
+		}
+		if pack.ValidTo != nil && !pack.ValidTo.After(asOf) {
+			continue
+		}
+		sizes = append(sizes, pack.Size)
+	}
+	return sizes, nil
+}
+
+// matching returns the pack sizes selected by the filtering fields of the filter, ordered by id.
+// The caller must hold the lock.
+func (p *packSizeRepository) matching(filter repositories.PackSizeFilter) []entities.PackSize {
+	var packSizes []entities.PackSize
+	for _, pack := range p.sorted() {
+		if filter.ProductID != 0 && pack.ProductID != filter.ProductID {
+			continue
+		}
+		if filter.Active != nil && pack.Active != *filter.Active {
+			continue
+		}
+		if filter.MinSize != 0 && pack.Size < filter.MinSize {
+			continue
+		}
+		if filter.MaxSize != 0 && pack.Size > filter.MaxSize {
+			continue
+		}
+		packSizes = append(packSizes, pack)
+	}
+	return packSizes
+}
+
+// sorted returns every pack size ordered by id. The caller must hold the lock.
+func (p *packSizeRepository) sorted() []entities.PackSize {
+	packSizes := make([]entities.PackSize, 0, len(p.packSizes))
+	for _, pack := range p.packSizes {
+		packSizes = append(packSizes, pack)
+	}
+	slices.SortFunc(packSizes, func(a, b entities.PackSize) int { return cmp.Compare(a.ID, b.ID) })
+	return packSizes
+}
+
+// packSizeSortKeys maps each supported sort to the value pack sizes are compared by
+var packSizeSortKeys = map[repositories.PackSizeSort]func(entities.PackSize) int64{
+	"":                                 func(pack entities.PackSize) int64 { return pack.ID },
+	repositories.PackSizeSortID:        func(pack entities.PackSize) int64 { return pack.ID },
+	repositories.PackSizeSortProductID: func(pack entities.PackSize) int64 { return int64(pack.ProductID) },
+	repositories.PackSizeSortSize:      func(pack entities.PackSize) int64 { return int64(pack.Size) },
+}
+
+// snapshot copies the state of the repository and returns a function restoring it. The caller must hold the lock.
+func (p *packSizeRepository) snapshot() func() {
+	lastID, packSizes := p.lastID, maps.Clone(p.packSizes)
+	return func() { p.lastID, p.packSizes = lastID, packSizes }
+}
@@ -0,0 +1,164 @@
+package memory
+
+import (
+	"context"
+	"order-pack-calculator/internal/domain/entities"
+	errs "order-pack-calculator/internal/domain/errors"
+	"order-pack-calculator/internal/domain/repositories"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackSizeRepository(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("create assigns ids", func(t *testing.T) {
+		repo := NewPackSizeRepository()
+
+		first, err := repo.Create(ctx, entities.PackSize{ProductID: 1, Size: 10})
+		assert.NoError(t, err)
+		second, err := repo.Create(ctx, entities.PackSize{ProductID: 1, Size: 20})
+		assert.NoError(t, err)
+
+		assert.Equal(t, int64(1), first.ID)
+		assert.Equal(t, int64(2), second.ID)
+		assert.True(t, first.Active)
+		assert.Equal(t, int64(1), first.Version)
+	})
+
+	t.Run("get by id", func(t *testing.T) {
+		repo := NewPackSizeRepository()
+		created, _ := repo.Create(ctx, entities.PackSize{ProductID: 1, Size: 10})
+
+		res, err := repo.GetByID(ctx, created.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, *created, *res)
+
+		_, err = repo.GetByID(ctx, 99)
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		repo := NewPackSizeRepository()
+		created, _ := repo.Create(ctx, entities.PackSize{ProductID: 1, Size: 10})
+
+		created.Size = 15
+		assert.NoError(t, repo.Update(ctx, *created))
+
+		res, _ := repo.GetByID(ctx, created.ID)
+		assert.Equal(t, 15, res.Size)
+		assert.Equal(t, int64(2), res.Version)
+	})
+
+	t.Run("update stale version", func(t *testing.T) {
+		repo := NewPackSizeRepository()
+		created, _ := repo.Create(ctx, entities.PackSize{ProductID: 1, Size: 10})
+		assert.NoError(t, repo.Update(ctx, *created))
+
+		err := repo.Update(ctx, *created)
+		assert.ErrorIs(t, err, errs.ErrPreconditionFailed)
+	})
+
+	t.Run("update not found", func(t *testing.T) {
+		repo := NewPackSizeRepository()
+
+		err := repo.Update(ctx, entities.PackSize{ID: 99, Size: 10})
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("sizes by product only include active effective sizes", func(t *testing.T) {
+		repo := NewPackSizeRepository()
+		asOf := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+		later := asOf.Add(time.Hour)
+
+		repo.Create(ctx, entities.PackSize{ProductID: 1, Size: 10})
+		inactive, _ := repo.Create(ctx, entities.PackSize{ProductID: 1, Size: 20})
+		inactive.Active = false
+		repo.Update(ctx, *inactive)
+		repo.Create(ctx, entities.PackSize{ProductID: 1, Size: 30, ValidFrom: &later})
+		repo.Create(ctx, entities.PackSize{ProductID: 1, Size: 40, ValidTo: &asOf})
+		repo.Create(ctx, entities.PackSize{ProductID: 1, Size: 50, ValidFrom: &asOf, ValidTo: &later})
+		repo.Create(ctx, entities.PackSize{ProductID: 2, Size: 60})
+
+		sizes, err := repo.GetSizesByProductID(ctx, 1, asOf)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{10, 50}, sizes)
+	})
+
+	t.Run("concurrent creates", func(t *testing.T) {
+		repo := NewPackSizeRepository()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				repo.Create(ctx, entities.PackSize{ProductID: 1, Size: 10})
+			}()
+		}
+		wg.Wait()
+
+		total, err := repo.Count(ctx, repositories.PackSizeFilter{})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(50), total)
+	})
+}
+
+func TestPackSizeRepositoryGetAll(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPackSizeRepository()
+	for _, pack := range []entities.PackSize{
+		{ProductID: 1, Size: 30},
+		{ProductID: 2, Size: 10},
+		{ProductID: 1, Size: 20},
+		{ProductID: 1, Size: 20},
+	} {
+		repo.Create(ctx, pack)
+	}
+
+	ids := func(packSizes []entities.PackSize) []int64 {
+		var ids []int64
+		for _, pack := range packSizes {
+			ids = append(ids, pack.ID)
+		}
+		return ids
+	}
+
+	t.Run("sorted by id", func(t *testing.T) {
+		res, err := repo.GetAll(ctx, repositories.PackSizeFilter{})
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{1, 2, 3, 4}, ids(res))
+	})
+
+	t.Run("filtered and sorted by size descending", func(t *testing.T) {
+		res, err := repo.GetAll(ctx, repositories.PackSizeFilter{ProductID: 1, MaxSize: 25, SortBy: repositories.PackSizeSortSize, Descending: true})
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{4, 3}, ids(res))
+	})
+
+	t.Run("pages after cursor", func(t *testing.T) {
+		filter := repositories.PackSizeFilter{SortBy: repositories.PackSizeSortSize, Limit: 2}
+		first, err := repo.GetAll(ctx, filter)
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{2, 3}, ids(first))
+
+		filter.After = &repositories.PackSizeCursor{Key: 20, ID: 3}
+		second, err := repo.GetAll(ctx, filter)
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{4, 1}, ids(second))
+	})
+
+	t.Run("count ignores paging", func(t *testing.T) {
+		total, err := repo.Count(ctx, repositories.PackSizeFilter{ProductID: 1, Limit: 1, After: &repositories.PackSizeCursor{Key: 1, ID: 1}})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+	})
+
+	t.Run("unsupported sort", func(t *testing.T) {
+		_, err := repo.GetAll(ctx, repositories.PackSizeFilter{SortBy: "active"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"order-pack-calculator/internal/domain/repositories"
+	"slices"
+	"sync"
+)
+
+// participant is implemented by the repositories of this package so a unit of work can coordinate them.
+type participant interface {
+	held() *guard
+	snapshot() func()
+}
+
+// guard protects the state of a repository. Calls made within a unit of work that already holds
+// the guard skip it, so the unit can use the repository without deadlocking on itself.
+type guard struct {
+	mu sync.RWMutex
+}
+
+func (g *guard) held() *guard {
+	return g
+}
+
+// lock takes the guard for writing and returns the function releasing it.
+func (g *guard) lock(ctx context.Context) func() {
+	if holds(ctx, g) {
+		return func() {}
+	}
+	g.mu.Lock()
+	return g.mu.Unlock
+}
+
+// rlock takes the guard for reading and returns the function releasing it.
+func (g *guard) rlock(ctx context.Context) func() {
+	if holds(ctx, g) {
+		return func() {}
+	}
+	g.mu.RLock()
+	return g.mu.RUnlock
+}
+
+type unitKey struct{}
+
+// holds reports whether the unit of work carried by ctx holds the guard.
+func holds(ctx context.Context, g *guard) bool {
+	unit, ok := ctx.Value(unitKey{}).(*unitOfWork)
+	return ok && slices.ContainsFunc(unit.participants, func(p participant) bool { return p.held() == g })
+}
+
+// NewUnitOfWork returns a UnitOfWork over repositories created by this package. A unit holds every
+// repository exclusively until it ends and restores their previous state when it fails, so units
+// are serialized and the repositories behave as if they shared one transaction.
+func NewUnitOfWork(repos ...any) repositories.UnitOfWork {
+	unit := &unitOfWork{}
+	for _, repo := range repos {
+		p, ok := repo.(participant)
+		if !ok {
+			panic(fmt.Sprintf("memory: %T is not an in-memory repository", repo))
+		}
+		unit.participants = append(unit.participants, p)
+	}
+	return unit
+}
+
+type unitOfWork struct {
+	participants []participant
+}
+
+func (u *unitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(unitKey{}).(*unitOfWork); ok {
+		return fn(ctx)
+	}
+
+	// Guards are always taken in the same order, so concurrent units cannot deadlock
+	restores := make([]func(), 0, len(u.participants))
+	for _, p := range u.participants {
+		p.held().mu.Lock()
+		defer p.held().mu.Unlock()
+		restores = append(restores, p.snapshot())
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			rollback(restores)
+			panic(r)
+		}
+		if err != nil {
+			rollback(restores)
+		}
+	}()
+
+	return fn(context.WithValue(ctx, unitKey{}, u))
+}
+
+func rollback(restores []func()) {
+	for _, restore := range restores {
+		restore()
+	}
+}
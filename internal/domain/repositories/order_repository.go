@@ -0,0 +1,229 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"order-pack-calculator/internal/domain/entities"
+	errs "order-pack-calculator/internal/domain/errors"
+	"time"
+)
+
+// orderStatusTimestampColumns maps each reachable status to the column recording when it was entered.
+var orderStatusTimestampColumns = map[entities.OrderStatus]string{
+	entities.OrderStatusConfirmed: "confirmed_at",
+	entities.OrderStatusPacked:    "packed_at",
+	entities.OrderStatusShipped:   "shipped_at",
+	entities.OrderStatusCancelled: "cancelled_at",
+}
+
+func NewOrderRepository(db *sql.DB, dialect Dialect) OrderRepository {
+	return orderRepository{db: sqlDB{DB: db, dialect: dialect}}
+}
+
+type orderRepository struct {
+	db sqlDB
+}
+
+// Create stores the order together with its pack combination in a single transaction.
+func (o orderRepository) Create(ctx context.Context, order entities.Order) (*entities.Order, error) {
+	packSizes, err := json.Marshal(order.PackSizes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode pack sizes snapshot: %w", err)
+	}
+
+	query := `
+	INSERT INTO orders (product_id, order_quantity, total_items, total_packs, objective, pack_sizes, as_of)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	RETURNING id, status, created_at
+`
+	err = inTx(ctx, o.db, func(tx queryer) error {
+		err := tx.QueryRowContext(ctx, query, order.ProductID, order.OrderQuantity, order.TotalItems, order.TotalPacks, order.Objective, string(packSizes), order.AsOf.UTC()).
+			Scan(&order.ID, &order.Status, &order.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to insert order for product_id=%d: %w", order.ProductID, err)
+		}
+		return insertOrderPacks(ctx, tx, order.ID, order.Packs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (o orderRepository) GetByID(ctx context.Context, ID int64) (*entities.Order, error) {
+	query := `
+	SELECT o.id, o.product_id, o.order_quantity, o.total_items, o.total_packs, o.objective, o.pack_sizes, o.as_of, o.status, o.created_at, o.confirmed_at, o.packed_at, o.shipped_at, o.cancelled_at, p.size, p.count
+	FROM orders o
+	LEFT JOIN order_packs p ON p.order_id = o.id
+	WHERE o.id = $1
+	ORDER BY p.size DESC
+`
+	rows, err := conn(ctx, o.db).QueryContext(ctx, query, ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query order id=%d: %w", ID, err)
+	}
+	defer rows.Close()
+
+	orders, err := scanOrders(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return nil, fmt.Errorf("%w: order id=%d", errs.ErrNotFound, ID)
+	}
+	return &orders[0], nil
+}
+
+// List returns the most recent orders first.
+func (o orderRepository) List(ctx context.Context, filter OrderFilter) ([]entities.Order, error) {
+	var (
+		conditions []string
+		args       []any
+	)
+	if filter.ProductID != 0 {
+		args = append(args, filter.ProductID)
+		conditions = append(conditions, fmt.Sprintf("product_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.CreatedFrom != nil {
+		args = append(args, filter.CreatedFrom.UTC())
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+	if filter.CreatedTo != nil {
+		args = append(args, filter.CreatedTo.UTC())
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+
+	query := fmt.Sprintf(`
+	SELECT o.id, o.product_id, o.order_quantity, o.total_items, o.total_packs, o.objective, o.pack_sizes, o.as_of, o.status, o.created_at, o.confirmed_at, o.packed_at, o.shipped_at, o.cancelled_at, p.size, p.count
+	FROM (
+		SELECT id, product_id, order_quantity, total_items, total_packs, objective, pack_sizes, as_of, status, created_at, confirmed_at, packed_at, shipped_at, cancelled_at
+		FROM orders
+		%s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d
+	) o
+	LEFT JOIN order_packs p ON p.order_id = o.id
+	ORDER BY o.created_at DESC, o.id DESC, p.size DESC
+`, whereClause(conditions), len(args)-1, len(args))
+
+	rows, err := conn(ctx, o.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query orders. %w", err)
+	}
+	defer rows.Close()
+
+	return scanOrders(rows)
+}
+
+func (o orderRepository) UpdateStatus(ctx context.Context, ID int64, from, to entities.OrderStatus, at time.Time) error {
+	column, ok := orderStatusTimestampColumns[to]
+	if !ok {
+		return fmt.Errorf("failed to update order id=%d: unsupported status %s", ID, to)
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE orders
+		SET status = $1, %s = $2
+		WHERE id = $3 AND status = $4
+	`, column)
+	rs, err := conn(ctx, o.db).ExecContext(ctx, query, to, at.UTC(), ID, from)
+	if err != nil {
+		return fmt.Errorf("failed to update order id=%d: %w", ID, err)
+	}
+	rowsAffected, err := rs.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update order id=%d: %w", ID, err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: order id=%d is no longer %s", errs.ErrConflict, ID, from)
+	}
+
+	return nil
+}
+
+func (o orderRepository) UpdateCalculation(ctx context.Context, order entities.Order) error {
+	packSizes, err := json.Marshal(order.PackSizes)
+	if err != nil {
+		return fmt.Errorf("failed to encode pack sizes snapshot: %w", err)
+	}
+
+	query := `
+		UPDATE orders
+		SET order_quantity = $1, total_items = $2, total_packs = $3, objective = $4, pack_sizes = $5, as_of = $6
+		WHERE id = $7 AND status = $8
+	`
+	return inTx(ctx, o.db, func(tx queryer) error {
+		rs, err := tx.ExecContext(ctx, query, order.OrderQuantity, order.TotalItems, order.TotalPacks, order.Objective, string(packSizes), order.AsOf.UTC(), order.ID, entities.OrderStatusDraft)
+		if err != nil {
+			return fmt.Errorf("failed to update order id=%d: %w", order.ID, err)
+		}
+		rowsAffected, err := rs.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to update order id=%d: %w", order.ID, err)
+		}
+		if rowsAffected == 0 {
+			return fmt.Errorf("%w: order id=%d is no longer a draft", errs.ErrConflict, order.ID)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM order_packs WHERE order_id = $1`, order.ID); err != nil {
+			return fmt.Errorf("failed to delete packs of order id=%d: %w", order.ID, err)
+		}
+		return insertOrderPacks(ctx, tx, order.ID, order.Packs)
+	})
+}
+
+func insertOrderPacks(ctx context.Context, tx queryer, orderID int64, packs []entities.OrderPack) error {
+	query := `
+	INSERT INTO order_packs (order_id, size, count)
+	VALUES ($1, $2, $3)
+`
+	for _, pack := range packs {
+		if _, err := tx.ExecContext(ctx, query, orderID, pack.Size, pack.Count); err != nil {
+			return fmt.Errorf("failed to insert pack size=%d for order id=%d: %w", pack.Size, orderID, err)
+		}
+	}
+	return nil
+}
+
+// scanOrders folds order rows joined with their packs into orders, preserving row order.
+func scanOrders(rows *tracedRows) ([]entities.Order, error) {
+	var orders []entities.Order
+	for rows.Next() {
+		var (
+			order     entities.Order
+			packSizes []byte
+			size      sql.NullInt64
+			count     sql.NullInt64
+		)
+		err := rows.Scan(&order.ID, &order.ProductID, &order.OrderQuantity, &order.TotalItems, &order.TotalPacks,
+			&order.Objective, &packSizes, &order.AsOf, &order.Status, &order.CreatedAt,
+			&order.ConfirmedAt, &order.PackedAt, &order.ShippedAt, &order.CancelledAt, &size, &count)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan order row: %w", err)
+		}
+
+		if len(orders) == 0 || orders[len(orders)-1].ID != order.ID {
+			if err := json.Unmarshal(packSizes, &order.PackSizes); err != nil {
+				return nil, fmt.Errorf("failed to decode pack sizes snapshot of order id=%d: %w", order.ID, err)
+			}
+			orders = append(orders, order)
+		}
+		if size.Valid {
+			current := &orders[len(orders)-1]
+			current.Packs = append(current.Packs, entities.OrderPack{Size: int(size.Int64), Count: int(count.Int64)})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate order rows: %w", err)
+	}
+
+	return orders, nil
+}
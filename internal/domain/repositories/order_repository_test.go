@@ -0,0 +1,225 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"order-pack-calculator/internal/domain/entities"
+	errs "order-pack-calculator/internal/domain/errors"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var orderColumns = []string{"id", "product_id", "order_quantity", "total_items", "total_packs", "objective", "pack_sizes", "as_of",
+	"status", "created_at", "confirmed_at", "packed_at", "shipped_at", "cancelled_at", "size", "count"}
+
+func TestOrderCreate(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	repo := NewOrderRepository(db, DialectPostgres)
+
+	asOf := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	order := entities.Order{
+		ProductID:     1,
+		OrderQuantity: 10,
+		TotalItems:    12,
+		TotalPacks:    2,
+		Objective:     entities.ObjectiveFewestItems,
+		PackSizes:     []int{6, 8},
+		AsOf:          asOf,
+		Packs:         []entities.OrderPack{{Size: 6, Count: 2}},
+	}
+
+	insertOrder := regexp.QuoteMeta(`INSERT INTO orders (product_id, order_quantity, total_items, total_packs, objective, pack_sizes, as_of)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, status, created_at`)
+	insertPack := regexp.QuoteMeta(`INSERT INTO order_packs (order_id, size, count) VALUES ($1, $2, $3)`)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(insertOrder).
+			WithArgs(1, 10, 12, 2, entities.ObjectiveFewestItems, "[6,8]", asOf).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at"}).AddRow(7, "draft", createdAt))
+		mock.ExpectExec(insertPack).
+			WithArgs(int64(7), 6, 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		res, err := repo.Create(context.Background(), order)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), res.ID)
+		assert.Equal(t, createdAt, res.CreatedAt)
+		assert.Equal(t, entities.OrderStatusDraft, res.Status)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("pack insert error rolls back", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(insertOrder).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at"}).AddRow(8, "draft", createdAt))
+		mock.ExpectExec(insertPack).
+			WillReturnError(errors.New("insert error"))
+		mock.ExpectRollback()
+
+		_, err := repo.Create(context.Background(), order)
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOrderGetByID(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	repo := NewOrderRepository(db, DialectPostgres)
+
+	query := regexp.QuoteMeta(`SELECT o.id, o.product_id, o.order_quantity, o.total_items, o.total_packs, o.objective, o.pack_sizes, o.as_of, o.status, o.created_at, o.confirmed_at, o.packed_at, o.shipped_at, o.cancelled_at, p.size, p.count
+FROM orders o
+LEFT JOIN order_packs p ON p.order_id = o.id
+WHERE o.id = $1`)
+	ts := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows(orderColumns).
+				AddRow(1, 1, 500000, 500000, 9438, entities.ObjectiveFewestItems, []byte("[23,31,53]"), ts, "confirmed", ts, ts, nil, nil, nil, 53, 9429).
+				AddRow(1, 1, 500000, 500000, 9438, entities.ObjectiveFewestItems, []byte("[23,31,53]"), ts, "confirmed", ts, ts, nil, nil, nil, 31, 7).
+				AddRow(1, 1, 500000, 500000, 9438, entities.ObjectiveFewestItems, []byte("[23,31,53]"), ts, "confirmed", ts, ts, nil, nil, nil, 23, 2))
+
+		res, err := repo.GetByID(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{23, 31, 53}, res.PackSizes)
+		assert.Equal(t, entities.OrderStatusConfirmed, res.Status)
+		assert.Equal(t, ts, *res.ConfirmedAt)
+		assert.Equal(t, []entities.OrderPack{{Size: 53, Count: 9429}, {Size: 31, Count: 7}, {Size: 23, Count: 2}}, res.Packs)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(int64(2)).
+			WillReturnRows(sqlmock.NewRows(orderColumns))
+
+		_, err := repo.GetByID(context.Background(), 2)
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+	})
+}
+
+func TestOrderList(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	repo := NewOrderRepository(db, DialectPostgres)
+
+	ts := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+
+	t.Run("with filters", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(`FROM orders
+WHERE product_id = $1 AND status = $2 AND created_at >= $3
+ORDER BY created_at DESC, id DESC
+LIMIT $4 OFFSET $5`)).
+			WithArgs(1, entities.OrderStatusDraft, ts, 10, 0).
+			WillReturnRows(sqlmock.NewRows(orderColumns).
+				AddRow(2, 1, 10, 12, 2, entities.ObjectiveFewestItems, []byte("[6,8]"), ts, "draft", ts, nil, nil, nil, nil, 6, 2).
+				AddRow(1, 1, 8, 8, 1, entities.ObjectiveFewestItems, []byte("[6,8]"), ts, "draft", ts, nil, nil, nil, nil, 8, 1))
+
+		res, err := repo.List(context.Background(), OrderFilter{ProductID: 1, Status: entities.OrderStatusDraft, CreatedFrom: &ts, Limit: 10})
+		assert.NoError(t, err)
+		assert.Len(t, res, 2)
+		assert.Equal(t, int64(2), res[0].ID)
+		assert.Equal(t, []entities.OrderPack{{Size: 8, Count: 1}}, res[1].Packs)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(`FROM orders`)).
+			WillReturnError(errors.New("query failed"))
+
+		_, err := repo.List(context.Background(), OrderFilter{Limit: 10})
+		assert.Error(t, err)
+	})
+}
+
+func TestOrderUpdateStatus(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	repo := NewOrderRepository(db, DialectPostgres)
+
+	query := regexp.QuoteMeta("UPDATE orders SET status = $1, confirmed_at = $2 WHERE id = $3 AND status = $4")
+	at := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec(query).
+			WithArgs(entities.OrderStatusConfirmed, at, int64(1), entities.OrderStatusDraft).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.UpdateStatus(context.Background(), 1, entities.OrderStatusDraft, entities.OrderStatusConfirmed, at)
+		assert.NoError(t, err)
+	})
+
+	t.Run("status changed concurrently", func(t *testing.T) {
+		mock.ExpectExec(query).
+			WithArgs(entities.OrderStatusConfirmed, at, int64(1), entities.OrderStatusDraft).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.UpdateStatus(context.Background(), 1, entities.OrderStatusDraft, entities.OrderStatusConfirmed, at)
+		assert.ErrorIs(t, err, errs.ErrConflict)
+	})
+
+	t.Run("unsupported status", func(t *testing.T) {
+		err := repo.UpdateStatus(context.Background(), 1, entities.OrderStatusConfirmed, entities.OrderStatusDraft, at)
+		assert.Error(t, err)
+	})
+}
+
+func TestOrderUpdateCalculation(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	repo := NewOrderRepository(db, DialectPostgres)
+
+	asOf := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	order := entities.Order{
+		ID:            1,
+		OrderQuantity: 100,
+		TotalItems:    120,
+		TotalPacks:    2,
+		Objective:     entities.ObjectiveFewestItems,
+		PackSizes:     []int{60},
+		AsOf:          asOf,
+		Packs:         []entities.OrderPack{{Size: 60, Count: 2}},
+	}
+	update := regexp.QuoteMeta(`UPDATE orders
+SET order_quantity = $1, total_items = $2, total_packs = $3, objective = $4, pack_sizes = $5, as_of = $6
+WHERE id = $7 AND status = $8`)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(update).
+			WithArgs(100, 120, 2, entities.ObjectiveFewestItems, "[60]", asOf, int64(1), entities.OrderStatusDraft).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM order_packs WHERE order_id = $1")).
+			WithArgs(int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO order_packs (order_id, size, count) VALUES ($1, $2, $3)")).
+			WithArgs(int64(1), 60, 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.UpdateCalculation(context.Background(), order)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("no longer a draft", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(update).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := repo.UpdateCalculation(context.Background(), order)
+		assert.ErrorIs(t, err, errs.ErrConflict)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
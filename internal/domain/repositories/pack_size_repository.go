@@ -7,37 +7,41 @@ import (
 	"fmt"
 	"order-pack-calculator/internal/domain/entities"
 	errs "order-pack-calculator/internal/domain/errors"
+	"time"
 )
 
-func NewPackSizeRepository(db *sql.DB) PackSizeRepository {
-	return packSizeRepository{db: db}
+func NewPackSizeRepository(db *sql.DB, dialect Dialect) PackSizeRepository {
+	return packSizeRepository{db: sqlDB{DB: db, dialect: dialect}}
 }
 
 type packSizeRepository struct {
-	db *sql.DB
+	db sqlDB
 }
 
 
 func (p packSizeRepository) Create(ctx context.Context, pack entities.PackSize) (*entities.PackSize, error) {
 	query := `
-	INSERT INTO pack_sizes (product_id, size)
-	VALUES ($1, $2)
-	RETURNING id, active
+	INSERT INTO pack_sizes (product_id, size, valid_from, valid_to)
+	VALUES ($1, $2, $3, $4)
+	RETURNING id, active, version
 `
 
-	err := p.db.QueryRowContext(ctx, query, pack.ProductID, pack.Size).Scan(&pack.ID, &pack.Active)
+	err := conn(ctx, p.db).QueryRowContext(ctx, query, pack.ProductID, pack.Size, utc(pack.ValidFrom), utc(pack.ValidTo)).Scan(&pack.ID, &pack.Active, &pack.Version)
 	if err != nil {
 		return nil, fmt.Errorf("failed to insert pack size for product_id=%d, size=%d: %w", pack.ProductID, pack.Size, err)
 	}
 	return &pack, nil
 }
+
+// Update writes the pack size only if the stored version still matches pack.Version,
+// incrementing the version. It fails with ErrPreconditionFailed when the version is stale.
 func (p packSizeRepository) Update(ctx context.Context, pack entities.PackSize) error {
 	query := `
 		UPDATE pack_sizes
-		SET size = $1, active = $2
-		WHERE id = $3
+		SET size = $1, active = $2, valid_from = $3, valid_to = $4, version = version + 1
+		WHERE id = $5 AND version = $6
 	`
-	rs, err := p.db.ExecContext(ctx, query, pack.Size, pack.Active, pack.ID)
+	rs, err := conn(ctx, p.db).ExecContext(ctx, query, pack.Size, pack.Active, utc(pack.ValidFrom), utc(pack.ValidTo), pack.ID, pack.Version)
 	if err != nil {
 		return fmt.Errorf("failed to update pack size id=%d: %w", pack.ID, err)
 	}
@@ -46,18 +50,42 @@ func (p packSizeRepository) Update(ctx context.Context, pack entities.PackSize)
 		return fmt.Errorf("failed to update pack size id=%d: %w", pack.ID, err)
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("%w: id=%d", errs.ErrNotFound, pack.ID)
+		return p.updateFailure(ctx, pack)
 	}
 
 	return nil
 }
-func (p packSizeRepository) GetSizesByProductID(ctx context.Context, productID int64) ([]int, error) {
+
+// updateFailure tells apart a missing pack size from a stale version after an update matched no rows
+func (p packSizeRepository) updateFailure(ctx context.Context, pack entities.PackSize) error {
+	query := `
+	SELECT version
+	FROM pack_sizes
+	WHERE id = $1
+`
+	var current int64
+	err := conn(ctx, p.db).QueryRowContext(ctx, query, pack.ID).Scan(&current)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return fmt.Errorf("%w: id=%d", errs.ErrNotFound, pack.ID)
+		default:
+			return fmt.Errorf("failed to update pack size id=%d: %w", pack.ID, err)
+		}
+	}
+	return fmt.Errorf("%w: pack size id=%d is at version %d, not %d", errs.ErrPreconditionFailed, pack.ID, current, pack.Version)
+}
+
+// GetSizesByProductID returns the active sizes of a product that are effective at the given instant.
+func (p packSizeRepository) GetSizesByProductID(ctx context.Context, productID int64, asOf time.Time) ([]int, error) {
 	query := `
 	SELECT size
 	FROM pack_sizes
 	WHERE product_id = $1 AND active = true
+	AND (valid_from IS NULL OR valid_from <= $2)
+	AND (valid_to IS NULL OR valid_to > $2)
 `
-	rows, err := p.db.QueryContext(ctx, query, productID)
+	rows, err := conn(ctx, p.db).QueryContext(ctx, query, productID, asOf.UTC())
 	if err != nil {
 		return nil, fmt.Errorf("failed to query pack sizes. product_id=%d: %w", productID, err)
 	}
@@ -76,13 +104,23 @@ func (p packSizeRepository) GetSizesByProductID(ctx context.Context, productID i
 }
 
 func (p packSizeRepository) GetByID(ctx context.Context, ID int64) (*entities.PackSize, error) {
-	query := `
-	SELECT id, product_id, size, active
+	return p.getByID(ctx, ID, "")
+}
+
+// GetByIDForUpdate reads the pack size and locks it until the end of the unit of work carried by ctx.
+func (p packSizeRepository) GetByIDForUpdate(ctx context.Context, ID int64) (*entities.PackSize, error) {
+	return p.getByID(ctx, ID, forUpdate(p.db))
+}
+
+func (p packSizeRepository) getByID(ctx context.Context, ID int64, lock string) (*entities.PackSize, error) {
+	query := fmt.Sprintf(`
+	SELECT id, product_id, size, active, valid_from, valid_to, version
 	FROM pack_sizes
 	WHERE id = $1
-`
+	%s
+`, lock)
 	var packSize entities.PackSize
-	err := p.db.QueryRowContext(ctx, query, ID).Scan(&packSize.ID, &packSize.ProductID, &packSize.Size, &packSize.Active)
+	err := conn(ctx, p.db).QueryRowContext(ctx, query, ID).Scan(&packSize.ID, &packSize.ProductID, &packSize.Size, &packSize.Active, &packSize.ValidFrom, &packSize.ValidTo, &packSize.Version)
 	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
@@ -97,12 +135,45 @@ func (p packSizeRepository) GetByID(ctx context.Context, ID int64) (*entities.Pa
 
 
 // GetAll implements PackSizeRepository.
-func (p packSizeRepository) GetAll(ctx context.Context) ([]entities.PackSize, error) {
-	query := `
-	SELECT id, product_id, size, active
+func (p packSizeRepository) GetAll(ctx context.Context, filter PackSizeFilter) ([]entities.PackSize, error) {
+	column, ok := packSizeSortColumns[filter.SortBy]
+	if !ok {
+		return nil, fmt.Errorf("failed to query pack sizes: unsupported sort %s", filter.SortBy)
+	}
+	direction, comparison := "ASC", ">"
+	if filter.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	conditions, args := packSizeConditions(filter)
+	if filter.After != nil {
+		if column == "id" {
+			args = append(args, filter.After.ID)
+			conditions = append(conditions, fmt.Sprintf("id %s $%d", comparison, len(args)))
+		} else {
+			args = append(args, filter.After.Key, filter.After.ID)
+			conditions = append(conditions, fmt.Sprintf("(%s, id) %s ($%d, $%d)", column, comparison, len(args)-1, len(args)))
+		}
+	}
+
+	order := fmt.Sprintf("id %s", direction)
+	if column != "id" {
+		order = fmt.Sprintf("%s %s, id %s", column, direction, direction)
+	}
+	limit := ""
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		limit = fmt.Sprintf("LIMIT $%d", len(args))
+	}
+
+	query := fmt.Sprintf(`
+	SELECT id, product_id, size, active, valid_from, valid_to, version
 	FROM pack_sizes
-`
-rows, err := p.db.QueryContext(ctx, query)
+	%s
+	ORDER BY %s
+	%s
+`, whereClause(conditions), order, limit)
+	rows, err := conn(ctx, p.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query pack sizes. %w", err)
 	}
@@ -111,11 +182,62 @@ rows, err := p.db.QueryContext(ctx, query)
 	var packSizes []entities.PackSize
 	for rows.Next() {
 		var packSize entities.PackSize
-		if err := rows.Scan(&packSize.ID, &packSize.ProductID, &packSize.Size, &packSize.Active); err != nil {
+		if err := rows.Scan(&packSize.ID, &packSize.ProductID, &packSize.Size, &packSize.Active, &packSize.ValidFrom, &packSize.ValidTo, &packSize.Version); err != nil {
 			return nil, fmt.Errorf("failed to scan pack size row: %w", err)
 		}
 		packSizes = append(packSizes, packSize)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate pack size rows: %w", err)
+	}
 
 	return packSizes, nil
 }
+
+func (p packSizeRepository) Count(ctx context.Context, filter PackSizeFilter) (int64, error) {
+	conditions, args := packSizeConditions(filter)
+	query := fmt.Sprintf(`
+	SELECT COUNT(*)
+	FROM pack_sizes
+	%s
+`, whereClause(conditions))
+
+	var total int64
+	if err := conn(ctx, p.db).QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to count pack sizes. %w", err)
+	}
+	return total, nil
+}
+
+// packSizeSortColumns maps each supported sort to its column
+var packSizeSortColumns = map[PackSizeSort]string{
+	"":                    "id",
+	PackSizeSortID:        "id",
+	PackSizeSortProductID: "product_id",
+	PackSizeSortSize:      "size",
+}
+
+// packSizeConditions translates the filtering fields of the filter into SQL conditions and their arguments
+func packSizeConditions(filter PackSizeFilter) ([]string, []any) {
+	var (
+		conditions []string
+		args       []any
+	)
+	if filter.ProductID != 0 {
+		args = append(args, filter.ProductID)
+		conditions = append(conditions, fmt.Sprintf("product_id = $%d", len(args)))
+	}
+	if filter.Active != nil {
+		args = append(args, *filter.Active)
+		conditions = append(conditions, fmt.Sprintf("active = $%d", len(args)))
+	}
+	if filter.MinSize != 0 {
+		args = append(args, filter.MinSize)
+		conditions = append(conditions, fmt.Sprintf("size >= $%d", len(args)))
+	}
+	if filter.MaxSize != 0 {
+		args = append(args, filter.MaxSize)
+		conditions = append(conditions, fmt.Sprintf("size <= $%d", len(args)))
+	}
+	return conditions, args
+}
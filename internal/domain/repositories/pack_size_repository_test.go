@@ -6,6 +6,7 @@ import (
 	"errors"
 	"regexp"
 	"testing"
+	"time"
 
 	"order-pack-calculator/internal/domain/entities"
 	errs "order-pack-calculator/internal/domain/errors"
@@ -17,24 +18,25 @@ import (
 func TestCreate(t *testing.T) {
 	db, mock, _ := sqlmock.New()
 	defer db.Close()
-	repo := NewPackSizeRepository(db)
+	repo := NewPackSizeRepository(db, DialectPostgres)
 
 	t.Run("success", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO pack_sizes (product_id, size)
-VALUES ($1, $2)
-RETURNING id, active`)).
-			WithArgs(int64(1), 10).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "active"}).AddRow(100, true))
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO pack_sizes (product_id, size, valid_from, valid_to)
+VALUES ($1, $2, $3, $4)
+RETURNING id, active, version`)).
+			WithArgs(int64(1), 10, nil, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "active", "version"}).AddRow(100, true, 1))
 
 		res, err := repo.Create(context.Background(), entities.PackSize{ProductID: 1, Size: 10})
 		assert.NoError(t, err)
 		assert.Equal(t, int64(100), res.ID)
 		assert.True(t, res.Active)
+		assert.Equal(t, int64(1), res.Version)
 	})
 
 	t.Run("query error", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO pack_sizes (product_id, size) VALUES ($1, $2) RETURNING id, active")).
-			WithArgs(int64(2), 20).
+		mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO pack_sizes (product_id, size, valid_from, valid_to) VALUES ($1, $2, $3, $4) RETURNING id, active, version")).
+			WithArgs(int64(2), 20, nil, nil).
 			WillReturnError(errors.New("insert error"))
 
 		_, err := repo.Create(context.Background(), entities.PackSize{ProductID: 2, Size: 20})
@@ -45,29 +47,44 @@ RETURNING id, active`)).
 func TestUpdate(t *testing.T) {
 	db, mock, _ := sqlmock.New()
 	defer db.Close()
-	repo := NewPackSizeRepository(db)
+	repo := NewPackSizeRepository(db, DialectPostgres)
 
 	t.Run("success", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta("UPDATE pack_sizes SET size = $1, active = $2 WHERE id = $3")).
-			WithArgs(20, true, int64(1)).
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE pack_sizes SET size = $1, active = $2, valid_from = $3, valid_to = $4, version = version + 1 WHERE id = $5 AND version = $6")).
+			WithArgs(20, true, nil, nil, int64(1), int64(3)).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
-		err := repo.Update(context.Background(), entities.PackSize{ID: 1, Size: 20, Active: true})
+		err := repo.Update(context.Background(), entities.PackSize{ID: 1, Size: 20, Active: true, Version: 3})
 		assert.NoError(t, err)
 	})
 
 	t.Run("no rows affected", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta("UPDATE pack_sizes SET size = $1, active = $2 WHERE id = $3")).
-			WithArgs(15, false, int64(99)).
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE pack_sizes SET size = $1, active = $2, valid_from = $3, valid_to = $4, version = version + 1 WHERE id = $5 AND version = $6")).
+			WithArgs(15, false, nil, nil, int64(99), int64(0)).
 			WillReturnResult(sqlmock.NewResult(1, 0))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT version FROM pack_sizes WHERE id = $1")).
+			WithArgs(int64(99)).
+			WillReturnError(sql.ErrNoRows)
 
 		err := repo.Update(context.Background(), entities.PackSize{ID: 99, Size: 15, Active: false})
 		assert.ErrorIs(t, err, errs.ErrNotFound)
 	})
 
+	t.Run("stale version", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE pack_sizes SET size = $1, active = $2, valid_from = $3, valid_to = $4, version = version + 1 WHERE id = $5 AND version = $6")).
+			WithArgs(20, true, nil, nil, int64(1), int64(2)).
+			WillReturnResult(sqlmock.NewResult(1, 0))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT version FROM pack_sizes WHERE id = $1")).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+
+		err := repo.Update(context.Background(), entities.PackSize{ID: 1, Size: 20, Active: true, Version: 2})
+		assert.ErrorIs(t, err, errs.ErrPreconditionFailed)
+	})
+
 	t.Run("exec error", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta("UPDATE pack_sizes SET size = $1, active = $2 WHERE id = $3")).
-			WithArgs(10, true, int64(2)).
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE pack_sizes SET size = $1, active = $2, valid_from = $3, valid_to = $4, version = version + 1 WHERE id = $5 AND version = $6")).
+			WithArgs(10, true, nil, nil, int64(2), int64(0)).
 			WillReturnError(errors.New("update error"))
 
 		err := repo.Update(context.Background(), entities.PackSize{ID: 2, Size: 10, Active: true})
@@ -78,20 +95,32 @@ func TestUpdate(t *testing.T) {
 func TestGetByID(t *testing.T) {
 	db, mock, _ := sqlmock.New()
 	defer db.Close()
-	repo := NewPackSizeRepository(db)
+	repo := NewPackSizeRepository(db, DialectPostgres)
 
 	t.Run("success", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, product_id, size, active FROM pack_sizes WHERE id = $1")).
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, product_id, size, active, valid_from, valid_to, version FROM pack_sizes WHERE id = $1")).
 			WithArgs(int64(1)).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "size", "active"}).AddRow(1, 1, 10, true))
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "size", "active", "valid_from", "valid_to", "version"}).AddRow(1, 1, 10, true, nil, nil, 1))
 
 		res, err := repo.GetByID(context.Background(), 1)
 		assert.NoError(t, err)
 		assert.Equal(t, int64(1), res.ID)
 	})
 
+	t.Run("with validity period", func(t *testing.T) {
+		validFrom := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, product_id, size, active, valid_from, valid_to, version FROM pack_sizes WHERE id = $1")).
+			WithArgs(int64(3)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "size", "active", "valid_from", "valid_to", "version"}).AddRow(3, 1, 60, true, validFrom, nil, 1))
+
+		res, err := repo.GetByID(context.Background(), 3)
+		assert.NoError(t, err)
+		assert.Equal(t, validFrom, *res.ValidFrom)
+		assert.Nil(t, res.ValidTo)
+	})
+
 	t.Run("not found", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, product_id, size, active FROM pack_sizes WHERE id = $1")).
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, product_id, size, active, valid_from, valid_to, version FROM pack_sizes WHERE id = $1")).
 			WithArgs(int64(2)).
 			WillReturnError(sql.ErrNoRows)
 
@@ -100,14 +129,30 @@ func TestGetByID(t *testing.T) {
 	})
 }
 
+func TestGetByIDForUpdate(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	repo := NewPackSizeRepository(db, DialectPostgres)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, product_id, size, active, valid_from, valid_to, version FROM pack_sizes WHERE id = $1 FOR UPDATE")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "size", "active", "valid_from", "valid_to", "version"}).AddRow(1, 1, 10, true, nil, nil, 2))
+
+	res, err := repo.GetByIDForUpdate(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), res.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetAll(t *testing.T) {
 	db, mock, _ := sqlmock.New()
 	defer db.Close()
-	repo := NewPackSizeRepository(db)
+	repo := NewPackSizeRepository(db, DialectPostgres)
 
 	t.Run("success", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, product_id, size, active FROM pack_sizes")).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "size", "active"}).AddRow(1, 1, 10, true).AddRow(2, 1, 20, true))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, product_id, size, active, valid_from, valid_to, version FROM pack_sizes ORDER BY id ASC")).
+			WithArgs().
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "size", "active", "valid_from", "valid_to", "version"}).AddRow(1, 1, 10, true, nil, nil, 1).AddRow(2, 1, 20, true, nil, nil, 1))
 
 		expected := []entities.PackSize{
 			{
@@ -115,22 +160,59 @@ func TestGetAll(t *testing.T) {
 				ProductID: 1,
 				Size:      10,
 				Active:    true,
+				Version:   1,
 			},
 			{
 				ID:        2,
 				ProductID: 1,
 				Size:      20,
 				Active:    true,
+				Version:   1,
 			},
 		}
 
-		res, err := repo.GetAll(context.Background())
+		res, err := repo.GetAll(context.Background(), PackSizeFilter{})
 		assert.NoError(t, err)
 		assert.ElementsMatch(t, expected, res)
 	})
 
+	t.Run("filtered page after cursor", func(t *testing.T) {
+		active := true
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, product_id, size, active, valid_from, valid_to, version FROM pack_sizes WHERE product_id = $1 AND active = $2 AND size >= $3 AND size <= $4 AND (size, id) < ($5, $6) ORDER BY size DESC, id DESC LIMIT $7")).
+			WithArgs(1, true, 10, 100, int64(50), int64(4), 2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "size", "active", "valid_from", "valid_to", "version"}).AddRow(2, 1, 20, true, nil, nil, 1))
+
+		res, err := repo.GetAll(context.Background(), PackSizeFilter{
+			ProductID:  1,
+			Active:     &active,
+			MinSize:    10,
+			MaxSize:    100,
+			SortBy:     PackSizeSortSize,
+			Descending: true,
+			After:      &PackSizeCursor{Key: 50, ID: 4},
+			Limit:      2,
+		})
+		assert.NoError(t, err)
+		assert.Len(t, res, 1)
+	})
+
+	t.Run("sorted by id after cursor", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, product_id, size, active, valid_from, valid_to, version FROM pack_sizes WHERE id > $1 ORDER BY id ASC LIMIT $2")).
+			WithArgs(int64(4), 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "size", "active", "valid_from", "valid_to", "version"}))
+
+		res, err := repo.GetAll(context.Background(), PackSizeFilter{SortBy: PackSizeSortID, After: &PackSizeCursor{Key: 4, ID: 4}, Limit: 10})
+		assert.NoError(t, err)
+		assert.Empty(t, res)
+	})
+
+	t.Run("unsupported sort", func(t *testing.T) {
+		_, err := repo.GetAll(context.Background(), PackSizeFilter{SortBy: "active"})
+		assert.Error(t, err)
+	})
+
 	t.Run("not found", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, product_id, size, active FROM pack_sizes")).
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, product_id, size, active, valid_from, valid_to, version FROM pack_sizes")).
 			WithArgs(int64(2)).
 			WillReturnError(sql.ErrNoRows)
 
@@ -139,27 +221,56 @@ func TestGetAll(t *testing.T) {
 	})
 }
 
+func TestCount(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	repo := NewPackSizeRepository(db, DialectPostgres)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM pack_sizes WHERE product_id = $1")).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+		total, err := repo.Count(context.Background(), PackSizeFilter{ProductID: 1, After: &PackSizeCursor{Key: 1, ID: 1}, Limit: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM pack_sizes")).
+			WillReturnError(errors.New("count error"))
+
+		_, err := repo.Count(context.Background(), PackSizeFilter{})
+		assert.Error(t, err)
+	})
+}
+
 func TestGetSizesByProductID(t *testing.T) {
 	db, mock, _ := sqlmock.New()
 	defer db.Close()
-	repo := NewPackSizeRepository(db)
+	repo := NewPackSizeRepository(db, DialectPostgres)
+
+	query := regexp.QuoteMeta(`SELECT size FROM pack_sizes WHERE product_id = $1 AND active = true
+AND (valid_from IS NULL OR valid_from <= $2)
+AND (valid_to IS NULL OR valid_to > $2)`)
+	asOf := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
 
 	t.Run("success", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta("SELECT size FROM pack_sizes WHERE product_id = $1 AND active = true")).
-			WithArgs(int64(1)).
+		mock.ExpectQuery(query).
+			WithArgs(int64(1), asOf).
 			WillReturnRows(sqlmock.NewRows([]string{"size"}).AddRow(10).AddRow(20))
 
-		sizes, err := repo.GetSizesByProductID(context.Background(), 1)
+		sizes, err := repo.GetSizesByProductID(context.Background(), 1, asOf)
 		assert.NoError(t, err)
 		assert.ElementsMatch(t, []int{10, 20}, sizes)
 	})
 
 	t.Run("query error", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta("SELECT size FROM pack_sizes WHERE product_id = $1 AND active = true")).
-			WithArgs(int64(2)).
+		mock.ExpectQuery(query).
+			WithArgs(int64(2), asOf).
 			WillReturnError(errors.New("query failed"))
 
-		_, err := repo.GetSizesByProductID(context.Background(), 2)
+		_, err := repo.GetSizesByProductID(context.Background(), 2, asOf)
 		assert.Error(t, err)
 	})
 }
@@ -0,0 +1,82 @@
+package repositorytest
+
+import (
+	"context"
+	"order-pack-calculator/internal/domain/entities"
+	errs "order-pack-calculator/internal/domain/errors"
+	"order-pack-calculator/internal/domain/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAPIKeyRepository checks that repo behaves as an APIKeyRepository.
+func TestAPIKeyRepository(t *testing.T, repo repositories.APIKeyRepository) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	newKey := func(name string) entities.APIKey {
+		return entities.APIKey{Name: name, Prefix: "opc_" + name, Hash: "hash-" + name, Roles: []string{"calculator"}, CreatedAt: now}
+	}
+
+	t.Run("create and get by hash", func(t *testing.T) {
+		created, err := repo.Create(ctx, newKey("contract-create"))
+		assert.NoError(t, err)
+		assert.NotZero(t, created.ID)
+
+		res, err := repo.GetByHash(ctx, "hash-contract-create")
+		assert.NoError(t, err)
+		assert.Equal(t, created.ID, res.ID)
+		assert.Equal(t, "contract-create", res.Name)
+		assert.Equal(t, "opc_contract-create", res.Prefix)
+		assert.Equal(t, []string{"calculator"}, res.Roles)
+		assert.Empty(t, res.ProductIDs)
+		assert.True(t, now.Equal(res.CreatedAt))
+		assert.Nil(t, res.RevokedAt)
+
+		_, err = repo.GetByHash(ctx, "hash-contract-missing")
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("product restrictions", func(t *testing.T) {
+		key := newKey("contract-products")
+		key.Roles = []string{"admin", "calculator"}
+		key.ProductIDs = []int{1000, 1001}
+		repo.Create(ctx, key)
+
+		res, err := repo.GetByHash(ctx, "hash-contract-products")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"admin", "calculator"}, res.Roles)
+		assert.Equal(t, []int{1000, 1001}, res.ProductIDs)
+	})
+
+	t.Run("list oldest first", func(t *testing.T) {
+		first, _ := repo.Create(ctx, newKey("contract-list-1"))
+		second, _ := repo.Create(ctx, newKey("contract-list-2"))
+
+		keys, err := repo.List(ctx)
+		assert.NoError(t, err)
+
+		var ids []int64
+		for _, key := range keys {
+			if key.ID == first.ID || key.ID == second.ID {
+				ids = append(ids, key.ID)
+			}
+		}
+		assert.Equal(t, []int64{first.ID, second.ID}, ids)
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		created, _ := repo.Create(ctx, newKey("contract-revoke"))
+
+		assert.NoError(t, repo.Revoke(ctx, created.ID, now))
+		res, err := repo.GetByHash(ctx, "hash-contract-revoke")
+		assert.NoError(t, err)
+		if assert.NotNil(t, res.RevokedAt) {
+			assert.True(t, now.Equal(*res.RevokedAt))
+		}
+
+		assert.ErrorIs(t, repo.Revoke(ctx, created.ID, now), errs.ErrNotFound)
+		assert.ErrorIs(t, repo.Revoke(ctx, created.ID+1000, now), errs.ErrNotFound)
+	})
+}
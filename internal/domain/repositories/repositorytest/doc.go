@@ -0,0 +1,7 @@
+// Package repositorytest holds the contract every implementation of the repository
+// interfaces must satisfy, whatever its storage.
+//
+// Each suite receives a single repository and runs its cases against it, so a backend
+// only has to be set up once. The repository may already hold data, as long as none of
+// it belongs to the products used by the suites (ids 1000 and above).
+package repositorytest
@@ -0,0 +1,104 @@
+package repositorytest
+
+import (
+	"context"
+	"order-pack-calculator/internal/domain/entities"
+	errs "order-pack-calculator/internal/domain/errors"
+	"order-pack-calculator/internal/domain/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIdempotencyKeyRepository checks that repo behaves as an IdempotencyKeyRepository.
+func TestIdempotencyKeyRepository(t *testing.T, repo repositories.IdempotencyKeyRepository) {
+	ctx := context.Background()
+	now := time.Now()
+	newKey := func(key string) entities.IdempotencyKey {
+		return entities.IdempotencyKey{Key: key, Route: "POST /api/v1/packsizes/", RequestHash: "hash", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	}
+
+	t.Run("create rejects unexpired key", func(t *testing.T) {
+		key := newKey("contract-create")
+
+		assert.NoError(t, repo.Create(ctx, key))
+		assert.ErrorIs(t, repo.Create(ctx, key), errs.ErrConflict)
+
+		other := key
+		other.Route = "PATCH /api/v1/packsizes/"
+		assert.NoError(t, repo.Create(ctx, other))
+	})
+
+	t.Run("create reclaims expired key", func(t *testing.T) {
+		key := newKey("contract-reclaim")
+		repo.Create(ctx, key)
+		key.StatusCode = 201
+		repo.Complete(ctx, key)
+
+		reclaimed := key
+		reclaimed.RequestHash = "other"
+		reclaimed.CreatedAt = key.ExpiresAt
+		reclaimed.ExpiresAt = key.ExpiresAt.Add(time.Hour)
+		assert.NoError(t, repo.Create(ctx, reclaimed))
+
+		res, _ := repo.GetByKey(ctx, key.Key, key.Route)
+		assert.Equal(t, "other", res.RequestHash)
+		assert.Zero(t, res.StatusCode)
+	})
+
+	t.Run("complete and get", func(t *testing.T) {
+		key := newKey("contract-complete")
+		repo.Create(ctx, key)
+
+		res, err := repo.GetByKey(ctx, key.Key, key.Route)
+		assert.NoError(t, err)
+		assert.Zero(t, res.StatusCode)
+
+		key.StatusCode = 200
+		key.ContentType = "application/json"
+		key.ResponseBody = []byte(`{}`)
+		assert.NoError(t, repo.Complete(ctx, key))
+
+		res, err = repo.GetByKey(ctx, key.Key, key.Route)
+		assert.NoError(t, err)
+		assert.Equal(t, "hash", res.RequestHash)
+		assert.Equal(t, 200, res.StatusCode)
+		assert.Equal(t, "application/json", res.ContentType)
+		assert.Equal(t, []byte(`{}`), res.ResponseBody)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := repo.GetByKey(ctx, "contract-missing", "POST /api/v1/packsizes/")
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+
+		err = repo.Complete(ctx, newKey("contract-missing"))
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		key := newKey("contract-delete")
+		repo.Create(ctx, key)
+
+		assert.NoError(t, repo.Delete(ctx, key.Key, key.Route))
+		_, err := repo.GetByKey(ctx, key.Key, key.Route)
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("delete expired", func(t *testing.T) {
+		expired := newKey("contract-expired")
+		expired.ExpiresAt = now.Add(-time.Hour)
+		repo.Create(ctx, expired)
+		kept := newKey("contract-kept")
+		kept.ExpiresAt = now.Add(48 * time.Hour)
+		repo.Create(ctx, kept)
+
+		_, err := repo.DeleteExpired(ctx, now)
+		assert.NoError(t, err)
+
+		_, err = repo.GetByKey(ctx, expired.Key, expired.Route)
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+		_, err = repo.GetByKey(ctx, kept.Key, kept.Route)
+		assert.NoError(t, err)
+	})
+}
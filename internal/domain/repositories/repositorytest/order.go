@@ -0,0 +1,118 @@
+package repositorytest
+
+import (
+	"context"
+	"order-pack-calculator/internal/domain/entities"
+	errs "order-pack-calculator/internal/domain/errors"
+	"order-pack-calculator/internal/domain/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestOrderRepository checks that repo behaves as an OrderRepository.
+func TestOrderRepository(t *testing.T, repo repositories.OrderRepository) {
+	ctx := context.Background()
+	newOrder := func(productID int) entities.Order {
+		return entities.Order{
+			ProductID:     productID,
+			OrderQuantity: 12,
+			TotalItems:    15,
+			TotalPacks:    2,
+			Objective:     entities.ObjectiveFewestItems,
+			PackSizes:     []int{5, 10},
+			AsOf:          time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+			Packs:         []entities.OrderPack{{Size: 5, Count: 1}, {Size: 10, Count: 1}},
+		}
+	}
+
+	t.Run("create and get by id", func(t *testing.T) {
+		created, err := repo.Create(ctx, newOrder(1000))
+		assert.NoError(t, err)
+		assert.NotZero(t, created.ID)
+		assert.Equal(t, entities.OrderStatusDraft, created.Status)
+		assert.False(t, created.CreatedAt.IsZero())
+
+		res, err := repo.GetByID(ctx, created.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 1000, res.ProductID)
+		assert.Equal(t, 15, res.TotalItems)
+		assert.Equal(t, []int{5, 10}, res.PackSizes)
+		assert.True(t, created.AsOf.Equal(res.AsOf))
+		assert.Equal(t, []entities.OrderPack{{Size: 10, Count: 1}, {Size: 5, Count: 1}}, res.Packs)
+	})
+
+	t.Run("get by id not found", func(t *testing.T) {
+		_, err := repo.GetByID(ctx, 999999)
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("list most recent first", func(t *testing.T) {
+		first, _ := repo.Create(ctx, newOrder(1001))
+		repo.Create(ctx, newOrder(1002))
+		second, _ := repo.Create(ctx, newOrder(1001))
+
+		res, err := repo.List(ctx, repositories.OrderFilter{ProductID: 1001, Limit: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{second.ID, first.ID}, orderIDs(res))
+
+		res, err = repo.List(ctx, repositories.OrderFilter{ProductID: 1001, Limit: 1, Offset: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{first.ID}, orderIDs(res))
+
+		createdFrom := first.CreatedAt.Add(-time.Minute)
+		res, err = repo.List(ctx, repositories.OrderFilter{ProductID: 1001, CreatedFrom: &createdFrom, Limit: 10})
+		assert.NoError(t, err)
+		assert.Len(t, res, 2)
+
+		res, err = repo.List(ctx, repositories.OrderFilter{ProductID: 1001, CreatedTo: &createdFrom, Limit: 10})
+		assert.NoError(t, err)
+		assert.Empty(t, res)
+	})
+
+	t.Run("update status", func(t *testing.T) {
+		created, _ := repo.Create(ctx, newOrder(1003))
+		at := time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC)
+
+		assert.NoError(t, repo.UpdateStatus(ctx, created.ID, entities.OrderStatusDraft, entities.OrderStatusConfirmed, at))
+		res, _ := repo.GetByID(ctx, created.ID)
+		assert.Equal(t, entities.OrderStatusConfirmed, res.Status)
+		assert.True(t, at.Equal(*res.ConfirmedAt))
+
+		orders, err := repo.List(ctx, repositories.OrderFilter{ProductID: 1003, Status: entities.OrderStatusConfirmed, Limit: 10})
+		assert.NoError(t, err)
+		assert.Len(t, orders, 1)
+	})
+
+	t.Run("update status from another status", func(t *testing.T) {
+		created, _ := repo.Create(ctx, newOrder(1004))
+
+		err := repo.UpdateStatus(ctx, created.ID, entities.OrderStatusConfirmed, entities.OrderStatusPacked, time.Now())
+		assert.ErrorIs(t, err, errs.ErrConflict)
+	})
+
+	t.Run("update calculation of a draft only", func(t *testing.T) {
+		created, _ := repo.Create(ctx, newOrder(1005))
+
+		created.OrderQuantity = 20
+		created.TotalItems = 20
+		created.Packs = []entities.OrderPack{{Size: 10, Count: 2}}
+		assert.NoError(t, repo.UpdateCalculation(ctx, *created))
+		res, _ := repo.GetByID(ctx, created.ID)
+		assert.Equal(t, 20, res.OrderQuantity)
+		assert.Equal(t, []entities.OrderPack{{Size: 10, Count: 2}}, res.Packs)
+
+		repo.UpdateStatus(ctx, created.ID, entities.OrderStatusDraft, entities.OrderStatusConfirmed, time.Now())
+		err := repo.UpdateCalculation(ctx, *created)
+		assert.ErrorIs(t, err, errs.ErrConflict)
+	})
+}
+
+func orderIDs(orders []entities.Order) []int64 {
+	ids := make([]int64, 0, len(orders))
+	for _, order := range orders {
+		ids = append(ids, order.ID)
+	}
+	return ids
+}
@@ -0,0 +1,211 @@
+package repositorytest
+
+import (
+	"context"
+	"order-pack-calculator/internal/domain/entities"
+	errs "order-pack-calculator/internal/domain/errors"
+	"order-pack-calculator/internal/domain/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPackSizeRepository checks that repo behaves as a PackSizeRepository.
+func TestPackSizeRepository(t *testing.T, repo repositories.PackSizeRepository) {
+	ctx := context.Background()
+
+	t.Run("create", func(t *testing.T) {
+		first, err := repo.Create(ctx, entities.PackSize{ProductID: 1000, Size: 10})
+		assert.NoError(t, err)
+		second, err := repo.Create(ctx, entities.PackSize{ProductID: 1000, Size: 20})
+		assert.NoError(t, err)
+
+		assert.NotZero(t, first.ID)
+		assert.Greater(t, second.ID, first.ID)
+		assert.Equal(t, 1000, first.ProductID)
+		assert.Equal(t, 10, first.Size)
+		assert.True(t, first.Active)
+		assert.Equal(t, int64(1), first.Version)
+	})
+
+	t.Run("get by id", func(t *testing.T) {
+		validFrom := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+		created, _ := repo.Create(ctx, entities.PackSize{ProductID: 1001, Size: 10, ValidFrom: &validFrom})
+
+		res, err := repo.GetByID(ctx, created.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, created.ID, res.ID)
+		assert.Equal(t, 1001, res.ProductID)
+		assert.Equal(t, 10, res.Size)
+		assert.True(t, res.Active)
+		assert.True(t, validFrom.Equal(*res.ValidFrom))
+		assert.Nil(t, res.ValidTo)
+		assert.Equal(t, int64(1), res.Version)
+	})
+
+	t.Run("get by id not found", func(t *testing.T) {
+		_, err := repo.GetByID(ctx, 999999)
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("get by id for update", func(t *testing.T) {
+		created, _ := repo.Create(ctx, entities.PackSize{ProductID: 1011, Size: 10})
+
+		res, err := repo.GetByIDForUpdate(ctx, created.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, *created, *res)
+
+		_, err = repo.GetByIDForUpdate(ctx, 999999)
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		created, _ := repo.Create(ctx, entities.PackSize{ProductID: 1002, Size: 10})
+		validTo := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		created.Size = 15
+		created.Active = false
+		created.ValidTo = &validTo
+		assert.NoError(t, repo.Update(ctx, *created))
+
+		res, _ := repo.GetByID(ctx, created.ID)
+		assert.Equal(t, 15, res.Size)
+		assert.False(t, res.Active)
+		assert.True(t, validTo.Equal(*res.ValidTo))
+		assert.Equal(t, int64(2), res.Version)
+	})
+
+	t.Run("update stale version", func(t *testing.T) {
+		created, _ := repo.Create(ctx, entities.PackSize{ProductID: 1003, Size: 10})
+		assert.NoError(t, repo.Update(ctx, *created))
+
+		err := repo.Update(ctx, *created)
+		assert.ErrorIs(t, err, errs.ErrPreconditionFailed)
+	})
+
+	t.Run("update not found", func(t *testing.T) {
+		err := repo.Update(ctx, entities.PackSize{ID: 999999, ProductID: 1003, Size: 10, Version: 1})
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("sizes by product only include active effective sizes", func(t *testing.T) {
+		asOf := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+		later := asOf.Add(time.Hour)
+		// The same instant as later, in another zone
+		laterElsewhere := later.In(time.FixedZone("UTC-3", -3*60*60))
+
+		repo.Create(ctx, entities.PackSize{ProductID: 1004, Size: 10})
+		inactive, _ := repo.Create(ctx, entities.PackSize{ProductID: 1004, Size: 20})
+		inactive.Active = false
+		repo.Update(ctx, *inactive)
+		repo.Create(ctx, entities.PackSize{ProductID: 1004, Size: 30, ValidFrom: &laterElsewhere})
+		repo.Create(ctx, entities.PackSize{ProductID: 1004, Size: 40, ValidTo: &asOf})
+		repo.Create(ctx, entities.PackSize{ProductID: 1004, Size: 50, ValidFrom: &asOf, ValidTo: &laterElsewhere})
+		repo.Create(ctx, entities.PackSize{ProductID: 1005, Size: 60})
+
+		sizes, err := repo.GetSizesByProductID(ctx, 1004, asOf)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []int{10, 50}, sizes)
+
+		sizes, err = repo.GetSizesByProductID(ctx, 1006, asOf)
+		assert.NoError(t, err)
+		assert.Empty(t, sizes)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		for _, size := range []int{5, 10, 15, 20} {
+			repo.Create(ctx, entities.PackSize{ProductID: 1007, Size: size})
+		}
+		inactive, _ := repo.Create(ctx, entities.PackSize{ProductID: 1007, Size: 12})
+		inactive.Active = false
+		repo.Update(ctx, *inactive)
+
+		active := true
+		res, err := repo.GetAll(ctx, repositories.PackSizeFilter{ProductID: 1007, Active: &active, MinSize: 10, MaxSize: 15})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{10, 15}, sizes(res))
+
+		total, err := repo.Count(ctx, repositories.PackSizeFilter{ProductID: 1007, Active: &active, MinSize: 10, MaxSize: 15})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+
+		inactiveOnly := false
+		res, err = repo.GetAll(ctx, repositories.PackSizeFilter{ProductID: 1007, Active: &inactiveOnly})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{12}, sizes(res))
+	})
+
+	t.Run("ordering", func(t *testing.T) {
+		var created []*entities.PackSize
+		for _, pack := range []entities.PackSize{{ProductID: 1009, Size: 30}, {ProductID: 1008, Size: 10}, {ProductID: 1009, Size: 20}, {ProductID: 1008, Size: 20}} {
+			c, _ := repo.Create(ctx, pack)
+			created = append(created, c)
+		}
+		id := func(i int) int64 { return created[i].ID }
+		filter := func(sortBy repositories.PackSizeSort, descending bool) repositories.PackSizeFilter {
+			return repositories.PackSizeFilter{SortBy: sortBy, Descending: descending}
+		}
+
+		tests := []struct {
+			name   string
+			filter repositories.PackSizeFilter
+			want   []int64
+		}{
+			{"by id", filter(repositories.PackSizeSortID, false), []int64{id(0), id(1), id(2), id(3)}},
+			{"by size, ties by id", filter(repositories.PackSizeSortSize, false), []int64{id(1), id(2), id(3), id(0)}},
+			{"by size descending", filter(repositories.PackSizeSortSize, true), []int64{id(0), id(3), id(2), id(1)}},
+			{"by product", filter(repositories.PackSizeSortProductID, false), []int64{id(1), id(3), id(0), id(2)}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				res, err := repo.GetAll(ctx, tt.filter)
+				assert.NoError(t, err)
+				// Other pack sizes may be interleaved, only the relative order of these is checked
+				assert.Equal(t, tt.want, ids(res, tt.want))
+			})
+		}
+	})
+
+	t.Run("pages after cursor", func(t *testing.T) {
+		for _, size := range []int{30, 10, 20, 20} {
+			repo.Create(ctx, entities.PackSize{ProductID: 1010, Size: size})
+		}
+
+		filter := repositories.PackSizeFilter{ProductID: 1010, SortBy: repositories.PackSizeSortSize, Descending: true, Limit: 3}
+		first, err := repo.GetAll(ctx, filter)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{30, 20, 20}, sizes(first))
+
+		last := first[len(first)-1]
+		filter.After = &repositories.PackSizeCursor{Key: int64(last.Size), ID: last.ID}
+		second, err := repo.GetAll(ctx, filter)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{10}, sizes(second))
+
+		total, err := repo.Count(ctx, filter)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4), total)
+	})
+}
+
+func sizes(packs []entities.PackSize) []int {
+	sizes := make([]int, 0, len(packs))
+	for _, pack := range packs {
+		sizes = append(sizes, pack.Size)
+	}
+	return sizes
+}
+
+// ids returns the ids of the pack sizes that are among wanted, preserving their order
+func ids(packs []entities.PackSize, wanted []int64) []int64 {
+	var ids []int64
+	for _, pack := range packs {
+		for _, id := range wanted {
+			if pack.ID == id {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
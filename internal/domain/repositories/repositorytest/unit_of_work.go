@@ -0,0 +1,121 @@
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"order-pack-calculator/internal/domain/entities"
+	errs "order-pack-calculator/internal/domain/errors"
+	"order-pack-calculator/internal/domain/repositories"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestUnitOfWork checks that unitOfWork makes the calls to packSizes and orders it runs atomic.
+func TestUnitOfWork(t *testing.T, unitOfWork repositories.UnitOfWork, packSizes repositories.PackSizeRepository, orders repositories.OrderRepository) {
+	ctx := context.Background()
+	failure := errors.New("something failed")
+
+	t.Run("commits when fn succeeds", func(t *testing.T) {
+		var (
+			pack  *entities.PackSize
+			order *entities.Order
+		)
+		err := unitOfWork.Do(ctx, func(ctx context.Context) error {
+			var err error
+			if pack, err = packSizes.Create(ctx, entities.PackSize{ProductID: 1100, Size: 10}); err != nil {
+				return err
+			}
+			order, err = orders.Create(ctx, entities.Order{ProductID: 1100, OrderQuantity: 10, PackSizes: []int{10}, AsOf: time.Now()})
+			return err
+		})
+		assert.NoError(t, err)
+
+		_, err = packSizes.GetByID(ctx, pack.ID)
+		assert.NoError(t, err)
+		_, err = orders.GetByID(ctx, order.ID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("rolls back when fn fails", func(t *testing.T) {
+		existing, _ := packSizes.Create(ctx, entities.PackSize{ProductID: 1101, Size: 10})
+
+		var (
+			pack  *entities.PackSize
+			order *entities.Order
+		)
+		err := unitOfWork.Do(ctx, func(ctx context.Context) error {
+			var err error
+			if pack, err = packSizes.Create(ctx, entities.PackSize{ProductID: 1101, Size: 20}); err != nil {
+				return err
+			}
+			updated := *existing
+			updated.Size = 15
+			if err := packSizes.Update(ctx, updated); err != nil {
+				return err
+			}
+			if order, err = orders.Create(ctx, entities.Order{ProductID: 1101, OrderQuantity: 10, PackSizes: []int{10}, AsOf: time.Now()}); err != nil {
+				return err
+			}
+			return failure
+		})
+		assert.ErrorIs(t, err, failure)
+
+		_, err = packSizes.GetByID(ctx, pack.ID)
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+		_, err = orders.GetByID(ctx, order.ID)
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+		res, err := packSizes.GetByID(ctx, existing.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, *existing, *res)
+	})
+
+	t.Run("nested units join the outer one", func(t *testing.T) {
+		var pack *entities.PackSize
+		err := unitOfWork.Do(ctx, func(ctx context.Context) error {
+			err := unitOfWork.Do(ctx, func(ctx context.Context) error {
+				var err error
+				pack, err = packSizes.Create(ctx, entities.PackSize{ProductID: 1102, Size: 10})
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			return failure
+		})
+		assert.ErrorIs(t, err, failure)
+
+		_, err = packSizes.GetByID(ctx, pack.ID)
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("rows read for update cannot change before the unit ends", func(t *testing.T) {
+		created, _ := packSizes.Create(ctx, entities.PackSize{ProductID: 1103, Size: 10})
+
+		const updates = 5
+		var wg sync.WaitGroup
+		for range updates {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				err := unitOfWork.Do(ctx, func(ctx context.Context) error {
+					pack, err := packSizes.GetByIDForUpdate(ctx, created.ID)
+					if err != nil {
+						return err
+					}
+					pack.Size++
+					return packSizes.Update(ctx, *pack)
+				})
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		res, err := packSizes.GetByID(ctx, created.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 10+updates, res.Size)
+		assert.Equal(t, int64(1+updates), res.Version)
+	})
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"order-pack-calculator/internal/domain/entities"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Seed is the content of a seed file. JSON files are accepted as well, being valid YAML.
+//
+//	pack_sizes:
+//	  - product_id: 1
+//	    size: 250
+//	  - product_id: 1
+//	    size: 500
+//	    active: false
+type Seed struct {
+	PackSizes []SeedPackSize `yaml:"pack_sizes"`
+}
+
+type SeedPackSize struct {
+	ProductID int        `yaml:"product_id"`
+	Size      int        `yaml:"size"`
+	Active    *bool      `yaml:"active"`
+	ValidFrom *time.Time `yaml:"valid_from"`
+	ValidTo   *time.Time `yaml:"valid_to"`
+}
+
+// LoadSeed reads a seed file and stores its pack sizes through the repository, in file order.
+// Pack sizes are active unless the file says otherwise.
+func LoadSeed(ctx context.Context, path string, packSizeRepository PackSizeRepository) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read seed file %s: %w", path, err)
+	}
+	var seed Seed
+	if err := yaml.Unmarshal(content, &seed); err != nil {
+		return fmt.Errorf("failed to parse seed file %s: %w", path, err)
+	}
+
+	for _, pack := range seed.PackSizes {
+		created, err := packSizeRepository.Create(ctx, entities.PackSize{
+			ProductID: pack.ProductID,
+			Size:      pack.Size,
+			ValidFrom: pack.ValidFrom,
+			ValidTo:   pack.ValidTo,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to seed pack size for product_id=%d, size=%d: %w", pack.ProductID, pack.Size, err)
+		}
+		if pack.Active != nil && *pack.Active != created.Active {
+			created.Active = *pack.Active
+			if err := packSizeRepository.Update(ctx, *created); err != nil {
+				return fmt.Errorf("failed to seed pack size for product_id=%d, size=%d: %w", pack.ProductID, pack.Size, err)
+			}
+		}
+	}
+	slog.InfoContext(ctx, "loaded seed file", "path", path, "pack_sizes", len(seed.PackSizes))
+	return nil
+}
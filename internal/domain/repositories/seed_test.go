@@ -0,0 +1,42 @@
+package repositories_test
+
+import (
+	"context"
+	"order-pack-calculator/internal/domain/repositories"
+	"order-pack-calculator/internal/domain/repositories/memory"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadSeed(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("yaml", func(t *testing.T) {
+		repo := memory.NewPackSizeRepository()
+
+		err := repositories.LoadSeed(ctx, "testdata/seed.yaml", repo)
+		assert.NoError(t, err)
+
+		packSizes, _ := repo.GetAll(ctx, repositories.PackSizeFilter{})
+		assert.Len(t, packSizes, 3)
+		assert.False(t, packSizes[1].Active)
+		assert.Equal(t, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), packSizes[2].ValidFrom.UTC())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		repo := memory.NewPackSizeRepository()
+
+		err := repositories.LoadSeed(ctx, "testdata/seed.json", repo)
+		assert.NoError(t, err)
+
+		sizes, _ := repo.GetSizesByProductID(ctx, 1, time.Now())
+		assert.Equal(t, []int{250, 500}, sizes)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		err := repositories.LoadSeed(ctx, "testdata/missing.yaml", memory.NewPackSizeRepository())
+		assert.Error(t, err)
+	})
+}
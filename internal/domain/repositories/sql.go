@@ -0,0 +1,230 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"order-pack-calculator/internal/tracing"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// The repositories in this package only use SQL understood by both Postgres and SQLite.
+// SQLite stores instants as text, which only compares correctly when every value is in
+// the same zone, so instants are always bound in UTC.
+
+// utc returns the instant in UTC, keeping nil as nil
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(conditions, " AND ")
+}
+
+// Dialect is the SQL database the repositories run their statements on
+type Dialect string
+
+const (
+	DialectPostgres Dialect = "postgres"
+	DialectSQLite   Dialect = "sqlite"
+)
+
+// sqlDB is the database of a repository and its dialect
+type sqlDB struct {
+	*sql.DB
+	dialect Dialect
+}
+
+// forUpdate returns the clause locking the selected rows until the end of the transaction.
+// SQLite has none and needs none: its transactions take the write lock when they begin.
+func forUpdate(db sqlDB) string {
+	if db.dialect == DialectSQLite {
+		return ""
+	}
+	return "FOR UPDATE"
+}
+
+// sqlQueryer is implemented by both *sql.DB and *sql.Tx
+type sqlQueryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// queryer runs the statements of the repositories, each in a span lasting until its rows are read
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*tracedRows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *tracedRow
+}
+
+// txKey carries the transaction of a unit of work, as the traced queryer handed out by inTx
+type txKey struct{}
+
+// conn returns the transaction of the unit of work carried by ctx, or db outside of one.
+// Every statement run with it is traced.
+func conn(ctx context.Context, db sqlDB) queryer {
+	if tx, ok := ctx.Value(txKey{}).(queryer); ok {
+		return tx
+	}
+	return traced(db.DB, db.dialect)
+}
+
+// inTx runs fn in the transaction of the unit of work carried by ctx,
+// or in a transaction of its own outside of one
+func inTx(ctx context.Context, db sqlDB, fn func(tx queryer) error) error {
+	if tx, ok := ctx.Value(txKey{}).(queryer); ok {
+		return fn(tx)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(traced(tx, db.dialect)); err != nil {
+		slog.DebugContext(ctx, "rolling back transaction", "error", err)
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		slog.WarnContext(ctx, "failed to commit transaction", "error", err)
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// tracerName is the instrumentation scope of the spans of the statements run by the repositories
+const tracerName = "order-pack-calculator/internal/domain/repositories"
+
+// tracedQueryer runs statements in a client span each, named after the operation and the table
+// as in "SELECT pack_sizes". Spans carry the statement but never its arguments.
+// The span of a query lasts until its rows are closed or its row is scanned, and records their errors.
+type tracedQueryer struct {
+	sqlQueryer
+	system attribute.KeyValue
+}
+
+func traced(q sqlQueryer, dialect Dialect) tracedQueryer {
+	system := semconv.DBSystemPostgreSQL
+	if dialect == DialectSQLite {
+		system = semconv.DBSystemSqlite
+	}
+	return tracedQueryer{sqlQueryer: q, system: system}
+}
+
+func (q tracedQueryer) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, span := q.start(ctx, query)
+	defer span.End()
+	result, err := q.sqlQueryer.ExecContext(ctx, query, args...)
+	tracing.RecordError(span, err)
+	return result, err
+}
+
+// QueryContext returns rows whose span ends when they are closed, which callers must always do
+func (q tracedQueryer) QueryContext(ctx context.Context, query string, args ...any) (*tracedRows, error) {
+	ctx, span := q.start(ctx, query)
+	rows, err := q.sqlQueryer.QueryContext(ctx, query, args...)
+	if err != nil {
+		tracing.RecordError(span, err)
+		span.End()
+		return nil, err
+	}
+	return &tracedRows{Rows: rows, span: span}, nil
+}
+
+// QueryRowContext returns a row whose span ends when it is scanned, which callers must always do
+func (q tracedQueryer) QueryRowContext(ctx context.Context, query string, args ...any) *tracedRow {
+	ctx, span := q.start(ctx, query)
+	return &tracedRow{Row: q.sqlQueryer.QueryRowContext(ctx, query, args...), span: span}
+}
+
+// tracedRows are the rows of a query, ending its span when closed
+type tracedRows struct {
+	*sql.Rows
+	span  trace.Span
+	ended bool
+}
+
+func (r *tracedRows) Scan(dest ...any) error {
+	err := r.Rows.Scan(dest...)
+	tracing.RecordError(r.span, err)
+	return err
+}
+
+// Close closes the rows and ends the span with the error that stopped the iteration, if any
+func (r *tracedRows) Close() error {
+	err := r.Rows.Close()
+	if !r.ended {
+		r.ended = true
+		tracing.RecordError(r.span, errors.Join(r.Rows.Err(), err))
+		r.span.End()
+	}
+	return err
+}
+
+// tracedRow is the row of a query, ending its span when scanned. A missing row is not an error of the statement.
+type tracedRow struct {
+	*sql.Row
+	span trace.Span
+}
+
+func (r *tracedRow) Scan(dest ...any) error {
+	err := r.Row.Scan(dest...)
+	if !errors.Is(err, sql.ErrNoRows) {
+		tracing.RecordError(r.span, err)
+	}
+	r.span.End()
+	return err
+}
+
+func (q tracedQueryer) start(ctx context.Context, query string) (context.Context, trace.Span) {
+	statement := strings.Join(strings.Fields(query), " ")
+	operation, table := queryTarget(statement)
+	attrs := []attribute.KeyValue{q.system, semconv.DBOperationName(operation), semconv.DBQueryText(statement)}
+	name := operation
+	if table != "" {
+		attrs = append(attrs, semconv.DBCollectionName(table))
+		name += " " + table
+	}
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// queryTarget returns the operation of a statement and the table it operates on, e.g. SELECT and pack_sizes.
+// The table is empty when it is not found.
+func queryTarget(statement string) (operation, table string) {
+	fields := strings.Fields(statement)
+	if len(fields) == 0 {
+		return "", ""
+	}
+	operation = strings.ToUpper(fields[0])
+	keyword := "FROM"
+	switch operation {
+	case "INSERT":
+		keyword = "INTO"
+	case "UPDATE":
+		keyword = "UPDATE"
+	}
+	for i, field := range fields[:len(fields)-1] {
+		if strings.EqualFold(field, keyword) {
+			table, _, _ = strings.Cut(fields[i+1], "(")
+			return operation, strings.TrimRight(table, ",;")
+		}
+	}
+	return operation, ""
+}
@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"order-pack-calculator/internal/domain/entities"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestQueryTarget(t *testing.T) {
+	for _, tc := range []struct {
+		statement, operation, table string
+	}{
+		{"SELECT size FROM pack_sizes WHERE product_id = $1", "SELECT", "pack_sizes"},
+		{"select count(*) from orders", "SELECT", "orders"},
+		{"INSERT INTO pack_sizes (product_id, size) VALUES ($1, $2)", "INSERT", "pack_sizes"},
+		{"INSERT INTO order_packs(order_id, size, count) VALUES ($1, $2, $3)", "INSERT", "order_packs"},
+		{"UPDATE pack_sizes SET size = $1 WHERE id = $2", "UPDATE", "pack_sizes"},
+		{"DELETE FROM idempotency_keys WHERE expires_at < $1", "DELETE", "idempotency_keys"},
+		{"SELECT 1", "SELECT", ""},
+		{"", "", ""},
+	} {
+		operation, table := queryTarget(tc.statement)
+		assert.Equal(t, tc.operation, operation, tc.statement)
+		assert.Equal(t, tc.table, table, tc.statement)
+	}
+}
+
+func TestTracedStatements(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	repo := NewPackSizeRepository(db, DialectPostgres)
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "request")
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT size FROM pack_sizes")).
+		WithArgs(int64(1), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"size"}).AddRow(250))
+	_, err := repo.GetSizesByProductID(ctx, 1, time.Now())
+	assert.NoError(t, err)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE pack_sizes")).WillReturnError(errors.New("db error"))
+	assert.Error(t, repo.Update(ctx, entities.PackSize{ID: 1, Size: 500, Version: 1}))
+	parent.End()
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 3)
+
+	query := spans[0]
+	assert.Equal(t, "SELECT pack_sizes", query.Name())
+	assert.Equal(t, trace.SpanKindClient, query.SpanKind())
+	assert.Equal(t, parent.SpanContext().SpanID(), query.Parent().SpanID())
+	assert.Contains(t, query.Attributes(), attribute.String("db.system", "postgresql"))
+	assert.Contains(t, query.Attributes(), attribute.String("db.operation.name", "SELECT"))
+	assert.Contains(t, query.Attributes(), attribute.String("db.collection.name", "pack_sizes"))
+	assert.Contains(t, query.Attributes(), attribute.String("db.query.text",
+		"SELECT size FROM pack_sizes WHERE product_id = $1 AND active = true AND (valid_from IS NULL OR valid_from <= $2) AND (valid_to IS NULL OR valid_to > $2)"))
+	assert.Equal(t, codes.Unset, query.Status().Code)
+
+	update := spans[1]
+	assert.Equal(t, "UPDATE pack_sizes", update.Name())
+	assert.Equal(t, codes.Error, update.Status().Code)
+	assert.Equal(t, "db error", update.Status().Description)
+}
+
+func TestTracedRows(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	repo := NewPackSizeRepository(db, DialectPostgres)
+	ctx := context.Background()
+
+	// Errors met while reading the rows fail the span of the query
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT size FROM pack_sizes")).
+		WillReturnRows(sqlmock.NewRows([]string{"size"}).AddRow("many"))
+	_, err := repo.GetSizesByProductID(ctx, 1, time.Now())
+	assert.Error(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT size FROM pack_sizes")).
+		WillReturnRows(sqlmock.NewRows([]string{"size"}).AddRow(250).AddRow(500).RowError(1, errors.New("connection reset")))
+	_, _ = repo.GetSizesByProductID(ctx, 1, time.Now())
+
+	// A missing row is not an error of the statement
+	mock.ExpectQuery(regexp.QuoteMeta("FROM pack_sizes WHERE id = $1")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, err = repo.GetByID(ctx, 1)
+	assert.Error(t, err)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 3)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Contains(t, spans[0].Status().Description, "converting")
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "connection reset", spans[1].Status().Description)
+	assert.Equal(t, codes.Unset, spans[2].Status().Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
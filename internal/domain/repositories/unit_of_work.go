@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+)
+
+func NewUnitOfWork(db *sql.DB, dialect Dialect) UnitOfWork {
+	return unitOfWork{db: sqlDB{DB: db, dialect: dialect}}
+}
+
+type unitOfWork struct {
+	db sqlDB
+}
+
+// Do runs fn in a database transaction. A unit of work started inside fn joins the outer one.
+func (u unitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return inTx(ctx, u.db, func(tx queryer) error {
+		if _, ok := ctx.Value(txKey{}).(queryer); ok {
+			return fn(ctx)
+		}
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"order-pack-calculator/internal/domain/entities"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitOfWork(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	unitOfWork := NewUnitOfWork(db, DialectPostgres)
+	packSizes := NewPackSizeRepository(db, DialectPostgres)
+	orders := NewOrderRepository(db, DialectPostgres)
+
+	t.Run("commits when fn succeeds", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("FROM pack_sizes WHERE id = $1 FOR UPDATE")).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "size", "active", "valid_from", "valid_to", "version"}).AddRow(1, 1, 10, true, nil, nil, 1))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE pack_sizes")).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := unitOfWork.Do(context.Background(), func(ctx context.Context) error {
+			pack, err := packSizes.GetByIDForUpdate(ctx, 1)
+			if err != nil {
+				return err
+			}
+			pack.Size = 20
+			return packSizes.Update(ctx, *pack)
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rolls back when fn fails", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE pack_sizes")).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectRollback()
+
+		err := unitOfWork.Do(context.Background(), func(ctx context.Context) error {
+			if err := packSizes.Update(ctx, entities.PackSize{ID: 1, Size: 20, Version: 1}); err != nil {
+				return err
+			}
+			return errors.New("something failed")
+		})
+		assert.EqualError(t, err, "something failed")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("repositories join the transaction instead of starting their own", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO orders")).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at"}).AddRow(1, entities.OrderStatusDraft, time.Now()))
+		mock.ExpectCommit()
+
+		err := unitOfWork.Do(context.Background(), func(ctx context.Context) error {
+			_, err := orders.Create(ctx, entities.Order{ProductID: 1, PackSizes: []int{10}})
+			return err
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("begin error", func(t *testing.T) {
+		mock.ExpectBegin().WillReturnError(errors.New("connection lost"))
+
+		called := false
+		err := unitOfWork.Do(context.Background(), func(ctx context.Context) error {
+			called = true
+			return nil
+		})
+		assert.ErrorContains(t, err, "failed to begin transaction")
+		assert.False(t, called)
+	})
+}
@@ -8,6 +8,34 @@ import (
 type PackSizeService interface {
 	CalcOptimalPacks(context.Context, dto.CalculatePackSizesRequest) (*dto.OptimalPackSizesResponse, error)
 	Create(context.Context, dto.CreatePackSizeRequest) (*dto.PackSizeResponse, error)
-	Update(context.Context, dto.UpdatePackSizeRequest) error
-	GetAll(ctx context.Context) ([]dto.PackSizeResponse, error)
+	Update(context.Context, dto.UpdatePackSizeRequest) (*dto.PackSizeResponse, error)
+	GetByID(ctx context.Context, ID int64) (*dto.PackSizeResponse, error)
+	GetAll(ctx context.Context, request dto.ListPackSizesRequest) (*dto.PackSizePageResponse, error)
+}
+
+type OrderService interface {
+	GetByID(ctx context.Context, ID int64) (*dto.OrderResponse, error)
+	List(ctx context.Context, request dto.ListOrdersRequest) ([]dto.OrderResponse, error)
+	Confirm(ctx context.Context, ID int64) (*dto.OrderResponse, error)
+	Pack(ctx context.Context, ID int64) (*dto.OrderResponse, error)
+	Ship(ctx context.Context, ID int64) (*dto.OrderResponse, error)
+	Cancel(ctx context.Context, ID int64) (*dto.OrderResponse, error)
+	Recalculate(ctx context.Context, ID int64, request dto.RecalculateOrderRequest) (*dto.OrderResponse, error)
+}
+
+type IdempotencyService interface {
+	// Begin reserves the key for a request. When an identical request already completed
+	// under the key, its stored response is returned so it can be replayed.
+	Begin(ctx context.Context, key, route, requestHash string) (*dto.IdempotentResponse, error)
+	Complete(ctx context.Context, key, route string, response dto.IdempotentResponse) error
+	// Release frees the key so the request can be retried.
+	Release(ctx context.Context, key, route string) error
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+type APIKeyService interface {
+	// Create generates a key. The key itself is only returned here, only its hash is stored.
+	Create(ctx context.Context, request dto.CreateAPIKeyRequest) (*dto.CreatedAPIKeyResponse, error)
+	List(ctx context.Context) ([]dto.APIKeyResponse, error)
+	Revoke(ctx context.Context, ID int64) error
 }
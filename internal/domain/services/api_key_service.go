@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"order-pack-calculator/internal/auth"
+	"order-pack-calculator/internal/domain/dto"
+	"order-pack-calculator/internal/domain/entities"
+	errs "order-pack-calculator/internal/domain/errors"
+	"order-pack-calculator/internal/domain/repositories"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Constructor for APIKeyService
+func NewAPIKeyService(apiKeyRepository repositories.APIKeyRepository) APIKeyService {
+	return apiKeyService{apiKeyRepository: apiKeyRepository}
+}
+
+type apiKeyService struct {
+	apiKeyRepository repositories.APIKeyRepository
+}
+
+// Generates and stores a new key
+func (a apiKeyService) Create(ctx context.Context, request dto.CreateAPIKeyRequest) (*dto.CreatedAPIKeyResponse, error) {
+	name := strings.TrimSpace(request.Name)
+	if err := validateAPIKey(name, request.Roles); err != nil {
+		return nil, fmt.Errorf("could not create api key. %w", err)
+	}
+
+	key, prefix, hash, err := auth.NewAPIKey()
+	if err != nil {
+		return nil, fmt.Errorf("could not generate api key. %w", err)
+	}
+	saved, err := a.apiKeyRepository.Create(ctx, entities.APIKey{
+		Name:       name,
+		Prefix:     prefix,
+		Hash:       hash,
+		Roles:      slices.Compact(slices.Sorted(slices.Values(request.Roles))),
+		ProductIDs: slices.Compact(slices.Sorted(slices.Values(request.ProductIDs))),
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not create api key. %w", err)
+	}
+	slog.InfoContext(ctx, "api key created", "api_key_id", saved.ID, "name", saved.Name, "roles", saved.Roles, "product_ids", saved.ProductIDs)
+
+	return &dto.CreatedAPIKeyResponse{APIKeyResponse: dto.APIKeyResponseFromEntity(*saved), Key: key}, nil
+}
+
+// Lists every key, revoked or not, oldest first
+func (a apiKeyService) List(ctx context.Context) ([]dto.APIKeyResponse, error) {
+	keys, err := a.apiKeyRepository.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch api keys. %w", err)
+	}
+
+	response := make([]dto.APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		response = append(response, dto.APIKeyResponseFromEntity(key))
+	}
+	return response, nil
+}
+
+// Revokes a key, it is rejected from then on
+func (a apiKeyService) Revoke(ctx context.Context, ID int64) error {
+	if err := a.apiKeyRepository.Revoke(ctx, ID, time.Now()); err != nil {
+		return fmt.Errorf("could not revoke api key. %w", err)
+	}
+	slog.InfoContext(ctx, "api key revoked", "api_key_id", ID)
+	return nil
+}
+
+// Ensures a key has a name and only known roles
+func validateAPIKey(name string, roles []string) error {
+	var fields []errs.FieldError
+	if name == "" {
+		fields = append(fields, errs.FieldError{Field: "name", Rule: "required", Message: "is required"})
+	}
+	if len(roles) == 0 {
+		fields = append(fields, errs.FieldError{Field: "roles", Rule: "required", Message: "is required"})
+	}
+	for _, role := range roles {
+		if !auth.IsRole(role) {
+			fields = append(fields, errs.FieldError{Field: "roles", Rule: "oneof", Message: fmt.Sprintf("must be %s or %s, not %q", auth.RoleAdmin, auth.RoleCalculator, role)})
+		}
+	}
+	if len(fields) > 0 {
+		return &errs.ValidationError{Fields: fields}
+	}
+	return nil
+}
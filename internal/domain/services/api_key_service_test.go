@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"order-pack-calculator/internal/auth"
+	"order-pack-calculator/internal/domain/dto"
+	"order-pack-calculator/internal/domain/entities"
+	errs "order-pack-calculator/internal/domain/errors"
+
+	"order-pack-calculator/mocks"
+)
+
+func TestAPIKeyCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAPIKeyRepository(ctrl)
+	service := NewAPIKeyService(repo)
+
+	t.Run("success", func(t *testing.T) {
+		var stored entities.APIKey
+		repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, key entities.APIKey) (*entities.APIKey, error) {
+			stored = key
+			key.ID = 1
+			return &key, nil
+		})
+
+		res, err := service.Create(context.Background(), dto.CreateAPIKeyRequest{Name: " ci ", Roles: []string{"calculator", "admin", "calculator"}, ProductIDs: []int{2, 1}})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), res.ID)
+		assert.Equal(t, "ci", res.Name)
+		assert.Equal(t, []string{"admin", "calculator"}, res.Roles)
+		assert.Equal(t, []int{1, 2}, res.ProductIDs)
+		assert.True(t, auth.IsAPIKey(res.Key))
+		assert.Equal(t, res.Key[:len(res.Prefix)], res.Prefix)
+		assert.Equal(t, auth.HashAPIKey(res.Key), stored.Hash)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		for _, request := range []dto.CreateAPIKeyRequest{
+			{Name: " ", Roles: []string{"admin"}},
+			{Name: "ci"},
+			{Name: "ci", Roles: []string{"root"}},
+		} {
+			_, err := service.Create(context.Background(), request)
+			assert.ErrorIs(t, err, errs.ErrValidation, request)
+		}
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, errors.New("insert error"))
+
+		_, err := service.Create(context.Background(), dto.CreateAPIKeyRequest{Name: "ci", Roles: []string{"admin"}})
+		assert.Error(t, err)
+	})
+}
+
+func TestAPIKeyList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAPIKeyRepository(ctrl)
+	service := NewAPIKeyService(repo)
+
+	now := time.Now()
+	repo.EXPECT().List(gomock.Any()).Return([]entities.APIKey{
+		{ID: 1, Name: "ci", Prefix: "opc_abcd", Hash: "hash", CreatedAt: now, RevokedAt: &now},
+	}, nil)
+
+	res, err := service.List(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []dto.APIKeyResponse{{ID: 1, Name: "ci", Prefix: "opc_abcd", CreatedAt: now, RevokedAt: &now}}, res)
+}
+
+func TestAPIKeyRevoke(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAPIKeyRepository(ctrl)
+	service := NewAPIKeyService(repo)
+
+	repo.EXPECT().Revoke(gomock.Any(), int64(1), gomock.Any()).Return(nil)
+	assert.NoError(t, service.Revoke(context.Background(), 1))
+
+	repo.EXPECT().Revoke(gomock.Any(), int64(2), gomock.Any()).Return(errs.ErrNotFound)
+	assert.ErrorIs(t, service.Revoke(context.Background(), 2), errs.ErrNotFound)
+}
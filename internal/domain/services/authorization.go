@@ -0,0 +1,28 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"order-pack-calculator/internal/auth"
+	errs "order-pack-calculator/internal/domain/errors"
+)
+
+// authorizeProduct fails with ErrForbidden when the principal of the request is restricted to other products.
+// Requests without a principal, made from the command line or with authentication disabled, are not restricted.
+func authorizeProduct(ctx context.Context, productID int) error {
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok || principal.CanAccessProduct(productID) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s cannot access product_id=%d", errs.ErrForbidden, principal.Subject, productID)
+}
+
+// authorizeProductFilter checks the product filter of a listing. Principals restricted to
+// some products must filter by one of them, listing every product is not allowed to them.
+func authorizeProductFilter(ctx context.Context, productID int) error {
+	principal, ok := auth.PrincipalFrom(ctx)
+	if ok && productID == 0 && len(principal.ProductIDs) > 0 {
+		return fmt.Errorf("%w: %s must filter by product_id", errs.ErrForbidden, principal.Subject)
+	}
+	return authorizeProduct(ctx, productID)
+}
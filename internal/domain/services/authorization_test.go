@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"order-pack-calculator/internal/auth"
+	"order-pack-calculator/internal/domain/dto"
+	"order-pack-calculator/internal/domain/entities"
+	errs "order-pack-calculator/internal/domain/errors"
+
+	"order-pack-calculator/mocks"
+)
+
+func TestProductRestrictions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	packSizeRepo := mocks.NewMockPackSizeRepository(ctrl)
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	packSizes := NewPackSizeService(packSizeRepo, orderRepo, mocks.NewMockUnitOfWork(ctrl), Limits{})
+	orders := NewOrderService(orderRepo, packSizeRepo, Limits{})
+
+	restricted := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "apikey:1", Roles: []string{auth.RoleAdmin}, ProductIDs: []int{1}})
+
+	t.Run("other products are forbidden", func(t *testing.T) {
+		_, err := packSizes.Create(restricted, dto.CreatePackSizeRequest{ProductID: 2, Size: 10})
+		assert.ErrorIs(t, err, errs.ErrForbidden)
+
+		_, err = packSizes.CalcOptimalPacks(restricted, dto.CalculatePackSizesRequest{ProductID: 2, OrderQuantity: 10})
+		assert.ErrorIs(t, err, errs.ErrForbidden)
+
+		orderRepo.EXPECT().GetByID(gomock.Any(), int64(5)).Return(&entities.Order{ID: 5, ProductID: 2, Status: entities.OrderStatusDraft}, nil)
+		_, err = orders.Confirm(restricted, 5)
+		assert.ErrorIs(t, err, errs.ErrForbidden)
+	})
+
+	t.Run("listings must filter by an allowed product", func(t *testing.T) {
+		_, err := packSizes.GetAll(restricted, dto.ListPackSizesRequest{})
+		assert.ErrorIs(t, err, errs.ErrForbidden)
+
+		_, err = orders.List(restricted, dto.ListOrdersRequest{ProductID: 2})
+		assert.ErrorIs(t, err, errs.ErrForbidden)
+	})
+
+	t.Run("allowed product", func(t *testing.T) {
+		packSizeRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, packSize entities.PackSize) (*entities.PackSize, error) {
+			packSize.ID = 1
+			return &packSize, nil
+		})
+		_, err := packSizes.Create(restricted, dto.CreatePackSizeRequest{ProductID: 1, Size: 10})
+		assert.NoError(t, err)
+	})
+
+	t.Run("requests without principal are not restricted", func(t *testing.T) {
+		orderRepo.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, nil)
+		_, err := orders.List(context.Background(), dto.ListOrdersRequest{})
+		assert.NoError(t, err)
+
+		orderRepo.EXPECT().GetByID(gomock.Any(), int64(5)).Return(&entities.Order{ID: 5, ProductID: 2, CreatedAt: time.Now()}, nil)
+		_, err = orders.GetByID(context.Background(), 5)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"order-pack-calculator/internal/domain/dto"
+	"order-pack-calculator/internal/domain/entities"
+	errs "order-pack-calculator/internal/domain/errors"
+	"order-pack-calculator/internal/domain/repositories"
+	"time"
+)
+
+// Constructor for IdempotencyService. Stored responses are kept for ttl.
+func NewIdempotencyService(idempotencyKeyRepository repositories.IdempotencyKeyRepository, ttl time.Duration) IdempotencyService {
+	return idempotencyService{idempotencyKeyRepository: idempotencyKeyRepository, ttl: ttl}
+}
+
+type idempotencyService struct {
+	idempotencyKeyRepository repositories.IdempotencyKeyRepository
+	ttl                      time.Duration
+}
+
+// Reserves the key or returns the response to replay
+func (i idempotencyService) Begin(ctx context.Context, key, route, requestHash string) (*dto.IdempotentResponse, error) {
+	now := time.Now()
+	err := i.idempotencyKeyRepository.Create(ctx, entities.IdempotencyKey{
+		Key:         key,
+		Route:       route,
+		RequestHash: requestHash,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(i.ttl),
+	})
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, errs.ErrConflict) {
+		return nil, fmt.Errorf("could not reserve idempotency key. %w", err)
+	}
+
+	existing, err := i.idempotencyKeyRepository.GetByKey(ctx, key, route)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch idempotency key. %w", err)
+	}
+	if existing.RequestHash != requestHash {
+		return nil, fmt.Errorf("%w: key=%s", errs.ErrIdempotencyKeyReused, key)
+	}
+	if existing.StatusCode == 0 {
+		return nil, fmt.Errorf("%w: key=%s", errs.ErrIdempotencyKeyInProgress, key)
+	}
+
+	return &dto.IdempotentResponse{
+		StatusCode:  existing.StatusCode,
+		ContentType: existing.ContentType,
+		Body:        existing.ResponseBody,
+	}, nil
+}
+
+// Stores the response of the request holding the key
+func (i idempotencyService) Complete(ctx context.Context, key, route string, response dto.IdempotentResponse) error {
+	err := i.idempotencyKeyRepository.Complete(ctx, entities.IdempotencyKey{
+		Key:          key,
+		Route:        route,
+		StatusCode:   response.StatusCode,
+		ContentType:  response.ContentType,
+		ResponseBody: response.Body,
+	})
+	if err != nil {
+		return fmt.Errorf("could not store idempotent response. %w", err)
+	}
+	return nil
+}
+
+// Frees the key so the request can be retried
+func (i idempotencyService) Release(ctx context.Context, key, route string) error {
+	err := i.idempotencyKeyRepository.Delete(ctx, key, route)
+	if err != nil {
+		return fmt.Errorf("could not release idempotency key. %w", err)
+	}
+	return nil
+}
+
+// Removes expired keys
+func (i idempotencyService) PurgeExpired(ctx context.Context) (int64, error) {
+	deleted, err := i.idempotencyKeyRepository.DeleteExpired(ctx, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("could not purge idempotency keys. %w", err)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"order-pack-calculator/internal/domain/dto"
+	"order-pack-calculator/internal/domain/entities"
+	errs "order-pack-calculator/internal/domain/errors"
+
+	"order-pack-calculator/mocks"
+)
+
+func TestIdempotencyBegin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockIdempotencyKeyRepository(ctrl)
+	service := NewIdempotencyService(repo, time.Hour)
+
+	const route = "POST /api/v1/packsizes/"
+
+	t.Run("first request reserves the key", func(t *testing.T) {
+		repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, key entities.IdempotencyKey) error {
+			assert.Equal(t, "abc", key.Key)
+			assert.Equal(t, "hash", key.RequestHash)
+			assert.Equal(t, time.Hour, key.ExpiresAt.Sub(key.CreatedAt))
+			return nil
+		})
+
+		replay, err := service.Begin(context.Background(), "abc", route, "hash")
+		assert.NoError(t, err)
+		assert.Nil(t, replay)
+	})
+
+	t.Run("retry replays the stored response", func(t *testing.T) {
+		repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errs.ErrConflict)
+		repo.EXPECT().GetByKey(gomock.Any(), "abc", route).Return(&entities.IdempotencyKey{
+			Key: "abc", Route: route, RequestHash: "hash", StatusCode: 200, ContentType: "application/json", ResponseBody: []byte(`{"id":1}`),
+		}, nil)
+
+		replay, err := service.Begin(context.Background(), "abc", route, "hash")
+		assert.NoError(t, err)
+		assert.Equal(t, &dto.IdempotentResponse{StatusCode: 200, ContentType: "application/json", Body: []byte(`{"id":1}`)}, replay)
+	})
+
+	t.Run("different body", func(t *testing.T) {
+		repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errs.ErrConflict)
+		repo.EXPECT().GetByKey(gomock.Any(), "abc", route).Return(&entities.IdempotencyKey{Key: "abc", RequestHash: "hash", StatusCode: 200}, nil)
+
+		_, err := service.Begin(context.Background(), "abc", route, "other")
+		assert.ErrorIs(t, err, errs.ErrIdempotencyKeyReused)
+	})
+
+	t.Run("first request still in progress", func(t *testing.T) {
+		repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errs.ErrConflict)
+		repo.EXPECT().GetByKey(gomock.Any(), "abc", route).Return(&entities.IdempotencyKey{Key: "abc", RequestHash: "hash"}, nil)
+
+		_, err := service.Begin(context.Background(), "abc", route, "hash")
+		assert.ErrorIs(t, err, errs.ErrIdempotencyKeyInProgress)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+
+		_, err := service.Begin(context.Background(), "abc", route, "hash")
+		assert.Error(t, err)
+	})
+}
+
+func TestIdempotencyComplete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockIdempotencyKeyRepository(ctrl)
+	service := NewIdempotencyService(repo, time.Hour)
+
+	repo.EXPECT().Complete(gomock.Any(), entities.IdempotencyKey{
+		Key: "abc", Route: "POST /api/v1/packsizes/", StatusCode: 200, ContentType: "application/json", ResponseBody: []byte(`{}`),
+	}).Return(nil)
+
+	err := service.Complete(context.Background(), "abc", "POST /api/v1/packsizes/", dto.IdempotentResponse{StatusCode: 200, ContentType: "application/json", Body: []byte(`{}`)})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"fmt"
+	errs "order-pack-calculator/internal/domain/errors"
+)
+
+// Limits bounds the values the services accept. Zero values are not enforced,
+// and a product overridden with zero gets the limit of every product.
+type Limits struct {
+	// MaxOrderQuantity is the largest quantity an order can be calculated for
+	MaxOrderQuantity int
+	// ProductMaxOrderQuantities overrides MaxOrderQuantity for the products it lists, by product ID
+	ProductMaxOrderQuantities map[int]int
+	// MaxPackSize is the largest pack size a product can have
+	MaxPackSize int
+	// ProductMaxPackSizes overrides MaxPackSize for the products it lists, by product ID
+	ProductMaxPackSizes map[int]int
+}
+
+// Returns the largest pack size the product can have, 0 when unbounded
+func (l Limits) maxPackSize(productID int) int {
+	if limit := l.ProductMaxPackSizes[productID]; limit > 0 {
+		return limit
+	}
+	return l.MaxPackSize
+}
+
+// Ensures the pack size is within the limit of its product
+func (l Limits) validatePackSize(productID, size int) error {
+	limit := l.maxPackSize(productID)
+	if limit > 0 && size > limit {
+		return &errs.ValidationError{Fields: []errs.FieldError{
+			{Field: "size", Rule: "max", Message: fmt.Sprintf("must be at most %d for product %d", limit, productID)},
+		}}
+	}
+	return nil
+}
+
+// Ensures an order of the product can be calculated for the quantity
+func (l Limits) validateOrderQuantity(productID, quantity int) error {
+	if limit := l.ProductMaxOrderQuantities[productID]; limit > 0 {
+		if quantity > limit {
+			return &errs.ValidationError{Fields: []errs.FieldError{
+				{Field: "order_quantity", Rule: "max", Message: fmt.Sprintf("must be at most %d for product %d", limit, productID)},
+			}}
+		}
+		return nil
+	}
+	if l.MaxOrderQuantity > 0 && quantity > l.MaxOrderQuantity {
+		return &errs.ValidationError{Fields: []errs.FieldError{
+			{Field: "order_quantity", Rule: "max", Message: fmt.Sprintf("must be at most %d", l.MaxOrderQuantity)},
+		}}
+	}
+	return nil
+}
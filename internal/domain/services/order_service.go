@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"order-pack-calculator/internal/domain/dto"
+	"order-pack-calculator/internal/domain/entities"
+	errs "order-pack-calculator/internal/domain/errors"
+	"order-pack-calculator/internal/domain/repositories"
+	"time"
+)
+
+// Number of orders returned by List when no limit is requested
+const defaultOrderListLimit = 50
+
+// Constructor for OrderService
+func NewOrderService(orderRepository repositories.OrderRepository, packSizeRepository repositories.PackSizeRepository, limits Limits) OrderService {
+	return orderService{orderRepository: orderRepository, packSizeRepository: packSizeRepository, limits: limits}
+}
+
+type orderService struct {
+	orderRepository    repositories.OrderRepository
+	packSizeRepository repositories.PackSizeRepository
+	limits             Limits
+}
+
+// Retrieves a stored order
+func (o orderService) GetByID(ctx context.Context, ID int64) (*dto.OrderResponse, error) {
+	order, err := o.orderRepository.GetByID(ctx, ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch order. %w", err)
+	}
+	if err := authorizeProduct(ctx, order.ProductID); err != nil {
+		return nil, fmt.Errorf("could not fetch order. %w", err)
+	}
+
+	response := dto.OrderResponseFromEntity(*order)
+	return &response, nil
+}
+
+// Lists stored orders, most recent first
+func (o orderService) List(ctx context.Context, request dto.ListOrdersRequest) ([]dto.OrderResponse, error) {
+	if err := authorizeProductFilter(ctx, request.ProductID); err != nil {
+		return nil, fmt.Errorf("could not fetch orders. %w", err)
+	}
+
+	filter := repositories.OrderFilter{
+		ProductID:   request.ProductID,
+		Status:      entities.OrderStatus(request.Status),
+		CreatedFrom: request.CreatedFrom,
+		CreatedTo:   request.CreatedTo,
+		Limit:       request.Limit,
+		Offset:      request.Offset,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultOrderListLimit
+	}
+
+	orders, err := o.orderRepository.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch orders. %w", err)
+	}
+	return dto.OrderResponseFromEntities(orders), nil
+}
+
+// Confirms a draft order, freezing its pack combination
+func (o orderService) Confirm(ctx context.Context, ID int64) (*dto.OrderResponse, error) {
+	return o.transition(ctx, ID, entities.OrderStatusConfirmed)
+}
+
+// Marks a confirmed order as packed
+func (o orderService) Pack(ctx context.Context, ID int64) (*dto.OrderResponse, error) {
+	return o.transition(ctx, ID, entities.OrderStatusPacked)
+}
+
+// Marks a packed order as shipped
+func (o orderService) Ship(ctx context.Context, ID int64) (*dto.OrderResponse, error) {
+	return o.transition(ctx, ID, entities.OrderStatusShipped)
+}
+
+// Cancels an order that has not been shipped yet
+func (o orderService) Cancel(ctx context.Context, ID int64) (*dto.OrderResponse, error) {
+	return o.transition(ctx, ID, entities.OrderStatusCancelled)
+}
+
+// Recalculates a draft order against the pack sizes currently in effect
+func (o orderService) Recalculate(ctx context.Context, ID int64, request dto.RecalculateOrderRequest) (*dto.OrderResponse, error) {
+	order, err := o.orderRepository.GetByID(ctx, ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not recalculate order. %w", err)
+	}
+	if err := authorizeProduct(ctx, order.ProductID); err != nil {
+		return nil, fmt.Errorf("could not recalculate order. %w", err)
+	}
+	if order.Status != entities.OrderStatusDraft {
+		return nil, fmt.Errorf("could not recalculate order. %w: order id=%d is %s", errs.ErrOrderNotDraft, ID, order.Status)
+	}
+
+	calculation := dto.CalculatePackSizesRequest{
+		ProductID:     order.ProductID,
+		OrderQuantity: order.OrderQuantity,
+		AsOf:          request.AsOf,
+	}
+	if request.OrderQuantity != nil {
+		calculation.OrderQuantity = *request.OrderQuantity
+	}
+
+	recalculated, _, err := calculateOrder(ctx, o.packSizeRepository, o.limits, calculation)
+	if err != nil {
+		return nil, fmt.Errorf("could not recalculate order. %w", err)
+	}
+	recalculated.ID = order.ID
+	recalculated.Status = order.Status
+	recalculated.CreatedAt = order.CreatedAt
+
+	err = o.orderRepository.UpdateCalculation(ctx, recalculated)
+	if err != nil {
+		return nil, fmt.Errorf("could not recalculate order. %w", err)
+	}
+	slog.InfoContext(ctx, "order recalculated", "order_id", recalculated.ID, "product_id", recalculated.ProductID, "order_quantity", recalculated.OrderQuantity,
+		"total_items", recalculated.TotalItems, "total_packs", recalculated.TotalPacks)
+
+	response := dto.OrderResponseFromEntity(recalculated)
+	return &response, nil
+}
+
+// Moves an order to the given status if its lifecycle allows it
+func (o orderService) transition(ctx context.Context, ID int64, to entities.OrderStatus) (*dto.OrderResponse, error) {
+	order, err := o.orderRepository.GetByID(ctx, ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not update order status. %w", err)
+	}
+	if err := authorizeProduct(ctx, order.ProductID); err != nil {
+		return nil, fmt.Errorf("could not update order status. %w", err)
+	}
+	if !order.Status.CanTransitionTo(to) {
+		return nil, fmt.Errorf("could not update order status. %w", &errs.InvalidTransitionError{OrderID: ID, From: string(order.Status), To: string(to)})
+	}
+
+	now := time.Now()
+	err = o.orderRepository.UpdateStatus(ctx, ID, order.Status, to, now)
+	if err != nil {
+		return nil, fmt.Errorf("could not update order status. %w", err)
+	}
+	slog.InfoContext(ctx, "order status changed", "order_id", ID, "from", order.Status, "to", to)
+	order.SetStatus(to, now)
+
+	response := dto.OrderResponseFromEntity(*order)
+	return &response, nil
+}
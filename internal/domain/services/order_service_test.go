@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"order-pack-calculator/internal/domain/dto"
+	"order-pack-calculator/internal/domain/entities"
+	errs "order-pack-calculator/internal/domain/errors"
+	"order-pack-calculator/internal/domain/repositories"
+
+	"order-pack-calculator/mocks"
+)
+
+func TestOrderGetByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockOrderRepository(ctrl)
+	service := NewOrderService(repo, mocks.NewMockPackSizeRepository(ctrl), Limits{})
+
+	t.Run("success", func(t *testing.T) {
+		createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+		order := &entities.Order{
+			ID:            1,
+			ProductID:     1,
+			OrderQuantity: 10,
+			TotalItems:    12,
+			TotalPacks:    2,
+			Objective:     entities.ObjectiveFewestItems,
+			PackSizes:     []int{6, 8},
+			AsOf:          createdAt,
+			Status:        entities.OrderStatusDraft,
+			CreatedAt:     createdAt,
+			Packs:         []entities.OrderPack{{Size: 6, Count: 2}},
+		}
+		repo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(order, nil)
+
+		resp, err := service.GetByID(context.Background(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, dto.OrderResponse{
+			ID:              1,
+			ProductID:       1,
+			OrderQuantity:   10,
+			PackCombination: []dto.PackDetail{{Size: 6, Count: 2}},
+			TotalItems:      12,
+			TotalPacks:      2,
+			Objective:       entities.ObjectiveFewestItems,
+			PackSizes:       []int{6, 8},
+			AsOf:            createdAt,
+			Status:          "draft",
+			CreatedAt:       createdAt,
+		}, *resp)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		repo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(nil, errs.ErrNotFound)
+		_, err := service.GetByID(context.Background(), 2)
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+	})
+}
+
+func TestOrderList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockOrderRepository(ctrl)
+	service := NewOrderService(repo, mocks.NewMockPackSizeRepository(ctrl), Limits{})
+
+	t.Run("default limit", func(t *testing.T) {
+		repo.EXPECT().List(gomock.Any(), repositories.OrderFilter{ProductID: 1, Limit: defaultOrderListLimit}).
+			Return([]entities.Order{{ID: 2}, {ID: 1}}, nil)
+
+		resp, err := service.List(context.Background(), dto.ListOrdersRequest{ProductID: 1})
+
+		assert.NoError(t, err)
+		assert.Len(t, resp, 2)
+		assert.Equal(t, int64(2), resp[0].ID)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+		repo.EXPECT().List(gomock.Any(), repositories.OrderFilter{Status: entities.OrderStatusShipped, CreatedFrom: &from, CreatedTo: &to, Limit: 10, Offset: 20}).
+			Return(nil, nil)
+
+		resp, err := service.List(context.Background(), dto.ListOrdersRequest{Status: "shipped", CreatedFrom: &from, CreatedTo: &to, Limit: 10, Offset: 20})
+
+		assert.NoError(t, err)
+		assert.Empty(t, resp)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		repo.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, errors.New("repo error"))
+		_, err := service.List(context.Background(), dto.ListOrdersRequest{})
+		assert.Error(t, err)
+	})
+}
+
+func TestOrderTransitions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockOrderRepository(ctrl)
+	service := NewOrderService(repo, mocks.NewMockPackSizeRepository(ctrl), Limits{})
+
+	tests := []struct {
+		name       string
+		from       entities.OrderStatus
+		to         entities.OrderStatus
+		transition func(context.Context, int64) (*dto.OrderResponse, error)
+		allowed    bool
+	}{
+		{"confirm draft", entities.OrderStatusDraft, entities.OrderStatusConfirmed, service.Confirm, true},
+		{"pack confirmed", entities.OrderStatusConfirmed, entities.OrderStatusPacked, service.Pack, true},
+		{"ship packed", entities.OrderStatusPacked, entities.OrderStatusShipped, service.Ship, true},
+		{"cancel draft", entities.OrderStatusDraft, entities.OrderStatusCancelled, service.Cancel, true},
+		{"cancel packed", entities.OrderStatusPacked, entities.OrderStatusCancelled, service.Cancel, true},
+		{"pack draft", entities.OrderStatusDraft, entities.OrderStatusPacked, service.Pack, false},
+		{"ship confirmed", entities.OrderStatusConfirmed, entities.OrderStatusShipped, service.Ship, false},
+		{"confirm cancelled", entities.OrderStatusCancelled, entities.OrderStatusConfirmed, service.Confirm, false},
+		{"cancel shipped", entities.OrderStatusShipped, entities.OrderStatusCancelled, service.Cancel, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&entities.Order{ID: 1, Status: tt.from}, nil)
+			if tt.allowed {
+				repo.EXPECT().UpdateStatus(gomock.Any(), int64(1), tt.from, tt.to, gomock.Any()).Return(nil)
+			}
+
+			resp, err := tt.transition(context.Background(), 1)
+
+			if !tt.allowed {
+				var transitionErr *errs.InvalidTransitionError
+				assert.ErrorIs(t, err, errs.ErrInvalidTransition)
+				assert.ErrorAs(t, err, &transitionErr)
+				assert.Equal(t, string(tt.from), transitionErr.From)
+				assert.Equal(t, string(tt.to), transitionErr.To)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, string(tt.to), resp.Status)
+		})
+	}
+
+	t.Run("records the transition time", func(t *testing.T) {
+		repo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&entities.Order{ID: 1, Status: entities.OrderStatusDraft}, nil)
+		repo.EXPECT().UpdateStatus(gomock.Any(), int64(1), entities.OrderStatusDraft, entities.OrderStatusConfirmed, gomock.Any()).Return(nil)
+
+		resp, err := service.Confirm(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.NotNil(t, resp.ConfirmedAt)
+		assert.Nil(t, resp.CancelledAt)
+	})
+
+	t.Run("concurrent change", func(t *testing.T) {
+		repo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&entities.Order{ID: 1, Status: entities.OrderStatusDraft}, nil)
+		repo.EXPECT().UpdateStatus(gomock.Any(), int64(1), gomock.Any(), gomock.Any(), gomock.Any()).Return(errs.ErrConflict)
+
+		_, err := service.Confirm(context.Background(), 1)
+		assert.ErrorIs(t, err, errs.ErrConflict)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		repo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(nil, errs.ErrNotFound)
+		_, err := service.Confirm(context.Background(), 2)
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+	})
+}
+
+func TestOrderRecalculate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockOrderRepository(ctrl)
+	packSizeRepo := mocks.NewMockPackSizeRepository(ctrl)
+	service := NewOrderService(repo, packSizeRepo, Limits{})
+
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("draft order", func(t *testing.T) {
+		asOf := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+		quantity := 100
+		existing := &entities.Order{ID: 1, ProductID: 1, OrderQuantity: 10, Status: entities.OrderStatusDraft, CreatedAt: createdAt}
+		expected := entities.Order{
+			ID:            1,
+			ProductID:     1,
+			OrderQuantity: 100,
+			TotalItems:    120,
+			TotalPacks:    2,
+			Objective:     entities.ObjectiveFewestItems,
+			PackSizes:     []int{60},
+			AsOf:          asOf,
+			Status:        entities.OrderStatusDraft,
+			CreatedAt:     createdAt,
+			Packs:         []entities.OrderPack{{Size: 60, Count: 2}},
+		}
+
+		repo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(existing, nil)
+		packSizeRepo.EXPECT().GetSizesByProductID(gomock.Any(), int64(1), asOf).Return([]int{60}, nil)
+		repo.EXPECT().UpdateCalculation(gomock.Any(), expected).Return(nil)
+
+		resp, err := service.Recalculate(context.Background(), 1, dto.RecalculateOrderRequest{OrderQuantity: &quantity, AsOf: &asOf})
+		assert.NoError(t, err)
+		assert.Equal(t, 120, resp.TotalItems)
+		assert.Equal(t, []dto.PackDetail{{Size: 60, Count: 2}}, resp.PackCombination)
+	})
+
+	t.Run("confirmed order is frozen", func(t *testing.T) {
+		repo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&entities.Order{ID: 1, Status: entities.OrderStatusConfirmed}, nil)
+
+		_, err := service.Recalculate(context.Background(), 1, dto.RecalculateOrderRequest{})
+		assert.ErrorIs(t, err, errs.ErrOrderNotDraft)
+	})
+
+	t.Run("confirmed concurrently", func(t *testing.T) {
+		repo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&entities.Order{ID: 1, ProductID: 1, OrderQuantity: 10, Status: entities.OrderStatusDraft}, nil)
+		packSizeRepo.EXPECT().GetSizesByProductID(gomock.Any(), int64(1), gomock.Any()).Return([]int{6, 8}, nil)
+		repo.EXPECT().UpdateCalculation(gomock.Any(), gomock.Any()).Return(errs.ErrConflict)
+
+		_, err := service.Recalculate(context.Background(), 1, dto.RecalculateOrderRequest{})
+		assert.ErrorIs(t, err, errs.ErrConflict)
+	})
+}
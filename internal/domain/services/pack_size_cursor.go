@@ -0,0 +1,69 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"order-pack-calculator/internal/domain/entities"
+	errs "order-pack-calculator/internal/domain/errors"
+	"order-pack-calculator/internal/domain/repositories"
+)
+
+// packSizeCursor is the content of the opaque cursor handed out with a page of pack sizes.
+// It records the ordering and a hash of the filters it was issued for so it cannot be replayed against other ones.
+type packSizeCursor struct {
+	SortBy     repositories.PackSizeSort `json:"s"`
+	Descending bool                      `json:"d,omitempty"`
+	Filters    string                    `json:"f"`
+	Key        int64                     `json:"k"`
+	ID         int64                     `json:"i"`
+}
+
+func encodePackSizeCursor(last entities.PackSize, filter repositories.PackSizeFilter) string {
+	cursor := packSizeCursor{SortBy: filter.SortBy, Descending: filter.Descending, Filters: packSizeFiltersHash(filter), Key: packSizeSortKey(last, filter.SortBy), ID: last.ID}
+	// Marshalling a struct of plain fields cannot fail
+	content, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(content)
+}
+
+func decodePackSizeCursor(token string, filter repositories.PackSizeFilter) (*repositories.PackSizeCursor, error) {
+	content, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errs.ErrInvalidCursor, err)
+	}
+	var cursor packSizeCursor
+	if err := json.Unmarshal(content, &cursor); err != nil {
+		return nil, fmt.Errorf("%w: %s", errs.ErrInvalidCursor, err)
+	}
+	if cursor.SortBy != filter.SortBy || cursor.Descending != filter.Descending {
+		return nil, fmt.Errorf("%w: cursor was issued for a different sort order", errs.ErrInvalidCursor)
+	}
+	if cursor.Filters != packSizeFiltersHash(filter) {
+		return nil, fmt.Errorf("%w: cursor was issued for different filters", errs.ErrInvalidCursor)
+	}
+	return &repositories.PackSizeCursor{Key: cursor.Key, ID: cursor.ID}, nil
+}
+
+// packSizeFiltersHash identifies the filters narrowing down the pack sizes listed. The page size is left out
+// as it can change between pages.
+func packSizeFiltersHash(filter repositories.PackSizeFilter) string {
+	active := ""
+	if filter.Active != nil {
+		active = fmt.Sprint(*filter.Active)
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("product_id=%d&active=%s&min_size=%d&max_size=%d", filter.ProductID, active, filter.MinSize, filter.MaxSize)))
+	return hex.EncodeToString(sum[:8])
+}
+
+func packSizeSortKey(pack entities.PackSize, sortBy repositories.PackSizeSort) int64 {
+	switch sortBy {
+	case repositories.PackSizeSortProductID:
+		return int64(pack.ProductID)
+	case repositories.PackSizeSortSize:
+		return int64(pack.Size)
+	default:
+		return pack.ID
+	}
+}
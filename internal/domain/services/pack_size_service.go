@@ -2,22 +2,41 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"math"
+	"log/slog"
 	"order-pack-calculator/internal/domain/dto"
 	"order-pack-calculator/internal/domain/entities"
+	errs "order-pack-calculator/internal/domain/errors"
 	"slices"
+	"time"
 
 	"order-pack-calculator/internal/domain/repositories"
+	"order-pack-calculator/internal/metrics"
+	"order-pack-calculator/internal/tracing"
+	"order-pack-calculator/pkg/packing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// tracerName is the instrumentation scope of the spans started by the services
+const tracerName = "order-pack-calculator/internal/domain/services"
+
+// Number of pack sizes returned by GetAll when no limit is requested
+const defaultPackSizeListLimit = 50
+
 // Constructor for PackSizeService
-func NewPackSizeService(packSizeRepository repositories.PackSizeRepository) PackSizeService {
-	return packSizeService{packSizeRepository: packSizeRepository}
+func NewPackSizeService(packSizeRepository repositories.PackSizeRepository, orderRepository repositories.OrderRepository, unitOfWork repositories.UnitOfWork, limits Limits) PackSizeService {
+	return packSizeService{packSizeRepository: packSizeRepository, orderRepository: orderRepository, unitOfWork: unitOfWork, limits: limits}
 }
 
 type packSizeService struct {
 	packSizeRepository repositories.PackSizeRepository
+	orderRepository    repositories.OrderRepository
+	unitOfWork         repositories.UnitOfWork
+	limits             Limits
 }
 
 // Creates a new pack size entry
@@ -25,126 +44,262 @@ func (p packSizeService) Create(ctx context.Context, request dto.CreatePackSizeR
 	packSize := entities.PackSize{
 		ProductID: request.ProductID,
 		Size:      request.Size,
+		ValidFrom: request.ValidFrom,
+		ValidTo:   request.ValidTo,
+	}
+
+	if err := authorizeProduct(ctx, packSize.ProductID); err != nil {
+		return nil, fmt.Errorf("could not create pack size. %w", err)
+	}
+	if err := p.limits.validatePackSize(packSize.ProductID, packSize.Size); err != nil {
+		return nil, fmt.Errorf("could not create pack size. %w", err)
+	}
+	if err := validateValidityPeriod(packSize); err != nil {
+		return nil, fmt.Errorf("could not create pack size. %w", err)
 	}
 
 	saved, err := p.packSizeRepository.Create(ctx, packSize)
 	if err != nil {
 		return nil, fmt.Errorf("could not update pack size. %w", err)
 	}
+	slog.InfoContext(ctx, "pack size created", "pack_size_id", saved.ID, "product_id", saved.ProductID, "size", saved.Size)
 
 	response := dto.PackSizeResponseFromEntity(*saved)
 	return &response, nil
 }
 
-// Updates an existing pack size
-func (p packSizeService) Update(ctx context.Context, request dto.UpdatePackSizeRequest) error {
-	packSize, err := p.packSizeRepository.GetByID(ctx, request.ID)
+// Updates an existing pack size, rejecting the update when the expected version is stale.
+// The pack size is read and written in one unit of work, locked against concurrent updates.
+func (p packSizeService) Update(ctx context.Context, request dto.UpdatePackSizeRequest) (*dto.PackSizeResponse, error) {
+	var packSize *entities.PackSize
+	err := p.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		var err error
+		packSize, err = p.packSizeRepository.GetByIDForUpdate(ctx, request.ID)
+		if err != nil {
+			return err
+		}
+		if err := authorizeProduct(ctx, packSize.ProductID); err != nil {
+			return err
+		}
+		if len(request.Versions) > 0 && !slices.Contains(request.Versions, packSize.Version) {
+			return fmt.Errorf("%w: pack size id=%d is at version %d, not %v", errs.ErrPreconditionFailed, packSize.ID, packSize.Version, request.Versions)
+		}
+
+		// Rows above a limit lowered since they were created can still be deactivated or have their validity edited
+		if request.Size != nil && *request.Size != packSize.Size {
+			if err := p.limits.validatePackSize(packSize.ProductID, *request.Size); err != nil {
+				return err
+			}
+			packSize.Size = *request.Size
+		}
+		if request.Active != nil {
+			packSize.Active = *request.Active
+		}
+		if request.ValidFrom.Set {
+			packSize.ValidFrom = request.ValidFrom.Time
+		}
+		if request.ValidTo.Set {
+			packSize.ValidTo = request.ValidTo.Time
+		}
+
+		if err := validateValidityPeriod(*packSize); err != nil {
+			return err
+		}
+
+		// The repository only applies the update if the version is still the one read above
+		return p.packSizeRepository.Update(ctx, *packSize)
+	})
 	if err != nil {
-		return fmt.Errorf("could not update pack size. %w", err)
+		return nil, fmt.Errorf("could not update pack size. %w", err)
 	}
+	packSize.Version++
+	slog.InfoContext(ctx, "pack size updated", "pack_size_id", packSize.ID, "product_id", packSize.ProductID, "size", packSize.Size, "active", packSize.Active, "version", packSize.Version)
 
-	if request.Size != nil {
-		packSize.Size = *request.Size
-	}
-	if request.Active != nil {
-		packSize.Active = *request.Active
-	}
+	response := dto.PackSizeResponseFromEntity(*packSize)
+	return &response, nil
+}
 
-	err = p.packSizeRepository.Update(ctx, *packSize)
+// Retrieves a pack size
+func (p packSizeService) GetByID(ctx context.Context, ID int64) (*dto.PackSizeResponse, error) {
+	packSize, err := p.packSizeRepository.GetByID(ctx, ID)
 	if err != nil {
-		return fmt.Errorf("could not update pack size. %w", err)
+		return nil, fmt.Errorf("could not fetch pack size. %w", err)
+	}
+	if err := authorizeProduct(ctx, packSize.ProductID); err != nil {
+		return nil, fmt.Errorf("could not fetch pack size. %w", err)
 	}
 
-	return nil
+	response := dto.PackSizeResponseFromEntity(*packSize)
+	return &response, nil
 }
 
 // Retrieves all pack sizes
-func (p packSizeService) GetAll(ctx context.Context) ([]dto.PackSizeResponse, error) {
-	packSizes, err := p.packSizeRepository.GetAll(ctx)
+func (p packSizeService) GetAll(ctx context.Context, request dto.ListPackSizesRequest) (*dto.PackSizePageResponse, error) {
+	if err := authorizeProductFilter(ctx, request.ProductID); err != nil {
+		return nil, fmt.Errorf("could not fetch pack size. %w", err)
+	}
+
+	filter := repositories.PackSizeFilter{
+		ProductID:  request.ProductID,
+		Active:     request.Active,
+		MinSize:    request.MinSize,
+		MaxSize:    request.MaxSize,
+		SortBy:     repositories.PackSizeSort(request.Sort),
+		Descending: request.Order == "desc",
+		Limit:      request.Limit,
+	}
+	if filter.SortBy == "" {
+		filter.SortBy = repositories.PackSizeSortID
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultPackSizeListLimit
+	}
+	if request.Cursor != "" {
+		after, err := decodePackSizeCursor(request.Cursor, filter)
+		if err != nil {
+			return nil, fmt.Errorf("could not fetch pack size. %w", err)
+		}
+		filter.After = after
+	}
+
+	total, err := p.packSizeRepository.Count(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("could not fetch pack size. %w", err)
 	}
-	responses := dto.PackSizeResponseFromEntities(packSizes)
-	return responses, nil
+
+	// One extra row tells whether there is a next page
+	page := filter
+	page.Limit++
+	packSizes, err := p.packSizeRepository.GetAll(ctx, page)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch pack size. %w", err)
+	}
+
+	response := &dto.PackSizePageResponse{Total: total}
+	if len(packSizes) > filter.Limit {
+		packSizes = packSizes[:filter.Limit]
+		response.NextCursor = encodePackSizeCursor(packSizes[len(packSizes)-1], filter)
+	}
+	response.Items = dto.PackSizeResponseFromEntities(packSizes)
+	return response, nil
 }
 
-// Calculate optimal pack sizes for an order
-func (p packSizeService) CalcOptimalPacks(ctx context.Context, order dto.CalculatePackSizesRequest) (*dto.OptimalPackSizesResponse, error) {
-	packSizes, err := p.packSizeRepository.GetSizesByProductID(ctx, int64(order.ProductID))
+// Calculate optimal pack sizes for an order and store the calculation as an order
+func (p packSizeService) CalcOptimalPacks(ctx context.Context, order dto.CalculatePackSizesRequest) (_ *dto.OptimalPackSizesResponse, err error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "PackSizeService.CalcOptimalPacks", trace.WithAttributes(
+		attribute.Int("product.id", order.ProductID),
+		attribute.Int("order.quantity", order.OrderQuantity),
+	))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	calculated, solution, err := calculateOrder(ctx, p.packSizeRepository, p.limits, order)
 	if err != nil {
-		return nil, fmt.Errorf("could not fetch pack sizes. %w", err)
+		return nil, err
 	}
+	span.SetAttributes(
+		attribute.IntSlice("pack.sizes", calculated.PackSizes),
+		attribute.Int("order.total_items", solution.TotalItems),
+		attribute.Int("order.total_packs", solution.TotalPacks),
+	)
+
+	saved, err := p.orderRepository.Create(ctx, calculated)
+	if err != nil {
+		return nil, fmt.Errorf("could not save order. %w", err)
+	}
+	solution.OrderID = saved.ID
+	span.SetAttributes(attribute.Int64("order.id", saved.ID))
+	slog.InfoContext(ctx, "order calculated", "order_id", saved.ID, "product_id", saved.ProductID, "order_quantity", saved.OrderQuantity,
+		"total_items", saved.TotalItems, "total_packs", saved.TotalPacks)
 
-	solution := p.calcOptimalPacks(order, packSizes)
 	return solution, nil
 }
 
-// Core logic: calculates optimal pack combination using dynamic programming
-func (packSizeService) calcOptimalPacks(order dto.CalculatePackSizesRequest, packSizes []int) *dto.OptimalPackSizesResponse {
-	maxPackSize := slices.Max(packSizes)
-	limit := order.OrderQuantity + maxPackSize
+// Fetches the pack sizes effective at the requested instant and solves the order against them
+func calculateOrder(ctx context.Context, packSizeRepository repositories.PackSizeRepository, limits Limits, request dto.CalculatePackSizesRequest) (entities.Order, *dto.OptimalPackSizesResponse, error) {
+	if err := authorizeProduct(ctx, request.ProductID); err != nil {
+		return entities.Order{}, nil, fmt.Errorf("could not calculate packs. %w", err)
+	}
+	if err := limits.validateOrderQuantity(request.ProductID, request.OrderQuantity); err != nil {
+		return entities.Order{}, nil, fmt.Errorf("could not calculate packs. %w", err)
+	}
+	metrics.ObserveOrderQuantity(request.OrderQuantity)
 
-	// dp[i] holds the best solution to exactly pack i items
-	dp := make([]*dto.OptimalPackSizesResponse, limit+1)
-	dp[0] = &dto.OptimalPackSizesResponse{} // base case: 0 items needs 0 packs
+	asOf := time.Now()
+	if request.AsOf != nil {
+		asOf = *request.AsOf
+	}
 
-	for i := 0; i <= limit; i++ {
-		if dp[i] == nil {
-			continue
-		}
-		for _, size := range packSizes {
-			next := i + size
-			if next > limit {
-				continue
-			}
+	packSizes, err := packSizeRepository.GetSizesByProductID(ctx, int64(request.ProductID), asOf)
+	if err != nil {
+		return entities.Order{}, nil, fmt.Errorf("could not fetch pack sizes. %w", err)
+	}
+	if len(packSizes) == 0 {
+		return entities.Order{}, nil, fmt.Errorf("%w: no pack sizes available for product_id=%d as of %s", errs.ErrNotFound, request.ProductID, asOf.Format(time.RFC3339))
+	}
 
-			// Clone current pack combination and add one more pack of current size
-			newCombo := cloneCombination(dp[i].PackCombination)
-			addToCombination(&newCombo, size)
+	solution, err := SolvePacks(request.OrderQuantity, packSizes)
+	if err != nil {
+		return entities.Order{}, nil, fmt.Errorf("could not calculate packs. %w", err)
+	}
+	return newOrder(request, asOf, packSizes, solution), solution, nil
+}
 
-			newSolution := &dto.OptimalPackSizesResponse{
-				PackCombination: newCombo,
-				TotalItems:      next,
-				TotalPacks:      dp[i].TotalPacks + 1,
-			}
+// Builds the order entity recording a calculation and the pack set it was based on
+func newOrder(request dto.CalculatePackSizesRequest, asOf time.Time, packSizes []int, solution *dto.OptimalPackSizesResponse) entities.Order {
+	snapshot := slices.Clone(packSizes)
+	slices.Sort(snapshot)
 
-			// Update dp[next] if it's a better solution (fewer items or fewer packs)
-			if dp[next] == nil ||
-				newSolution.TotalItems < dp[next].TotalItems ||
-				(newSolution.TotalItems == dp[next].TotalItems && newSolution.TotalPacks < dp[next].TotalPacks) {
-				dp[next] = newSolution
-			}
-		}
+	packs := make([]entities.OrderPack, 0, len(solution.PackCombination))
+	for _, p := range solution.PackCombination {
+		packs = append(packs, entities.OrderPack{Size: p.Size, Count: p.Count})
 	}
 
-	// Find best valid solution with minimum total items >= order quantity
-	best := &dto.OptimalPackSizesResponse{TotalItems: math.MaxInt64}
-	for i := order.OrderQuantity; i <= limit; i++ {
-		if dp[i] != nil && (dp[i].TotalItems < best.TotalItems ||
-			(dp[i].TotalItems == best.TotalItems && dp[i].TotalPacks < best.TotalPacks)) {
-			best = dp[i]
-		}
+	return entities.Order{
+		ProductID:     request.ProductID,
+		OrderQuantity: request.OrderQuantity,
+		TotalItems:    solution.TotalItems,
+		TotalPacks:    solution.TotalPacks,
+		Objective:     entities.ObjectiveFewestItems,
+		PackSizes:     snapshot,
+		AsOf:          asOf,
+		Packs:         packs,
 	}
-
-	return best
 }
 
-// Deep copies a pack combination slice
-func cloneCombination(combo []dto.PackDetail) []dto.PackDetail {
-	var cloned []dto.PackDetail
-	for _, cb := range combo {
-		cloned = append(cloned, dto.PackDetail{Size: cb.Size, Count: cb.Count})
+// SolvePacks calculates the optimal pack combination for an order quantity from the given pack sizes,
+// without reading or storing anything. The combination lists the largest packs first.
+func SolvePacks(orderQuantity int, packSizes []int) (*dto.OptimalPackSizesResponse, error) {
+	var stats packing.Stats
+	start := time.Now()
+	result, err := packing.Solve(packSizes, orderQuantity, packing.Options{Stats: &stats})
+	if errors.Is(err, packing.ErrQuantityTooLarge) {
+		return nil, &errs.ValidationError{Fields: []errs.FieldError{
+			{Field: "order_quantity", Rule: "max", Message: fmt.Sprintf("is too large for pack sizes of up to %d", slices.Max(packSizes))},
+		}}
+	}
+	if err != nil {
+		return nil, err
+	}
+	metrics.ObserveSolve(stats.Method, stats.TableSize, time.Since(start))
+
+	combination := make([]dto.PackDetail, 0, len(result.Packs))
+	for _, p := range result.Packs {
+		combination = append(combination, dto.PackDetail{Size: p.Size, Count: p.Count})
 	}
-	return cloned
+	return &dto.OptimalPackSizesResponse{
+		PackCombination: combination,
+		TotalItems:      result.TotalItems,
+		TotalPacks:      result.TotalPacks,
+	}, nil
 }
 
-// Adds a pack of the given size to the combination (increments if already exists)
-func addToCombination(combo *[]dto.PackDetail, size int) {
-	for i := range *combo {
-		if (*combo)[i].Size == size {
-			(*combo)[i].Count++
-			return
-		}
+// Ensures the validity window, when bounded on both ends, is not empty
+func validateValidityPeriod(pack entities.PackSize) error {
+	if pack.ValidFrom != nil && pack.ValidTo != nil && !pack.ValidTo.After(*pack.ValidFrom) {
+		return errs.ErrInvalidValidityPeriod
 	}
-	*combo = append(*combo, dto.PackDetail{Size: size, Count: 1})
+	return nil
 }
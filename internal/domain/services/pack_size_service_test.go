@@ -3,13 +3,25 @@ package services
 import (
 	"context"
 	"errors"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 
 	"order-pack-calculator/internal/domain/dto"
 	"order-pack-calculator/internal/domain/entities"
+	errs "order-pack-calculator/internal/domain/errors"
+	"order-pack-calculator/internal/domain/repositories"
+	"order-pack-calculator/pkg/packing"
 
 	"order-pack-calculator/mocks"
 )
@@ -19,7 +31,8 @@ func TestCreate(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := mocks.NewMockPackSizeRepository(ctrl)
-	service := NewPackSizeService(repo)
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	service := NewPackSizeService(repo, orderRepo, mocks.NewMockUnitOfWork(ctrl), Limits{})
 
 	t.Run("success", func(t *testing.T) {
 		req := dto.CreatePackSizeRequest{ProductID: 1, Size: 10}
@@ -36,6 +49,13 @@ func TestCreate(t *testing.T) {
 		assert.Equal(t, 10, resp.Size)
 	})
 
+	t.Run("invalid validity period", func(t *testing.T) {
+		validFrom := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+		validTo := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+		_, err := service.Create(context.Background(), dto.CreatePackSizeRequest{ProductID: 1, Size: 60, ValidFrom: &validFrom, ValidTo: &validTo})
+		assert.ErrorIs(t, err, errs.ErrInvalidValidityPeriod)
+	})
+
 	t.Run("repository error", func(t *testing.T) {
 		repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, errors.New("repo error"))
 		_, err := service.Create(context.Background(), dto.CreatePackSizeRequest{})
@@ -48,7 +68,8 @@ func TestGetAll(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := mocks.NewMockPackSizeRepository(ctrl)
-	service := NewPackSizeService(repo)
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	service := NewPackSizeService(repo, orderRepo, mocks.NewMockUnitOfWork(ctrl), Limits{})
 
 	t.Run("success", func(t *testing.T) {
 
@@ -69,7 +90,11 @@ func TestGetAll(t *testing.T) {
 
 		ctx := context.Background()
 
-		repo.EXPECT().GetAll(ctx).Return(saved, nil)
+		filter := repositories.PackSizeFilter{SortBy: repositories.PackSizeSortID, Limit: defaultPackSizeListLimit}
+		page := filter
+		page.Limit++
+		repo.EXPECT().Count(ctx, filter).Return(int64(2), nil)
+		repo.EXPECT().GetAll(ctx, page).Return(saved, nil)
 
 		expected := []dto.PackSizeResponse{
 			{
@@ -86,15 +111,65 @@ func TestGetAll(t *testing.T) {
 			},
 		}
 
-		resp, err := service.GetAll(ctx)
+		resp, err := service.GetAll(ctx, dto.ListPackSizesRequest{})
 
 		assert.NoError(t, err)
-		assert.ElementsMatch(t, expected, resp)
+		assert.ElementsMatch(t, expected, resp.Items)
+		assert.Equal(t, int64(2), resp.Total)
+		assert.Empty(t, resp.NextCursor)
+	})
+
+	t.Run("paging through results", func(t *testing.T) {
+		ctx := context.Background()
+		request := dto.ListPackSizesRequest{ProductID: 1, Sort: "size", Order: "desc", Limit: 1}
+
+		repo.EXPECT().Count(ctx, gomock.Any()).Return(int64(2), nil)
+		repo.EXPECT().GetAll(ctx, repositories.PackSizeFilter{ProductID: 1, SortBy: repositories.PackSizeSortSize, Descending: true, Limit: 2}).
+			Return([]entities.PackSize{{ID: 2, ProductID: 1, Size: 20}, {ID: 1, ProductID: 1, Size: 10}}, nil)
+
+		first, err := service.GetAll(ctx, request)
+		assert.NoError(t, err)
+		assert.Len(t, first.Items, 1)
+		assert.NotEmpty(t, first.NextCursor)
+
+		request.Cursor = first.NextCursor
+		repo.EXPECT().Count(ctx, gomock.Any()).Return(int64(2), nil)
+		repo.EXPECT().GetAll(ctx, repositories.PackSizeFilter{ProductID: 1, SortBy: repositories.PackSizeSortSize, Descending: true, After: &repositories.PackSizeCursor{Key: 20, ID: 2}, Limit: 2}).
+			Return([]entities.PackSize{{ID: 1, ProductID: 1, Size: 10}}, nil)
+
+		second, err := service.GetAll(ctx, request)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), second.Items[0].ID)
+		assert.Empty(t, second.NextCursor)
+	})
+
+	t.Run("cursor issued for another sort", func(t *testing.T) {
+		cursor := encodePackSizeCursor(entities.PackSize{ID: 2, Size: 20}, repositories.PackSizeFilter{SortBy: repositories.PackSizeSortSize})
+
+		_, err := service.GetAll(context.Background(), dto.ListPackSizesRequest{Cursor: cursor})
+		assert.ErrorIs(t, err, errs.ErrInvalidCursor)
+	})
+
+	t.Run("cursor issued for other filters", func(t *testing.T) {
+		cursor := encodePackSizeCursor(entities.PackSize{ID: 2, Size: 20}, repositories.PackSizeFilter{ProductID: 1, SortBy: repositories.PackSizeSortID})
+
+		_, err := service.GetAll(context.Background(), dto.ListPackSizesRequest{ProductID: 2, Cursor: cursor})
+		assert.ErrorIs(t, err, errs.ErrInvalidCursor)
+
+		active := true
+		_, err = service.GetAll(context.Background(), dto.ListPackSizesRequest{ProductID: 1, Active: &active, Cursor: cursor})
+		assert.ErrorIs(t, err, errs.ErrInvalidCursor)
+	})
+
+	t.Run("malformed cursor", func(t *testing.T) {
+		_, err := service.GetAll(context.Background(), dto.ListPackSizesRequest{Cursor: "not a cursor"})
+		assert.ErrorIs(t, err, errs.ErrInvalidCursor)
 	})
 
 	t.Run("repository error", func(t *testing.T) {
-		repo.EXPECT().GetAll(gomock.Any()).Return(nil, errors.New("repo error"))
-		_, err := service.GetAll(context.Background())
+		repo.EXPECT().Count(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+		repo.EXPECT().GetAll(gomock.Any(), gomock.Any()).Return(nil, errors.New("repo error"))
+		_, err := service.GetAll(context.Background(), dto.ListPackSizesRequest{})
 		assert.Error(t, err)
 	})
 }
@@ -104,7 +179,13 @@ func TestUpdate(t *testing.T) {
 	defer ctrl.Finish()
 
 	repo := mocks.NewMockPackSizeRepository(ctrl)
-	service := NewPackSizeService(repo)
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	service := NewPackSizeService(repo, orderRepo, uow, Limits{})
+
+	uow.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}).AnyTimes()
 
 	t.Run("update size and active", func(t *testing.T) {
 		newSize := 20
@@ -114,38 +195,145 @@ func TestUpdate(t *testing.T) {
 		updated.Size = newSize
 		updated.Active = newActive
 
-		repo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(existing, nil)
+		repo.EXPECT().GetByIDForUpdate(gomock.Any(), int64(1)).Return(existing, nil)
 		repo.EXPECT().Update(gomock.Any(), updated).Return(nil)
 
-		err := service.Update(context.Background(), dto.UpdatePackSizeRequest{
+		res, err := service.Update(context.Background(), dto.UpdatePackSizeRequest{
 			ID:     1,
 			Size:   &newSize,
 			Active: &newActive,
 		})
 		assert.NoError(t, err)
+		assert.Equal(t, newSize, res.Size)
+		assert.Equal(t, int64(1), res.Version)
+	})
+
+	t.Run("update validity period", func(t *testing.T) {
+		validFrom := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+		existing := &entities.PackSize{ID: 1, ProductID: 1, Size: 60, Active: true}
+		updated := *existing
+		updated.ValidFrom = &validFrom
+
+		repo.EXPECT().GetByIDForUpdate(gomock.Any(), int64(1)).Return(existing, nil)
+		repo.EXPECT().Update(gomock.Any(), updated).Return(nil)
+
+		_, err := service.Update(context.Background(), dto.UpdatePackSizeRequest{ID: 1, ValidFrom: dto.SetTime(&validFrom)})
+		assert.NoError(t, err)
+	})
+
+	t.Run("clear validity period", func(t *testing.T) {
+		validFrom := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+		validTo := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+		existing := &entities.PackSize{ID: 1, ProductID: 1, Size: 60, Active: true, ValidFrom: &validFrom, ValidTo: &validTo}
+		updated := *existing
+		updated.ValidTo = nil
+
+		repo.EXPECT().GetByIDForUpdate(gomock.Any(), int64(1)).Return(existing, nil)
+		repo.EXPECT().Update(gomock.Any(), updated).Return(nil)
+
+		_, err := service.Update(context.Background(), dto.UpdatePackSizeRequest{ID: 1, ValidTo: dto.SetTime(nil)})
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid validity period", func(t *testing.T) {
+		validFrom := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+		validTo := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+		existing := &entities.PackSize{ID: 1, ProductID: 1, Size: 60, Active: true, ValidTo: &validFrom}
+
+		repo.EXPECT().GetByIDForUpdate(gomock.Any(), int64(1)).Return(existing, nil)
+
+		_, err := service.Update(context.Background(), dto.UpdatePackSizeRequest{ID: 1, ValidFrom: dto.SetTime(&validTo)})
+		assert.ErrorIs(t, err, errs.ErrInvalidValidityPeriod)
 	})
 
 	t.Run("get by id error", func(t *testing.T) {
-		repo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(nil, errors.New("not found"))
-		err := service.Update(context.Background(), dto.UpdatePackSizeRequest{ID: 1})
+		repo.EXPECT().GetByIDForUpdate(gomock.Any(), int64(1)).Return(nil, errors.New("not found"))
+		_, err := service.Update(context.Background(), dto.UpdatePackSizeRequest{ID: 1})
 		assert.Error(t, err)
 	})
 
+	t.Run("matching version", func(t *testing.T) {
+		version := int64(4)
+		existing := &entities.PackSize{ID: 1, ProductID: 1, Size: 10, Active: true, Version: version}
+
+		repo.EXPECT().GetByIDForUpdate(gomock.Any(), int64(1)).Return(existing, nil)
+		repo.EXPECT().Update(gomock.Any(), *existing).Return(nil)
+
+		res, err := service.Update(context.Background(), dto.UpdatePackSizeRequest{ID: 1, Versions: []int64{version}})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), res.Version)
+	})
+
+	t.Run("any of the versions", func(t *testing.T) {
+		existing := &entities.PackSize{ID: 1, ProductID: 1, Size: 10, Active: true, Version: 4}
+
+		repo.EXPECT().GetByIDForUpdate(gomock.Any(), int64(1)).Return(existing, nil)
+		repo.EXPECT().Update(gomock.Any(), *existing).Return(nil)
+
+		res, err := service.Update(context.Background(), dto.UpdatePackSizeRequest{ID: 1, Versions: []int64{3, 4}})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), res.Version)
+	})
+
+	t.Run("stale version", func(t *testing.T) {
+		version := int64(3)
+		existing := &entities.PackSize{ID: 1, ProductID: 1, Size: 10, Active: true, Version: 4}
+
+		repo.EXPECT().GetByIDForUpdate(gomock.Any(), int64(1)).Return(existing, nil)
+
+		_, err := service.Update(context.Background(), dto.UpdatePackSizeRequest{ID: 1, Versions: []int64{version}})
+		assert.ErrorIs(t, err, errs.ErrPreconditionFailed)
+	})
+
 	t.Run("update error", func(t *testing.T) {
 		pack := &entities.PackSize{ID: 1, ProductID: 1, Size: 10, Active: true}
-		repo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(pack, nil)
+		repo.EXPECT().GetByIDForUpdate(gomock.Any(), int64(1)).Return(pack, nil)
 		repo.EXPECT().Update(gomock.Any(), *pack).Return(errors.New("update failed"))
-		err := service.Update(context.Background(), dto.UpdatePackSizeRequest{ID: 1})
+		_, err := service.Update(context.Background(), dto.UpdatePackSizeRequest{ID: 1})
+		assert.Error(t, err)
+	})
+
+	t.Run("transaction error", func(t *testing.T) {
+		failing := mocks.NewMockUnitOfWork(ctrl)
+		failing.EXPECT().Do(gomock.Any(), gomock.Any()).Return(errors.New("failed to begin transaction"))
+
+		_, err := NewPackSizeService(repo, orderRepo, failing, Limits{}).Update(context.Background(), dto.UpdatePackSizeRequest{ID: 1})
 		assert.Error(t, err)
 	})
 }
 
+func TestGetByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockPackSizeRepository(ctrl)
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	service := NewPackSizeService(repo, orderRepo, mocks.NewMockUnitOfWork(ctrl), Limits{})
+
+	t.Run("success", func(t *testing.T) {
+		repo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&entities.PackSize{ID: 1, ProductID: 1, Size: 10, Active: true, Version: 2}, nil)
+
+		res, err := service.GetByID(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), res.ID)
+		assert.Equal(t, int64(2), res.Version)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		repo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(nil, errs.ErrNotFound)
+
+		_, err := service.GetByID(context.Background(), 2)
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+	})
+}
+
 func TestCalcOptimalPacks(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	repo := mocks.NewMockPackSizeRepository(ctrl)
-	service := NewPackSizeService(repo)
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	service := NewPackSizeService(repo, orderRepo, mocks.NewMockUnitOfWork(ctrl), Limits{})
 
 	tests := []struct {
 		name       string
@@ -198,7 +386,8 @@ func TestCalcOptimalPacks(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			repo.EXPECT().GetSizesByProductID(gomock.Any(), gomock.Any()).Return(tt.packs, nil)
+			repo.EXPECT().GetSizesByProductID(gomock.Any(), gomock.Any(), gomock.Any()).Return(tt.packs, nil)
+			orderRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&entities.Order{ID: 1}, nil)
 			resp, err := service.CalcOptimalPacks(context.Background(), dto.CalculatePackSizesRequest{ProductID: 1, OrderQuantity: tt.orderQty})
 			assert.NoError(t, err)
 			assert.Equal(t, tt.expectResp.TotalItems, resp.TotalItems)
@@ -207,9 +396,222 @@ func TestCalcOptimalPacks(t *testing.T) {
 		})
 	}
 
+	t.Run("as of date", func(t *testing.T) {
+		asOf := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+		repo.EXPECT().GetSizesByProductID(gomock.Any(), int64(1), asOf).Return([]int{60}, nil)
+		orderRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&entities.Order{ID: 1}, nil)
+		resp, err := service.CalcOptimalPacks(context.Background(), dto.CalculatePackSizesRequest{ProductID: 1, OrderQuantity: 100, AsOf: &asOf})
+		assert.NoError(t, err)
+		assert.Equal(t, 120, resp.TotalItems)
+		assert.Equal(t, 2, resp.TotalPacks)
+	})
+
+	t.Run("stores the calculation as an order", func(t *testing.T) {
+		asOf := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+		expected := entities.Order{
+			ProductID:     1,
+			OrderQuantity: 10,
+			TotalItems:    12,
+			TotalPacks:    2,
+			Objective:     entities.ObjectiveFewestItems,
+			PackSizes:     []int{6, 8},
+			AsOf:          asOf,
+			Packs:         []entities.OrderPack{{Size: 6, Count: 2}},
+		}
+		saved := expected
+		saved.ID = 42
+
+		repo.EXPECT().GetSizesByProductID(gomock.Any(), int64(1), asOf).Return([]int{8, 6}, nil)
+		orderRepo.EXPECT().Create(gomock.Any(), expected).Return(&saved, nil)
+
+		resp, err := service.CalcOptimalPacks(context.Background(), dto.CalculatePackSizesRequest{ProductID: 1, OrderQuantity: 10, AsOf: &asOf})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), resp.OrderID)
+	})
+
+	t.Run("order repository error", func(t *testing.T) {
+		repo.EXPECT().GetSizesByProductID(gomock.Any(), gomock.Any(), gomock.Any()).Return([]int{6, 8}, nil)
+		orderRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
+		_, err := service.CalcOptimalPacks(context.Background(), dto.CalculatePackSizesRequest{ProductID: 1, OrderQuantity: 10})
+		assert.Error(t, err)
+	})
+
+	t.Run("no pack sizes available", func(t *testing.T) {
+		repo.EXPECT().GetSizesByProductID(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+		_, err := service.CalcOptimalPacks(context.Background(), dto.CalculatePackSizesRequest{ProductID: 1, OrderQuantity: 10})
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
 	t.Run("repository error", func(t *testing.T) {
-		repo.EXPECT().GetSizesByProductID(gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
+		repo.EXPECT().GetSizesByProductID(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
 		_, err := service.CalcOptimalPacks(context.Background(), dto.CalculatePackSizesRequest{ProductID: 1, OrderQuantity: 10})
 		assert.Error(t, err)
 	})
 }
+
+func TestCalcOptimalPacksSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockPackSizeRepository(ctrl)
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	service := NewPackSizeService(repo, orderRepo, mocks.NewMockUnitOfWork(ctrl), Limits{})
+
+	// The repositories are called in the span, so their statements are traced as its children
+	var repositorySpan trace.SpanContext
+	repo.EXPECT().GetSizesByProductID(gomock.Any(), int64(7), gomock.Any()).DoAndReturn(func(ctx context.Context, _ int64, _ time.Time) ([]int, error) {
+		repositorySpan = trace.SpanContextFromContext(ctx)
+		return []int{53, 31, 23}, nil
+	})
+	orderRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&entities.Order{ID: 42}, nil)
+	_, err := service.CalcOptimalPacks(context.Background(), dto.CalculatePackSizesRequest{ProductID: 7, OrderQuantity: 263})
+	assert.NoError(t, err)
+
+	repo.EXPECT().GetSizesByProductID(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
+	_, err = service.CalcOptimalPacks(context.Background(), dto.CalculatePackSizesRequest{ProductID: 7, OrderQuantity: 10})
+	assert.Error(t, err)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+
+	calculated := spans[0]
+	assert.Equal(t, "PackSizeService.CalcOptimalPacks", calculated.Name())
+	assert.Equal(t, calculated.SpanContext(), repositorySpan)
+	assert.Subset(t, calculated.Attributes(), []attribute.KeyValue{
+		attribute.Int("product.id", 7),
+		attribute.Int("order.quantity", 263),
+		attribute.IntSlice("pack.sizes", []int{23, 31, 53}),
+		attribute.Int("order.total_items", 263),
+		attribute.Int("order.total_packs", 9),
+		attribute.Int64("order.id", 42),
+	})
+	assert.Equal(t, codes.Unset, calculated.Status().Code)
+
+	failed := spans[1]
+	assert.Equal(t, codes.Error, failed.Status().Code)
+	assert.Contains(t, failed.Status().Description, "db error")
+}
+
+func TestLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockPackSizeRepository(ctrl)
+	orderRepo := mocks.NewMockOrderRepository(ctrl)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	limits := Limits{MaxOrderQuantity: 1000, ProductMaxOrderQuantities: map[int]int{3: 5000}, MaxPackSize: 100, ProductMaxPackSizes: map[int]int{2: 50}}
+	service := NewPackSizeService(repo, orderRepo, uow, limits)
+
+	uow.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}).AnyTimes()
+
+	t.Run("zero overrides are not enforced", func(t *testing.T) {
+		zero := Limits{MaxOrderQuantity: 1000, ProductMaxOrderQuantities: map[int]int{1: 0}, MaxPackSize: 100, ProductMaxPackSizes: map[int]int{1: 0}}
+		assert.NoError(t, zero.validateOrderQuantity(1, 500))
+		assert.ErrorIs(t, zero.validateOrderQuantity(1, 1001), errs.ErrValidation)
+		assert.NoError(t, zero.validatePackSize(1, 50))
+		assert.ErrorIs(t, zero.validatePackSize(1, 101), errs.ErrValidation)
+	})
+
+	t.Run("pack size above the maximum", func(t *testing.T) {
+		_, err := service.Create(context.Background(), dto.CreatePackSizeRequest{ProductID: 1, Size: 101})
+		assert.ErrorIs(t, err, errs.ErrValidation)
+
+		var validation *errs.ValidationError
+		assert.ErrorAs(t, err, &validation)
+		assert.Equal(t, []errs.FieldError{{Field: "size", Rule: "max", Message: "must be at most 100 for product 1"}}, validation.Fields)
+	})
+
+	t.Run("pack size above the maximum of the product", func(t *testing.T) {
+		_, err := service.Create(context.Background(), dto.CreatePackSizeRequest{ProductID: 2, Size: 60})
+		assert.ErrorIs(t, err, errs.ErrValidation)
+	})
+
+	t.Run("pack size at the maximum", func(t *testing.T) {
+		repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&entities.PackSize{ID: 1, ProductID: 1, Size: 100}, nil)
+		_, err := service.Create(context.Background(), dto.CreatePackSizeRequest{ProductID: 1, Size: 100})
+		assert.NoError(t, err)
+	})
+
+	t.Run("update above the maximum of the product", func(t *testing.T) {
+		size := 60
+		repo.EXPECT().GetByIDForUpdate(gomock.Any(), int64(1)).Return(&entities.PackSize{ID: 1, ProductID: 2, Size: 10}, nil)
+		_, err := service.Update(context.Background(), dto.UpdatePackSizeRequest{ID: 1, Size: &size})
+		assert.ErrorIs(t, err, errs.ErrValidation)
+	})
+
+	t.Run("update of a pack size above a lowered maximum", func(t *testing.T) {
+		// The limit of product 2 was lowered below the size after the row was created
+		existing := &entities.PackSize{ID: 1, ProductID: 2, Size: 80, Active: true}
+		active := false
+		updated := *existing
+		updated.Active = false
+		repo.EXPECT().GetByIDForUpdate(gomock.Any(), int64(1)).Return(existing, nil)
+		repo.EXPECT().Update(gomock.Any(), updated).Return(nil)
+		_, err := service.Update(context.Background(), dto.UpdatePackSizeRequest{ID: 1, Active: &active})
+		assert.NoError(t, err)
+
+		// Sending the unchanged size is not changing it
+		size := 80
+		existing = &entities.PackSize{ID: 1, ProductID: 2, Size: 80, Active: true}
+		repo.EXPECT().GetByIDForUpdate(gomock.Any(), int64(1)).Return(existing, nil)
+		repo.EXPECT().Update(gomock.Any(), *existing).Return(nil)
+		_, err = service.Update(context.Background(), dto.UpdatePackSizeRequest{ID: 1, Size: &size})
+		assert.NoError(t, err)
+	})
+
+	t.Run("order quantity above the maximum", func(t *testing.T) {
+		_, err := service.CalcOptimalPacks(context.Background(), dto.CalculatePackSizesRequest{ProductID: 1, OrderQuantity: 1001})
+		assert.ErrorIs(t, err, errs.ErrValidation)
+	})
+
+	t.Run("order quantity above the maximum of the product", func(t *testing.T) {
+		_, err := service.CalcOptimalPacks(context.Background(), dto.CalculatePackSizesRequest{ProductID: 3, OrderQuantity: 5001})
+		assert.ErrorIs(t, err, errs.ErrValidation)
+
+		var validation *errs.ValidationError
+		assert.ErrorAs(t, err, &validation)
+		assert.Equal(t, []errs.FieldError{{Field: "order_quantity", Rule: "max", Message: "must be at most 5000 for product 3"}}, validation.Fields)
+	})
+
+	t.Run("order quantity within the maximum of the product", func(t *testing.T) {
+		repo.EXPECT().GetSizesByProductID(gomock.Any(), int64(3), gomock.Any()).Return([]int{1000}, nil)
+		orderRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, order entities.Order) (*entities.Order, error) {
+			order.ID = 1
+			return &order, nil
+		})
+		res, err := service.CalcOptimalPacks(context.Background(), dto.CalculatePackSizesRequest{ProductID: 3, OrderQuantity: 5000})
+		assert.NoError(t, err)
+		assert.Equal(t, 5000, res.TotalItems)
+	})
+
+	t.Run("recalculated quantity above the maximum", func(t *testing.T) {
+		quantity := 1001
+		orderRepo.EXPECT().GetByID(gomock.Any(), int64(7)).Return(&entities.Order{ID: 7, ProductID: 1, OrderQuantity: 10, Status: entities.OrderStatusDraft}, nil)
+		_, err := NewOrderService(orderRepo, repo, limits).Recalculate(context.Background(), 7, dto.RecalculateOrderRequest{OrderQuantity: &quantity})
+		assert.ErrorIs(t, err, errs.ErrValidation)
+	})
+}
+
+func TestSolvePacks(t *testing.T) {
+	res, err := SolvePacks(501, []int{250, 500, 1000})
+	assert.NoError(t, err)
+	assert.Equal(t, []dto.PackDetail{{Size: 500, Count: 1}, {Size: 250, Count: 1}}, res.PackCombination)
+	assert.Equal(t, 750, res.TotalItems)
+	assert.Equal(t, 2, res.TotalPacks)
+	assert.Zero(t, res.OrderID)
+
+	_, err = SolvePacks(0, []int{250})
+	assert.ErrorIs(t, err, packing.ErrInvalidQuantity)
+
+	// Quantities the solver cannot handle are a validation error of the request, not an internal one
+	_, err = SolvePacks(math.MaxInt, []int{250})
+	assert.ErrorIs(t, err, errs.ErrValidation)
+	_, err = SolvePacks(1<<31, []int{50000})
+	assert.ErrorIs(t, err, errs.ErrValidation)
+}
@@ -0,0 +1,32 @@
+package health
+
+import (
+	"context"
+	"fmt"
+)
+
+// Disk checks the free space of the file system holding path. It is degraded when less than
+// degradedBelow of the space is free and down below downBelow, both fractions of the total.
+func Disk(path string, degradedBelow, downBelow float64) Check {
+	return func(ctx context.Context) Result {
+		free, total, err := diskSpace(path)
+		if err != nil {
+			return Failed("failed to read the free space of the disk", fmt.Errorf("%s: %w", path, err))
+		}
+		details := map[string]any{"path": path, "free_bytes": free, "total_bytes": total}
+		if total == 0 {
+			return Up(details)
+		}
+
+		ratio := float64(free) / float64(total)
+		details["free_ratio"] = ratio
+		switch {
+		case ratio < downBelow:
+			return Down(fmt.Sprintf("%.1f%% of the disk is free", ratio*100), details)
+		case ratio < degradedBelow:
+			return Degraded(fmt.Sprintf("%.1f%% of the disk is free", ratio*100), details)
+		default:
+			return Up(details)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin
+
+package health
+
+// diskSpace reports no space on the platforms it cannot read it on, which Disk takes as up
+func diskSpace(path string) (free, total uint64, err error) {
+	return 0, 0, nil
+}
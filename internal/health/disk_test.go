@@ -0,0 +1,30 @@
+package health
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisk(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	result := Disk(dir, 0, 0)(ctx)
+	assert.Equal(t, StatusUp, result.Status)
+	assert.Equal(t, dir, result.Details["path"])
+
+	// No file system has more than all of its space free
+	result = Disk(dir, 1.1, 0)(ctx)
+	assert.Equal(t, StatusDegraded, result.Status)
+	assert.Contains(t, result.Message, "of the disk is free")
+
+	result = Disk(dir, 1.1, 1.1)(ctx)
+	assert.Equal(t, StatusDown, result.Status)
+
+	result = Disk(dir+"/missing", 0.1, 0.02)(ctx)
+	assert.Equal(t, StatusDown, result.Status)
+	assert.NotContains(t, result.Message, dir, "the path is only in the error")
+	assert.ErrorContains(t, result.Err, dir)
+}
@@ -0,0 +1,161 @@
+// Package health runs the checks of the dependencies the service needs to serve requests,
+// reporting whether it is ready to. A failing check never stops the process: it only takes
+// the service out of rotation until the dependency recovers.
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Status is the health of a dependency or of the whole service
+type Status string
+
+const (
+	// StatusUp is a dependency working as expected
+	StatusUp Status = "up"
+	// StatusDegraded is a dependency working with reduced capacity, which does not stop the service from serving
+	StatusDegraded Status = "degraded"
+	// StatusDown is a dependency the service cannot serve without
+	StatusDown Status = "down"
+)
+
+// severity orders the statuses, the worst status of the checks being the status of the service
+var severity = map[Status]int{StatusUp: 0, StatusDegraded: 1, StatusDown: 2}
+
+// Result is the outcome of a check. Only the status and the message are reported to the callers of the
+// readiness probe, which needs no credentials; the error and the details are for the logs of the service.
+type Result struct {
+	Status Status
+	// Message explains a degraded or down status. It must not reveal errors, paths or versions.
+	Message string
+	// Err is the cause of a degraded or down status
+	Err error
+	// Details are statistics describing the dependency
+	Details map[string]any
+}
+
+// Up returns a result with StatusUp
+func Up(details map[string]any) Result {
+	return Result{Status: StatusUp, Details: details}
+}
+
+// Degraded returns a result with StatusDegraded
+func Degraded(message string, details map[string]any) Result {
+	return Result{Status: StatusDegraded, Message: message, Details: details}
+}
+
+// Down returns a result with StatusDown
+func Down(message string, details map[string]any) Result {
+	return Result{Status: StatusDown, Message: message, Details: details}
+}
+
+// Failed returns a result with StatusDown caused by err
+func Failed(message string, err error) Result {
+	return Result{Status: StatusDown, Message: message, Err: err}
+}
+
+// Check reports the health of a dependency. It must return once ctx is done.
+type Check func(ctx context.Context) Result
+
+// Report is the health of the service: the worst status of its checks, and the result of each
+type Report struct {
+	Status Status
+	// Message tells why the service is down when no check is to blame, e.g. it is shutting down
+	Message string
+	Checks  map[string]Result
+}
+
+// Ready tells whether the service can serve requests
+func (r Report) Ready() bool {
+	return r.Status != StatusDown
+}
+
+// DefaultTimeout is the time a check is given before it is reported down
+const DefaultTimeout = 2 * time.Second
+
+// Registry holds the checks the readiness of the service depends on
+type Registry struct {
+	timeout time.Duration
+
+	mu     sync.RWMutex
+	checks map[string]Check
+
+	shuttingDown atomic.Bool
+}
+
+// NewRegistry returns an empty registry giving every check the timeout, DefaultTimeout when zero
+func NewRegistry(timeout time.Duration) *Registry {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Registry{timeout: timeout, checks: map[string]Check{}}
+}
+
+// Register adds a check under the name, replacing any check of the same name
+func (r *Registry) Register(name string, check Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks[name] = check
+}
+
+// Shutdown reports the service down from now on, so load balancers stop sending it requests while it drains
+func (r *Registry) Shutdown() {
+	r.shuttingDown.Store(true)
+}
+
+// Run runs the checks concurrently. Checks taking longer than the timeout of the registry are reported down.
+func (r *Registry) Run(ctx context.Context) Report {
+	r.mu.RLock()
+	checks := make(map[string]Check, len(r.checks))
+	for name, check := range r.checks {
+		checks[name] = check
+	}
+	r.mu.RUnlock()
+
+	report := Report{Status: StatusUp, Checks: make(map[string]Result, len(checks))}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := r.run(ctx, check)
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if severity[result.Status] > severity[report.Status] {
+				report.Status = result.Status
+			}
+		}()
+	}
+	wg.Wait()
+
+	if r.shuttingDown.Load() {
+		report.Status = StatusDown
+		report.Message = "shutting down"
+	}
+	return report
+}
+
+// run runs a single check within the timeout, even when it does not return once its context is done
+func (r *Registry) run(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	done := make(chan Result, 1)
+	go func() { done <- check(ctx) }()
+	select {
+	case result := <-done:
+		if _, ok := severity[result.Status]; !ok {
+			result.Status = StatusDown
+		}
+		return result
+	case <-ctx.Done():
+		return Failed("check timed out", ctx.Err())
+	}
+}
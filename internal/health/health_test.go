@@ -0,0 +1,79 @@
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("no checks", func(t *testing.T) {
+		report := NewRegistry(0).Run(ctx)
+		assert.Equal(t, StatusUp, report.Status)
+		assert.True(t, report.Ready())
+		assert.Empty(t, report.Checks)
+	})
+
+	t.Run("worst status", func(t *testing.T) {
+		registry := NewRegistry(time.Second)
+		registry.Register("database", func(context.Context) Result { return Up(map[string]any{"open_connections": 2}) })
+		registry.Register("disk", func(context.Context) Result { return Degraded("5% of the disk is free", nil) })
+
+		report := registry.Run(ctx)
+		assert.Equal(t, StatusDegraded, report.Status)
+		assert.True(t, report.Ready(), "degraded dependencies do not stop the service from serving")
+		assert.Equal(t, Up(map[string]any{"open_connections": 2}), report.Checks["database"])
+		assert.Equal(t, "5% of the disk is free", report.Checks["disk"].Message)
+
+		registry.Register("cache", func(context.Context) Result { return Down("connection refused", nil) })
+		report = registry.Run(ctx)
+		assert.Equal(t, StatusDown, report.Status)
+		assert.False(t, report.Ready())
+		assert.Len(t, report.Checks, 3)
+	})
+
+	t.Run("register replaces", func(t *testing.T) {
+		registry := NewRegistry(time.Second)
+		registry.Register("database", func(context.Context) Result { return Down("db down", nil) })
+		registry.Register("database", func(context.Context) Result { return Up(nil) })
+		assert.Equal(t, StatusUp, registry.Run(ctx).Status)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		registry := NewRegistry(10 * time.Millisecond)
+		block := make(chan struct{})
+		defer close(block)
+		// The check ignores its context, the registry does not wait for it
+		registry.Register("stuck", func(context.Context) Result {
+			<-block
+			return Up(nil)
+		})
+
+		report := registry.Run(ctx)
+		assert.Equal(t, StatusDown, report.Status)
+		assert.Equal(t, "check timed out", report.Checks["stuck"].Message)
+		assert.ErrorIs(t, report.Checks["stuck"].Err, context.DeadlineExceeded)
+	})
+
+	t.Run("unknown status", func(t *testing.T) {
+		registry := NewRegistry(time.Second)
+		registry.Register("invalid", func(context.Context) Result { return Result{} })
+		assert.Equal(t, StatusDown, registry.Run(ctx).Status)
+	})
+
+	t.Run("shutdown", func(t *testing.T) {
+		registry := NewRegistry(time.Second)
+		registry.Register("database", func(context.Context) Result { return Up(nil) })
+		assert.True(t, registry.Run(ctx).Ready())
+
+		registry.Shutdown()
+		report := registry.Run(ctx)
+		assert.False(t, report.Ready())
+		assert.Equal(t, "shutting down", report.Message)
+		assert.Equal(t, StatusUp, report.Checks["database"].Status, "checks still run while shutting down")
+	})
+}
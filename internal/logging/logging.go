@@ -0,0 +1,113 @@
+// Package logging sets up the structured logger of the service and carries the ID of the
+// request being served in contexts, so every line logged while serving it can be correlated.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Config is the configuration of the logger
+type Config struct {
+	// Level is the least severe level logged
+	Level slog.Level
+	// Format is FormatJSON or FormatText
+	Format string
+}
+
+// ConfigFromEnv reads the configuration from LOG_LEVEL (debug, info, warn or error, info by default)
+// and LOG_FORMAT (json by default, or text)
+func ConfigFromEnv() (Config, error) {
+	config := Config{Level: slog.LevelInfo, Format: FormatJSON}
+	if value := os.Getenv("LOG_LEVEL"); value != "" {
+		if err := config.Level.UnmarshalText([]byte(value)); err != nil {
+			return config, fmt.Errorf("invalid LOG_LEVEL %q: %w", value, err)
+		}
+	}
+	switch format := strings.ToLower(os.Getenv("LOG_FORMAT")); format {
+	case "", FormatJSON:
+	case FormatText:
+		config.Format = FormatText
+	default:
+		return config, fmt.Errorf("invalid LOG_FORMAT %q, expected json or text", format)
+	}
+	return config, nil
+}
+
+// New returns a logger writing to w. Lines logged with a context carrying a request ID are given its request_id,
+// and those logged in a span its trace_id and span_id.
+func New(w io.Writer, config Config) *slog.Logger {
+	options := &slog.HandlerOptions{Level: config.Level}
+	var handler slog.Handler = slog.NewJSONHandler(w, options)
+	if config.Format == FormatText {
+		handler = slog.NewTextHandler(w, options)
+	}
+	return slog.New(contextHandler{handler})
+}
+
+// Setup makes the logger configured in the environment the default one, which the log package
+// writes to as well. An invalid configuration is logged and replaced by the defaults.
+func Setup() {
+	config, err := ConfigFromEnv()
+	slog.SetDefault(New(os.Stderr, config))
+	if err != nil {
+		slog.Warn("ignoring invalid logging configuration", "error", err)
+	}
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the ID of the request being served
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFrom returns the request ID carried by ctx, empty when there is none
+func RequestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// NewRequestID returns a random request ID
+func NewRequestID() string {
+	b := make([]byte, 16)
+	// crypto/rand never fails on the supported platforms
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// contextHandler adds the request ID and the span carried by the context to the lines it handles
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestIDFrom(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		r.AddAttrs(slog.String("trace_id", span.TraceID().String()), slog.String("span_id", span.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,80 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNew(t *testing.T) {
+	var out bytes.Buffer
+	logger := New(&out, Config{Level: slog.LevelInfo, Format: FormatJSON})
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	logger.With("component", "test").InfoContext(ctx, "order calculated", "order_id", 5)
+	logger.DebugContext(ctx, "not logged")
+	logger.Info("no request")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 2)
+
+	var line map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &line))
+	assert.Equal(t, "INFO", line["level"])
+	assert.Equal(t, "order calculated", line["msg"])
+	assert.Equal(t, "req-1", line["request_id"])
+	assert.Equal(t, "test", line["component"])
+	assert.Equal(t, 5.0, line["order_id"])
+
+	assert.NotContains(t, lines[1], "request_id")
+
+	out.Reset()
+	New(&out, Config{Level: slog.LevelDebug, Format: FormatText}).DebugContext(ctx, "calculated")
+	assert.Contains(t, out.String(), "level=DEBUG msg=calculated request_id=req-1")
+
+	// Lines logged in a span carry its IDs
+	out.Reset()
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx = trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}))
+	New(&out, Config{Level: slog.LevelInfo, Format: FormatText}).InfoContext(ctx, "calculated")
+	assert.Contains(t, out.String(), "request_id=req-1 trace_id=4bf92f3577b34da6a3ce929d0e0e4736 span_id=00f067aa0ba902b7")
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "")
+	t.Setenv("LOG_FORMAT", "")
+	config, err := ConfigFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, Config{Level: slog.LevelInfo, Format: FormatJSON}, config)
+
+	t.Setenv("LOG_LEVEL", "debug")
+	t.Setenv("LOG_FORMAT", "TEXT")
+	config, err = ConfigFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, Config{Level: slog.LevelDebug, Format: FormatText}, config)
+
+	t.Setenv("LOG_LEVEL", "verbose")
+	_, err = ConfigFromEnv()
+	assert.Error(t, err)
+
+	t.Setenv("LOG_LEVEL", "warn")
+	t.Setenv("LOG_FORMAT", "xml")
+	_, err = ConfigFromEnv()
+	assert.Error(t, err)
+}
+
+func TestRequestID(t *testing.T) {
+	assert.Empty(t, RequestIDFrom(context.Background()))
+	assert.Equal(t, "req-1", RequestIDFrom(WithRequestID(context.Background(), "req-1")))
+
+	id := NewRequestID()
+	assert.Len(t, id, 32)
+	assert.NotEqual(t, id, NewRequestID())
+}
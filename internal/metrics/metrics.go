@@ -0,0 +1,125 @@
+// Package metrics defines the Prometheus metrics of the service, registered with Registry and
+// served in the Prometheus text format by Handler. The Observe functions record them.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Caches reported by ObserveCache
+const (
+	// CacheETag is the conditional requests answered with 304 Not Modified
+	CacheETag = "etag"
+	// CacheIdempotency is the responses replayed for a known Idempotency-Key
+	CacheIdempotency = "idempotency"
+)
+
+// Registry holds the metrics of the service, along with the Go runtime and process metrics
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests served, by method, route and status.",
+	}, []string{"method", "route", "status"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Time taken to serve HTTP requests, by method, route and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	solveDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "packing_solve_duration_seconds",
+		Help:    "Time taken by the pack solver, by method.",
+		Buckets: prometheus.ExponentialBuckets(0.00001, 4, 10),
+	}, []string{"method"})
+
+	solveTableSize = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "packing_table_size",
+		Help:    "Entries of the dynamic programming table of the pack solver, by method.",
+		Buckets: prometheus.ExponentialBuckets(16, 4, 12),
+	}, []string{"method"})
+
+	orderQuantity = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "order_quantity",
+		Help:    "Quantities of the orders calculated and recalculated.",
+		Buckets: prometheus.ExponentialBuckets(1, 10, 10),
+	})
+
+	cacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_requests_total",
+		Help: "Requests that could be answered from a cache, by cache and result (hit or miss).",
+	}, []string{"cache", "result"})
+
+	db = &dbStats{}
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests, httpRequestDuration, solveDuration, solveTableSize, orderQuantity, cacheRequests, db,
+	)
+}
+
+// Handler serves the metrics of Registry in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// ObserveRequest records an HTTP request served, route being its registered path so that the number of series stays bounded
+func ObserveRequest(method, route string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+	httpRequests.WithLabelValues(method, route, code).Inc()
+	httpRequestDuration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+// ObserveSolve records a run of the pack solver
+func ObserveSolve(method string, tableSize int, duration time.Duration) {
+	solveDuration.WithLabelValues(method).Observe(duration.Seconds())
+	solveTableSize.WithLabelValues(method).Observe(float64(tableSize))
+}
+
+// ObserveOrderQuantity records the quantity of an order calculated
+func ObserveOrderQuantity(quantity int) {
+	orderQuantity.Observe(float64(quantity))
+}
+
+// ObserveCache records whether a request was answered from a cache
+func ObserveCache(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	cacheRequests.WithLabelValues(cache, result).Inc()
+}
+
+// ObserveDB exports the connection pool statistics of the database as the go_sql_* metrics,
+// replacing the database observed before
+func ObserveDB(name string, database *sql.DB) {
+	var collector prometheus.Collector = collectors.NewDBStatsCollector(database, name)
+	db.current.Store(&collector)
+}
+
+// dbStats collects the pool statistics of the database observed last. It is unchecked,
+// describing no metrics, as the database and its name are only known once the storage is opened.
+type dbStats struct {
+	current atomic.Pointer[prometheus.Collector]
+}
+
+func (d *dbStats) Describe(chan<- *prometheus.Desc) {}
+
+func (d *dbStats) Collect(ch chan<- prometheus.Metric) {
+	if collector := d.current.Load(); collector != nil {
+		(*collector).Collect(ch)
+	}
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	_ "modernc.org/sqlite"
+)
+
+// scrape returns the metrics as Prometheus scrapes them
+func scrape(t *testing.T) string {
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	body, _ := io.ReadAll(w.Body)
+	return string(body)
+}
+
+func TestMetrics(t *testing.T) {
+	ObserveRequest(http.MethodPost, "/api/v1/orders/calculate", http.StatusOK, 30*time.Millisecond)
+	ObserveSolve("table", 553, 2*time.Millisecond)
+	ObserveOrderQuantity(500)
+	ObserveCache(CacheETag, true)
+	ObserveCache(CacheETag, false)
+
+	out := scrape(t)
+	for _, line := range []string{
+		`http_requests_total{method="POST",route="/api/v1/orders/calculate",status="200"} 1`,
+		`http_request_duration_seconds_bucket{method="POST",route="/api/v1/orders/calculate",status="200",le="0.05"} 1`,
+		`packing_solve_duration_seconds_count{method="table"} 1`,
+		`packing_table_size_bucket{method="table",le="1024"} 1`,
+		`order_quantity_bucket{le="1000"} 1`,
+		`cache_requests_total{cache="etag",result="hit"} 1`,
+		`cache_requests_total{cache="etag",result="miss"} 1`,
+		`go_goroutines`,
+	} {
+		assert.Contains(t, out, line)
+	}
+	assert.NotContains(t, out, "go_sql_", "no database is observed yet")
+}
+
+func TestObserveDB(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(4)
+
+	ObserveDB("sqlite", db)
+	assert.Contains(t, scrape(t), `go_sql_max_open_connections{db_name="sqlite"} 4`)
+
+	// Observing another database replaces the first one
+	ObserveDB("postgres", db)
+	out := scrape(t)
+	assert.Contains(t, out, `go_sql_max_open_connections{db_name="postgres"} 4`)
+	assert.NotContains(t, out, `db_name="sqlite"`)
+}
@@ -0,0 +1,132 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Buckets are swept of the ones that refilled at most this often
+const sweepInterval = time.Minute
+
+// Decision is the outcome of taking tokens from a bucket
+type Decision struct {
+	Allowed bool
+	// Limited is false when no limit applies, the other fields are then meaningless
+	Limited bool
+	// Limit is the size of the bucket and Remaining the tokens left in it
+	Limit     int
+	Remaining int
+	// Reset is how long the bucket takes to refill completely
+	Reset time.Duration
+	// RetryAfter is how long to wait before the request would be allowed, when it is not
+	RetryAfter time.Duration
+	// Window is how long an empty bucket takes to refill
+	Window time.Duration
+}
+
+// Limiter keeps a token bucket per client and limit. It is safe for concurrent use.
+type Limiter struct {
+	policy *Policy
+	now    func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[bucketKey]*bucket
+	lastSweep time.Time
+}
+
+type bucketKey struct {
+	client string
+	// name tells the buckets of a client apart: the route with a limit of its own, requests or compute
+	name string
+}
+
+type bucket struct {
+	limit  Limit
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter returns a limiter applying the limits of the policy
+func NewLimiter(policy *Policy) *Limiter {
+	return &Limiter{policy: policy, now: time.Now, buckets: map[bucketKey]*bucket{}}
+}
+
+// Policy returns the policy the limiter applies
+func (l *Limiter) Policy() *Policy {
+	return l.policy
+}
+
+// AllowRequest takes a token for a request of the client to a route, given by method and registered path.
+// Routes without a limit of their own share the requests limit of the client.
+func (l *Limiter) AllowRequest(client, route string) Decision {
+	config := l.policy.Config()
+	if limit, ok := config.Routes[route]; ok {
+		return l.take(bucketKey{client: client, name: route}, limit, 1)
+	}
+	return l.take(bucketKey{client: client, name: "requests"}, config.Requests, 1)
+}
+
+// AllowCompute takes as many compute tokens as the order quantity. Quantities larger than the bucket
+// are allowed once it is full and leave it in debt, so the client waits for them to be paid back.
+func (l *Limiter) AllowCompute(client string, quantity int) Decision {
+	return l.take(bucketKey{client: client, name: "compute"}, l.policy.Config().Compute, float64(max(quantity, 1)))
+}
+
+func (l *Limiter) take(key bucketKey, limit Limit, cost float64) Decision {
+	if limit.Unlimited() {
+		return Decision{Allowed: true}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok || b.limit != limit {
+		// New buckets, and buckets whose limit was reloaded, start full
+		b = &bucket{limit: limit, tokens: float64(limit.Burst), last: now}
+		l.buckets[key] = b
+	}
+	b.refill(now)
+
+	decision := Decision{Limited: true, Limit: limit.Burst, Window: seconds(float64(limit.Burst) / limit.Rate)}
+	required := math.Min(cost, float64(limit.Burst))
+	if b.tokens >= required {
+		b.tokens -= cost
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = seconds((required - b.tokens) / limit.Rate)
+	}
+	decision.Remaining = int(math.Max(0, math.Floor(b.tokens)))
+	decision.Reset = seconds((float64(limit.Burst) - b.tokens) / limit.Rate)
+	return decision
+}
+
+// sweep forgets the buckets that refilled, they would start full anyway. The caller must hold the lock.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.limit.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(float64(b.limit.Burst), b.tokens+elapsed*b.limit.Rate)
+		b.last = now
+	}
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
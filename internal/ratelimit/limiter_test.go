@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLimiter(config Config) (*Limiter, *time.Time) {
+	policy := &Policy{}
+	policy.config.Store(&config)
+	limiter := NewLimiter(policy)
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+	return limiter, &now
+}
+
+func TestAllowRequest(t *testing.T) {
+	const calculate = "POST /api/v1/orders/calculate"
+
+	t.Run("token bucket", func(t *testing.T) {
+		limiter, now := newTestLimiter(Config{Requests: Limit{Rate: 1, Burst: 2}})
+
+		first := limiter.AllowRequest("apikey:1", "GET /api/v1/orders/")
+		assert.Equal(t, Decision{Allowed: true, Limited: true, Limit: 2, Remaining: 1, Reset: time.Second, Window: 2 * time.Second}, first)
+		assert.True(t, limiter.AllowRequest("apikey:1", "GET /api/v1/orders/:id").Allowed, "routes share the requests limit")
+
+		denied := limiter.AllowRequest("apikey:1", "GET /api/v1/orders/")
+		assert.False(t, denied.Allowed)
+		assert.Equal(t, 0, denied.Remaining)
+		assert.Equal(t, time.Second, denied.RetryAfter)
+
+		assert.True(t, limiter.AllowRequest("apikey:2", "GET /api/v1/orders/").Allowed, "clients have buckets of their own")
+
+		*now = now.Add(500 * time.Millisecond)
+		assert.False(t, limiter.AllowRequest("apikey:1", "GET /api/v1/orders/").Allowed)
+		*now = now.Add(500 * time.Millisecond)
+		assert.True(t, limiter.AllowRequest("apikey:1", "GET /api/v1/orders/").Allowed)
+	})
+
+	t.Run("route limits", func(t *testing.T) {
+		limiter, _ := newTestLimiter(Config{Requests: Limit{Rate: 100, Burst: 100}, Routes: map[string]Limit{calculate: {Rate: 1, Burst: 1}}})
+
+		assert.True(t, limiter.AllowRequest("ip:10.0.0.1", calculate).Allowed)
+		assert.False(t, limiter.AllowRequest("ip:10.0.0.1", calculate).Allowed)
+		assert.True(t, limiter.AllowRequest("ip:10.0.0.1", "GET /api/v1/orders/").Allowed)
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		limiter, _ := newTestLimiter(Config{})
+		assert.Equal(t, Decision{Allowed: true}, limiter.AllowRequest("apikey:1", calculate))
+	})
+
+	t.Run("reloaded limits apply at once", func(t *testing.T) {
+		limiter, _ := newTestLimiter(Config{Requests: Limit{Rate: 1, Burst: 1}})
+		limiter.AllowRequest("apikey:1", calculate)
+		assert.False(t, limiter.AllowRequest("apikey:1", calculate).Allowed)
+
+		limiter.policy.config.Store(&Config{Requests: Limit{Rate: 10, Burst: 10}})
+		assert.True(t, limiter.AllowRequest("apikey:1", calculate).Allowed)
+	})
+}
+
+func TestAllowCompute(t *testing.T) {
+	limiter, now := newTestLimiter(Config{Compute: Limit{Rate: 1000, Burst: 10000}})
+
+	assert.True(t, limiter.AllowCompute("apikey:1", 6000).Allowed)
+	denied := limiter.AllowCompute("apikey:1", 6000)
+	assert.False(t, denied.Allowed)
+	assert.Equal(t, 4000, denied.Remaining)
+	assert.Equal(t, 2*time.Second, denied.RetryAfter)
+
+	// Quantities above the burst wait for a full bucket and leave it in debt
+	*now = now.Add(6 * time.Second)
+	assert.True(t, limiter.AllowCompute("apikey:1", 50000).Allowed)
+	denied = limiter.AllowCompute("apikey:1", 1)
+	assert.False(t, denied.Allowed)
+	assert.Equal(t, 40*time.Second+time.Millisecond, denied.RetryAfter)
+}
+
+func TestSweep(t *testing.T) {
+	limiter, now := newTestLimiter(Config{Requests: Limit{Rate: 1, Burst: 5}})
+	limiter.AllowRequest("apikey:1", "GET /api/v1/orders/")
+	limiter.AllowRequest("apikey:2", "GET /api/v1/orders/")
+	assert.Len(t, limiter.buckets, 2)
+
+	*now = now.Add(sweepInterval)
+	limiter.AllowRequest("apikey:3", "GET /api/v1/orders/")
+	assert.Len(t, limiter.buckets, 1)
+}
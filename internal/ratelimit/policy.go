@@ -0,0 +1,143 @@
+// Package ratelimit limits how fast each client may call the API, with token buckets refilled
+// at the rates of a policy file. The file is reloaded when it changes, so limits can be tuned
+// on a running service.
+package ratelimit
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the content of a policy file. JSON files are accepted as well, being valid YAML.
+//
+//	requests:            # every route without a limit of its own
+//	  rate: 10           # requests per second
+//	  burst: 20
+//	routes:
+//	  POST /api/v1/orders/calculate:
+//	    rate: 2
+//	    burst: 5
+//	compute:             # order quantity calculated per second
+//	  rate: 100000
+//	  burst: 1000000
+type Config struct {
+	Requests Limit `yaml:"requests"`
+	// Routes overrides the request limit of some routes, by method and path as they are registered
+	Routes map[string]Limit `yaml:"routes"`
+	// Compute bounds the quantity of the orders a client has calculated, whatever the number of requests
+	Compute Limit `yaml:"compute"`
+}
+
+// Limit is a token bucket holding up to Burst tokens, refilled at Rate tokens per second.
+// A zero Rate means no limit.
+type Limit struct {
+	Rate  float64 `yaml:"rate"`
+	Burst int     `yaml:"burst"`
+}
+
+// Unlimited reports whether the limit lets everything through
+func (l Limit) Unlimited() bool {
+	return l.Rate <= 0
+}
+
+// normalize checks the limit and defaults its burst to one second worth of tokens
+func (l Limit) normalize() (Limit, error) {
+	if l.Rate < 0 || l.Burst < 0 || math.IsNaN(l.Rate) || math.IsInf(l.Rate, 0) {
+		return l, fmt.Errorf("rate and burst must be positive, got rate=%v burst=%d", l.Rate, l.Burst)
+	}
+	if l.Rate > 0 && l.Burst == 0 {
+		l.Burst = int(math.Max(1, math.Ceil(l.Rate)))
+	}
+	return l, nil
+}
+
+// LoadConfig reads and checks a policy file
+func LoadConfig(path string) (*Config, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rate limit file %s: %w", path, err)
+	}
+	var config Config
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse rate limit file %s: %w", path, err)
+	}
+
+	if config.Requests, err = config.Requests.normalize(); err != nil {
+		return nil, fmt.Errorf("invalid requests limit in %s: %w", path, err)
+	}
+	if config.Compute, err = config.Compute.normalize(); err != nil {
+		return nil, fmt.Errorf("invalid compute limit in %s: %w", path, err)
+	}
+	for route, limit := range config.Routes {
+		if config.Routes[route], err = limit.normalize(); err != nil {
+			return nil, fmt.Errorf("invalid limit of %s in %s: %w", route, path, err)
+		}
+	}
+	return &config, nil
+}
+
+// Policy holds the configuration of a policy file, reloading it when the file changes
+type Policy struct {
+	path    string
+	config  atomic.Pointer[Config]
+	mu      sync.Mutex
+	modTime time.Time
+}
+
+// NewPolicy loads the policy file at path
+func NewPolicy(path string) (*Policy, error) {
+	p := &Policy{path: path}
+	if err := p.reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Config returns the configuration currently in effect
+func (p *Policy) Config() *Config {
+	return p.config.Load()
+}
+
+// Watch reloads the file whenever it is modified, checking it at every interval. A file that
+// cannot be loaded is logged and the previous configuration is kept.
+func (p *Policy) Watch(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := p.reload(); err != nil {
+			slog.Warn("keeping the previous rate limits", "error", err)
+		}
+	}
+}
+
+// reload loads the file if it changed since it was last loaded
+func (p *Policy) reload() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return fmt.Errorf("failed to read rate limit file %s: %w", p.path, err)
+	}
+	if p.config.Load() != nil && info.ModTime().Equal(p.modTime) {
+		return nil
+	}
+
+	config, err := LoadConfig(p.path)
+	if err != nil {
+		return err
+	}
+	if p.config.Swap(config) != nil {
+		slog.Info("reloaded rate limits", "path", p.path)
+	}
+	p.modTime = info.ModTime()
+	return nil
+}
@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writePolicy(t *testing.T, path, content string, modTime time.Time) {
+	t.Helper()
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	assert.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ratelimits.yaml")
+
+	t.Run("valid", func(t *testing.T) {
+		writePolicy(t, path, `
+requests: {rate: 10, burst: 20}
+routes:
+  POST /api/v1/orders/calculate: {rate: 2.5}
+compute: {rate: 1000, burst: 50000}
+`, time.Now())
+
+		config, err := LoadConfig(path)
+		assert.NoError(t, err)
+		assert.Equal(t, Limit{Rate: 10, Burst: 20}, config.Requests)
+		assert.Equal(t, Limit{Rate: 2.5, Burst: 3}, config.Routes["POST /api/v1/orders/calculate"], "burst defaults to a second of tokens")
+		assert.Equal(t, Limit{Rate: 1000, Burst: 50000}, config.Compute)
+	})
+
+	t.Run("empty file limits nothing", func(t *testing.T) {
+		writePolicy(t, path, ``, time.Now())
+
+		config, err := LoadConfig(path)
+		assert.NoError(t, err)
+		assert.True(t, config.Requests.Unlimited())
+		assert.True(t, config.Compute.Unlimited())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, content := range []string{
+			`requests: [1, 2]`,
+			`requests: {rate: -1}`,
+			`compute: {rate: 1, burst: -5}`,
+			`routes: {"GET /api/v1/orders/": {rate: .nan}}`,
+		} {
+			writePolicy(t, path, content, time.Now())
+			_, err := LoadConfig(path)
+			assert.Error(t, err, content)
+		}
+
+		_, err := LoadConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+		assert.Error(t, err)
+	})
+}
+
+func TestPolicyReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ratelimits.yaml")
+	modTime := time.Now().Add(-time.Hour)
+	writePolicy(t, path, `requests: {rate: 1}`, modTime)
+
+	policy, err := NewPolicy(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, policy.Config().Requests.Rate)
+
+	// Unchanged files are not read again
+	writePolicy(t, path, `requests: {rate: 2}`, modTime)
+	assert.NoError(t, policy.reload())
+	assert.Equal(t, 1.0, policy.Config().Requests.Rate)
+
+	writePolicy(t, path, `requests: {rate: 3}`, modTime.Add(time.Minute))
+	assert.NoError(t, policy.reload())
+	assert.Equal(t, 3.0, policy.Config().Requests.Rate)
+
+	// Invalid changes keep the previous configuration
+	writePolicy(t, path, `requests: {rate: -3}`, modTime.Add(2*time.Minute))
+	assert.Error(t, policy.reload())
+	assert.Equal(t, 3.0, policy.Config().Requests.Rate)
+
+	_, err = NewPolicy(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
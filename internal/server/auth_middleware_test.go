@@ -0,0 +1,125 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"order-pack-calculator/internal/auth"
+	"order-pack-calculator/internal/domain/entities"
+	"order-pack-calculator/internal/domain/repositories/memory"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	keys := memory.NewAPIKeyRepository()
+	key, prefix, hash, _ := auth.NewAPIKey()
+	keys.Create(context.Background(), entities.APIKey{Name: "ci", Prefix: prefix, Hash: hash, CreatedAt: time.Now()})
+	authenticator, err := auth.NewAuthenticator(auth.Config{}, keys)
+	assert.NoError(t, err)
+
+	s := &Server{authenticator: authenticator}
+	var principal auth.Principal
+	r := gin.New()
+	r.ContextWithFallback = true
+	r.GET("/api/v1/orders/", s.authenticate(), func(ctx *gin.Context) {
+		// Services receive the gin context, it must carry the principal
+		principal, _ = auth.PrincipalFrom(ctx)
+		ctx.Status(http.StatusOK)
+	})
+	send := func(header, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/orders/", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("api key header", func(t *testing.T) {
+		principal = auth.Principal{}
+		w := send(APIKeyHeader, key)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, auth.Principal{Subject: "apikey:1", Method: auth.MethodAPIKey, Name: "ci"}, principal)
+	})
+
+	t.Run("api key as bearer token", func(t *testing.T) {
+		w := send("Authorization", "Bearer "+key)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		for name, header := range map[string][2]string{
+			"no credentials": {"", ""},
+			"unknown key":    {APIKeyHeader, auth.APIKeyPrefix + "unknown"},
+			"basic auth":     {"Authorization", "Basic dXNlcjpwYXNz"},
+			"bearer token":   {"Authorization", "Bearer a.b.c"},
+		} {
+			w := send(header[0], header[1])
+			assert.Equal(t, http.StatusUnauthorized, w.Code, name)
+			assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"), name)
+			assert.Contains(t, w.Header().Get("WWW-Authenticate"), "Bearer", name)
+			assert.Contains(t, w.Body.String(), `"code":"unauthenticated"`, name)
+		}
+	})
+}
+
+func TestAuthorizeMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	keys := memory.NewAPIKeyRepository()
+	newKey := func(roles ...string) string {
+		key, prefix, hash, _ := auth.NewAPIKey()
+		keys.Create(context.Background(), entities.APIKey{Name: "test", Prefix: prefix, Hash: hash, Roles: roles, CreatedAt: time.Now()})
+		return key
+	}
+	admin, calculator := newKey(auth.RoleAdmin), newKey(auth.RoleCalculator)
+	authenticator, _ := auth.NewAuthenticator(auth.Config{}, keys)
+
+	newRouter := func(s *Server) *gin.Engine {
+		r := gin.New()
+		r.ContextWithFallback = true
+		v1 := r.Group("/api/v1")
+		if s.authenticator != nil {
+			v1.Use(s.authenticate())
+		}
+		ok := func(ctx *gin.Context) { ctx.Status(http.StatusOK) }
+		v1.POST("/orders/calculate", s.authorize(auth.PermissionCalculate), ok)
+		v1.POST("/packsizes/", s.authorize(auth.PermissionWritePackSizes), ok)
+		return r
+	}
+	send := func(r *gin.Engine, path, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		if key != "" {
+			req.Header.Set(APIKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("permissions of the roles", func(t *testing.T) {
+		r := newRouter(&Server{authenticator: authenticator})
+
+		assert.Equal(t, http.StatusOK, send(r, "/api/v1/orders/calculate", calculator).Code)
+		assert.Equal(t, http.StatusOK, send(r, "/api/v1/orders/calculate", admin).Code)
+		assert.Equal(t, http.StatusOK, send(r, "/api/v1/packsizes/", admin).Code)
+
+		w := send(r, "/api/v1/packsizes/", calculator)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), `"code":"forbidden"`)
+		assert.Contains(t, w.Body.String(), string(auth.PermissionWritePackSizes))
+	})
+
+	t.Run("authentication disabled", func(t *testing.T) {
+		r := newRouter(&Server{})
+		assert.Equal(t, http.StatusOK, send(r, "/api/v1/packsizes/", "").Code)
+	})
+}
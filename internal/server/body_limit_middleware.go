@@ -0,0 +1,27 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	errs "order-pack-calculator/internal/domain/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// limitRequestBody rejects request bodies larger than the configured maximum. Bodies announcing their
+// length are rejected at once, the others fail to be read past the limit and are reported by invalidRequest.
+func (s *Server) limitRequestBody() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if s.maxBodyBytes <= 0 {
+			ctx.Next()
+			return
+		}
+		if ctx.Request.ContentLength > s.maxBodyBytes {
+			ErrResponse(ctx, "request body too large", fmt.Errorf("%w: larger than %d bytes", errs.ErrRequestTooLarge, s.maxBodyBytes))
+			ctx.Abort()
+			return
+		}
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, s.maxBodyBytes)
+		ctx.Next()
+	}
+}
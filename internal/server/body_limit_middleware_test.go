@@ -0,0 +1,46 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"order-pack-calculator/internal/domain/dto"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitRequestBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	s := &Server{maxBodyBytes: 64}
+	r := gin.New()
+	r.Use(s.limitRequestBody())
+	r.POST("/api/v1/orders/calculate", func(ctx *gin.Context) {
+		var request dto.CalculatePackSizesRequest
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			ErrResponse(ctx, "unable to parse request", invalidRequest(err))
+			return
+		}
+		ctx.Status(http.StatusOK)
+	})
+	send := func(body string, chunked bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/calculate", strings.NewReader(body))
+		if chunked {
+			req.ContentLength = -1
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	large := `{"product_id": 1, "order_quantity": 500` + strings.Repeat(" ", 64) + `}`
+
+	assert.Equal(t, http.StatusOK, send(`{"product_id": 1, "order_quantity": 500}`, false).Code)
+
+	for _, chunked := range []bool{false, true} {
+		w := send(large, chunked)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"request_too_large"`)
+	}
+}
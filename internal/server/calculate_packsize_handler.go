@@ -13,16 +13,26 @@ import (
 // @Tags         orders
 // @Accept       json
 // @Produce      json
-// @Param        order  body      dto.CalculatePackSizesRequest  true  "Order details"
+// @Param        order            body      dto.CalculatePackSizesRequest  true   "Order details"
+// @Param        Idempotency-Key  header    string                         false  "Key identifying retries of the same request"
 // @Success      200    {object}  dto.OptimalPackSizesResponse
 // @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      409    {object}  dto.ErrorResponse
+// @Failure      413    {object}  dto.ErrorResponse
+// @Failure      422    {object}  dto.ErrorResponse
+// @Failure      429    {object}  dto.ErrorResponse
 // @Failure      500    {object}  dto.ErrorResponse
+// @Security     APIKey
+// @Security     BearerToken
 // @Router       /api/v1/orders/calculate [post]
 func (s *Server) CalculatePackSizeHandler(ctx *gin.Context) {
 	var order dto.CalculatePackSizesRequest
-	err := ctx.BindJSON(&order)
+	err := ctx.ShouldBindJSON(&order)
 	if err != nil {
-		ErrResponse(ctx, "unable to parse request", err)
+		ErrResponse(ctx, "unable to parse request", invalidRequest(err))
 		return
 	}
 
@@ -9,6 +9,7 @@ import (
 	"order-pack-calculator/internal/domain/dto"
 	"order-pack-calculator/mocks"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
@@ -45,6 +46,33 @@ func TestCalculatePackSizeHandler(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
 
+	t.Run("success - as of date", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockService := mocks.NewMockPackSizeService(ctrl)
+		s := &Server{packSizeService: mockService}
+
+		asOf := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+		reqBody := dto.CalculatePackSizesRequest{ProductID: 1, OrderQuantity: 100, AsOf: &asOf}
+		respBody := &dto.OptimalPackSizesResponse{
+			PackCombination: []dto.PackDetail{{Size: 60, Count: 2}},
+			TotalItems:      120,
+			TotalPacks:      2,
+		}
+
+		mockService.EXPECT().CalcOptimalPacks(gomock.Any(), reqBody).Return(respBody, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/calculate", bytes.NewBufferString(`{"product_id":1,"order_quantity":100,"as_of":"2026-11-01T00:00:00Z"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r, _ := gin.CreateTestContext(w)
+		r.Request = req
+
+		s.CalculatePackSizeHandler(r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
 	t.Run("bad request - invalid json", func(t *testing.T) {
 		s := &Server{}
 
@@ -56,6 +84,8 @@ func TestCalculatePackSizeHandler(t *testing.T) {
 
 		s.CalculatePackSizeHandler(r)
 		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), `"code":"invalid_request"`)
 	})
 
 	t.Run("internal server error - service failure", func(t *testing.T) {
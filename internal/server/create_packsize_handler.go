@@ -13,16 +13,25 @@ import (
 // @Tags         packsizes
 // @Accept       json
 // @Produce      json
-// @Param        packSize  body      dto.CreatePackSizeRequest  true  "Pack size details"
+// @Param        packSize         body      dto.CreatePackSizeRequest      true   "Pack size details"
+// @Param        Idempotency-Key  header    string                         false  "Key identifying retries of the same request"
 // @Success      200       {object}  dto.PackSizeResponse
 // @Failure      400       {object}  dto.ErrorResponse
+// @Failure      401       {object}  dto.ErrorResponse
+// @Failure      403       {object}  dto.ErrorResponse
+// @Failure      409       {object}  dto.ErrorResponse
+// @Failure      413    {object}  dto.ErrorResponse
+// @Failure      422       {object}  dto.ErrorResponse
+// @Failure      429    {object}  dto.ErrorResponse
 // @Failure      500       {object}  dto.ErrorResponse
+// @Security     APIKey
+// @Security     BearerToken
 // @Router       /api/v1/packsizes [post]
 func (s *Server) CreatePackSizeHandler(ctx *gin.Context) {
 	var request dto.CreatePackSizeRequest
-	err := ctx.BindJSON(&request)
+	err := ctx.ShouldBindJSON(&request)
 	if err != nil {
-		ErrResponse(ctx, "unable to parse request", err)
+		ErrResponse(ctx, "unable to parse request", invalidRequest(err))
 		return
 	}
 
@@ -2,28 +2,114 @@ package server
 
 import (
 	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"order-pack-calculator/internal/domain/dto"
 	errs "order-pack-calculator/internal/domain/errors"
+	"order-pack-calculator/internal/logging"
 
 	"github.com/gin-gonic/gin"
 )
 
-func ErrResponse(ctx *gin.Context, message string, err error) {
-	response := dto.ErrorResponse{
-		Message: message,
-		Details: err.Error(),
-	}
-	switch {
-	case errors.Is(err, errs.ErrNotFound):
-		{
-			ctx.JSON(http.StatusBadRequest, response)
-			break
+// ProblemContentType is the media type of error responses
+const ProblemContentType = "application/problem+json"
+
+// problem is an entry of the error catalog
+type problem struct {
+	status int
+	// code is part of the API, it must not change once released
+	code  string
+	title string
+}
+
+var internalProblem = problem{http.StatusInternalServerError, "internal", "Internal server error"}
+
+// problems maps domain errors to the problem reported for them. Errors matching none are internal errors.
+var problems = []struct {
+	err     error
+	problem problem
+}{
+	{errs.ErrRequestTooLarge, problem{http.StatusRequestEntityTooLarge, "request_too_large", "Request body too large"}},
+	{errs.ErrInvalidRequest, problem{http.StatusBadRequest, "invalid_request", "The request is malformed"}},
+	{errs.ErrValidation, problem{http.StatusUnprocessableEntity, "validation_failed", "The request failed validation"}},
+	{errs.ErrInvalidCursor, problem{http.StatusBadRequest, "invalid_cursor", "Invalid pagination cursor"}},
+	{errs.ErrInvalidIdempotencyKey, problem{http.StatusBadRequest, "invalid_idempotency_key", "Invalid idempotency key"}},
+	{errs.ErrUnauthenticated, problem{http.StatusUnauthorized, "unauthenticated", "Authentication required"}},
+	{errs.ErrForbidden, problem{http.StatusForbidden, "forbidden", "Permission denied"}},
+	{errs.ErrNotFound, problem{http.StatusNotFound, "not_found", "Resource not found"}},
+	{errs.ErrInvalidTransition, problem{http.StatusConflict, "invalid_transition", "Invalid order status transition"}},
+	{errs.ErrOrderNotDraft, problem{http.StatusConflict, "order_not_draft", "Order is no longer a draft"}},
+	{errs.ErrIdempotencyKeyInProgress, problem{http.StatusConflict, "idempotency_key_in_progress", "Request still in progress"}},
+	{errs.ErrConflict, problem{http.StatusConflict, "conflict", "Resource was modified concurrently"}},
+	{errs.ErrPreconditionFailed, problem{http.StatusPreconditionFailed, "precondition_failed", "Resource version does not match"}},
+	{errs.ErrInvalidValidityPeriod, problem{http.StatusUnprocessableEntity, "invalid_validity_period", "Invalid validity period"}},
+	{errs.ErrIdempotencyKeyReused, problem{http.StatusUnprocessableEntity, "idempotency_key_reused", "Idempotency key reused"}},
+	{errs.ErrRateLimited, problem{http.StatusTooManyRequests, "rate_limited", "Rate limit exceeded"}},
+	{errs.ErrQuotaExceeded, problem{http.StatusTooManyRequests, "quota_exceeded", "Compute quota exceeded"}},
+}
+
+// problemFor returns the catalog entry of an error
+func problemFor(err error) problem {
+	for _, p := range problems {
+		if errors.Is(err, p.err) {
+			return p.problem
 		}
-	default:
-		{
-			ctx.JSON(http.StatusInternalServerError, response)
-			break
+	}
+	return internalProblem
+}
+
+// ErrResponse writes err as a problem details response. Client errors are detailed with the error itself and
+// the fields failing validation, internal errors only with message, while the error is logged.
+// Responses carry the request ID, so that clients can point at the log lines of their request.
+func ErrResponse(ctx *gin.Context, message string, err error) {
+	p := problemFor(err)
+	response := newProblemResponse(ctx, p, err.Error())
+	var validation *errs.ValidationError
+	if errors.As(err, &validation) {
+		for _, f := range validation.Fields {
+			response.Errors = append(response.Errors, dto.FieldError{Field: f.Field, Rule: f.Rule, Message: f.Message})
 		}
 	}
+	if p.status >= http.StatusInternalServerError {
+		slog.ErrorContext(ctx, message, "method", ctx.Request.Method, "path", ctx.Request.URL.Path, "error", err)
+		response.Detail = message
+	} else {
+		slog.DebugContext(ctx, message, "method", ctx.Request.Method, "path", ctx.Request.URL.Path, "code", p.code, "error", err)
+	}
+
+	ctx.Header("Content-Type", ProblemContentType)
+	ctx.JSON(p.status, response)
+}
+
+func newProblemResponse(ctx *gin.Context, p problem, detail string) dto.ErrorResponse {
+	return dto.ErrorResponse{
+		Type:      "/problems/" + p.code,
+		Title:     p.title,
+		Status:    p.status,
+		Detail:    detail,
+		Instance:  ctx.Request.URL.Path,
+		Code:      p.code,
+		RequestID: logging.RequestIDFrom(ctx),
+	}
+}
+
+// invalidRequest marks an error binding a request. Values breaking the binding rules
+// of the request or not matching the type of their field fail validation like the values
+// breaking a limit of the service, listing the fields. Requests that cannot be decoded at all
+// are malformed, and bodies cut short by limitRequestBody are reported as too large.
+func invalidRequest(err error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return fmt.Errorf("%w: larger than %d bytes", errs.ErrRequestTooLarge, tooLarge.Limit)
+	}
+	if fields := bindingFieldErrors(err); len(fields) > 0 {
+		return &errs.ValidationError{Fields: fields}
+	}
+	return fmt.Errorf("%w: %w", errs.ErrInvalidRequest, err)
+}
+
+// noRouteHandler reports unknown routes as problems too
+func noRouteHandler(ctx *gin.Context) {
+	ErrResponse(ctx, "route not found", fmt.Errorf("%w: no route for %s %s", errs.ErrNotFound, ctx.Request.Method, ctx.Request.URL.Path))
 }
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"order-pack-calculator/internal/domain/dto"
+	errs "order-pack-calculator/internal/domain/errors"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrResponse(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+		detail string
+	}{
+		{name: "invalid request", err: invalidRequest(errors.New("unexpected EOF")), status: http.StatusBadRequest, code: "invalid_request", detail: "invalid request: unexpected EOF"},
+		{name: "request too large", err: invalidRequest(&http.MaxBytesError{Limit: 1024}), status: http.StatusRequestEntityTooLarge, code: "request_too_large", detail: "request body too large: larger than 1024 bytes"},
+		{name: "invalid cursor", err: errs.ErrInvalidCursor, status: http.StatusBadRequest, code: "invalid_cursor", detail: "invalid pagination cursor"},
+		{name: "unauthenticated", err: fmt.Errorf("%w: unknown api key", errs.ErrUnauthenticated), status: http.StatusUnauthorized, code: "unauthenticated"},
+		{name: "forbidden", err: fmt.Errorf("%w: apikey:1 lacks packsizes:write", errs.ErrForbidden), status: http.StatusForbidden, code: "forbidden"},
+		{name: "not found", err: fmt.Errorf("could not get order. %w: order id=5", errs.ErrNotFound), status: http.StatusNotFound, code: "not_found", detail: "could not get order. resource not found: order id=5"},
+		{name: "invalid transition", err: &errs.InvalidTransitionError{OrderID: 5, From: "shipped", To: "cancelled"}, status: http.StatusConflict, code: "invalid_transition"},
+		{name: "order not draft", err: errs.ErrOrderNotDraft, status: http.StatusConflict, code: "order_not_draft"},
+		{name: "conflict", err: errs.ErrConflict, status: http.StatusConflict, code: "conflict"},
+		{name: "idempotency key in progress", err: errs.ErrIdempotencyKeyInProgress, status: http.StatusConflict, code: "idempotency_key_in_progress"},
+		{name: "precondition failed", err: errs.ErrPreconditionFailed, status: http.StatusPreconditionFailed, code: "precondition_failed"},
+		{name: "invalid validity period", err: errs.ErrInvalidValidityPeriod, status: http.StatusUnprocessableEntity, code: "invalid_validity_period"},
+		{name: "idempotency key reused", err: errs.ErrIdempotencyKeyReused, status: http.StatusUnprocessableEntity, code: "idempotency_key_reused"},
+		{name: "rate limited", err: fmt.Errorf("%w: retry in 1s", errs.ErrRateLimited), status: http.StatusTooManyRequests, code: "rate_limited"},
+		{name: "quota exceeded", err: errs.ErrQuotaExceeded, status: http.StatusTooManyRequests, code: "quota_exceeded"},
+		{name: "validation failed", err: &errs.ValidationError{Fields: []errs.FieldError{{Field: "order_quantity", Rule: "max", Message: "must be at most 1000"}}}, status: http.StatusUnprocessableEntity, code: "validation_failed", detail: "validation failed: order_quantity must be at most 1000"},
+		{name: "internal details are not returned", err: errors.New(`pq: relation "orders" does not exist`), status: http.StatusInternalServerError, code: "internal", detail: "unable to get order"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/api/v1/orders/5", nil)
+
+			ErrResponse(ctx, "unable to get order", tt.err)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+
+			var response dto.ErrorResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.code, response.Code)
+			assert.Equal(t, "/problems/"+tt.code, response.Type)
+			assert.Equal(t, tt.status, response.Status)
+			assert.NotEmpty(t, response.Title)
+			assert.Equal(t, "/api/v1/orders/5", response.Instance)
+			if tt.detail != "" {
+				assert.Equal(t, tt.detail, response.Detail)
+			}
+			var validation *errs.ValidationError
+			if errors.As(tt.err, &validation) {
+				assert.Len(t, response.Errors, len(validation.Fields))
+			} else {
+				assert.Empty(t, response.Errors)
+			}
+		})
+	}
+}
+
+func TestNoRoute(t *testing.T) {
+	handler := (&Server{}).RegisterRoutes()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/unknown", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `"code":"not_found"`)
+}
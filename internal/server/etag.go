@@ -0,0 +1,103 @@
+package server
+
+import (
+	"fmt"
+	errs "order-pack-calculator/internal/domain/errors"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// versionETag renders a resource version as a strong entity tag
+func versionETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// ifMatchVersions returns the versions accepted by the If-Match request header, a "*"
+// or a comma-separated list of entity tags. It returns nil when the header is absent or "*".
+// If-Match uses the strong comparison, so weak tags and tags that are not versions never
+// match: a header left without any version is reported as ErrPreconditionFailed.
+func ifMatchVersions(ctx *gin.Context) ([]int64, error) {
+	header := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	tags, err := entityTags(header)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed If-Match %s", errs.ErrPreconditionFailed, header)
+	}
+	var versions []int64
+	for _, tag := range tags {
+		if tag.weak {
+			continue
+		}
+		version, err := strconv.ParseInt(tag.opaque, 10, 64)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("%w: no entity tag of %s can match", errs.ErrPreconditionFailed, header)
+	}
+	return versions, nil
+}
+
+// ifNoneMatch tells whether the If-None-Match header, a "*" or a comma-separated list of entity tags,
+// matches the version. If-None-Match uses the weak comparison, so W/"2" matches version 2.
+// A malformed header matches nothing, and the resource is sent as if it were absent.
+func ifNoneMatch(header string, version int64) bool {
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return true
+	}
+	tags, err := entityTags(header)
+	if err != nil {
+		return false
+	}
+	opaque := strconv.FormatInt(version, 10)
+	for _, tag := range tags {
+		if tag.opaque == opaque {
+			return true
+		}
+	}
+	return false
+}
+
+type entityTag struct {
+	opaque string
+	weak   bool
+}
+
+// entityTags parses a comma-separated list of entity tags, each a quoted string optionally prefixed by W/
+func entityTags(header string) ([]entityTag, error) {
+	var tags []entityTag
+	for rest := header; ; {
+		rest = strings.TrimLeft(rest, " \t,")
+		if rest == "" {
+			return tags, nil
+		}
+
+		var tag entityTag
+		if strings.HasPrefix(rest, "W/") {
+			tag.weak = true
+			rest = rest[len("W/"):]
+		}
+		if !strings.HasPrefix(rest, `"`) {
+			return nil, fmt.Errorf("entity tag is not quoted: %s", rest)
+		}
+		end := strings.IndexByte(rest[1:], '"')
+		if end < 0 {
+			return nil, fmt.Errorf("entity tag is not terminated: %s", rest)
+		}
+		tag.opaque = rest[1 : end+1]
+		tags = append(tags, tag)
+
+		rest = strings.TrimLeft(rest[end+2:], " \t")
+		if rest != "" && rest[0] != ',' {
+			return nil, fmt.Errorf("entity tags are not separated by commas: %s", rest)
+		}
+	}
+}
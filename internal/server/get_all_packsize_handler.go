@@ -2,23 +2,43 @@ package server
 
 import (
 	"net/http"
+	"order-pack-calculator/internal/domain/dto"
 
 	"github.com/gin-gonic/gin"
 )
 
-// CreatePackSizeHandler godoc
+// GetAllPackSizeHandler godoc
 // @Summary      Get All pack sizes
-// @Description  Get All pack sizes
+// @Description  Lists pack sizes a page at a time. Pass the next_cursor of a page as cursor, with the same filters and ordering, to get the following one.
 // @Tags         packsizes
 // @Accept       json
 // @Produce      json
-// @Success      200       {array}  dto.PackSizeResponse
-// @Failure      500       {object}  dto.ErrorResponse
+// @Param        product_id  query     int     false  "Product ID"
+// @Param        active      query     bool    false  "Active flag"
+// @Param        min_size    query     int     false  "Minimum size"
+// @Param        max_size    query     int     false  "Maximum size"
+// @Param        sort        query     string  false  "Sort column (default id)" Enums(id, product_id, size)
+// @Param        order       query     string  false  "Sort direction (default asc)" Enums(asc, desc)
+// @Param        limit       query     int     false  "Maximum number of pack sizes (default 50, max 100)"
+// @Param        cursor      query     string  false  "Cursor of the page to fetch"
+// @Success      200         {object}  dto.PackSizePageResponse
+// @Failure      400         {object}  dto.ErrorResponse
+// @Failure      401         {object}  dto.ErrorResponse
+// @Failure      403         {object}  dto.ErrorResponse
+// @Failure      429    {object}  dto.ErrorResponse
+// @Failure      500         {object}  dto.ErrorResponse
+// @Security     APIKey
+// @Security     BearerToken
 // @Router       /api/v1/packsizes [get]
 func (s *Server) GetAllPackSizeHandler(ctx *gin.Context) {
-	
+	var request dto.ListPackSizesRequest
+	err := ctx.ShouldBindQuery(&request)
+	if err != nil {
+		ErrResponse(ctx, "unable to parse request", invalidRequest(err))
+		return
+	}
 
-	response, err := s.packSizeService.GetAll(ctx)
+	response, err := s.packSizeService.GetAll(ctx, request)
 
 	if err != nil {
 		ErrResponse(ctx, "unable to get pack sizes", err)
@@ -24,12 +24,12 @@ func TestGetAllPackSizeHandler(t *testing.T) {
 		s := &Server{packSizeService: mockService}
 
 	
-		respBody := []dto.PackSizeResponse{{ID: 1, ProductID: 1, Size: 10, Active: true}}
+		respBody := &dto.PackSizePageResponse{Items: []dto.PackSizeResponse{{ID: 1, ProductID: 1, Size: 10, Active: true}}, Total: 1}
 
-		mockService.EXPECT().GetAll(gomock.Any()).Return(respBody, nil)
+		mockService.EXPECT().GetAll(gomock.Any(), dto.ListPackSizesRequest{ProductID: 1, Sort: "size"}).Return(respBody, nil)
 
 	
-		req := httptest.NewRequest(http.MethodGet, "/api/v1/packsizes", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/packsizes?product_id=1&sort=size", nil)
 		req.Header.Set("Content-Type", "application/json")
 		w := httptest.NewRecorder()
 		r, _ := gin.CreateTestContext(w)
@@ -39,6 +39,18 @@ func TestGetAllPackSizeHandler(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
 
+	t.Run("validation failed - invalid sort", func(t *testing.T) {
+		s := &Server{}
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/packsizes?sort=active", nil)
+		w := httptest.NewRecorder()
+		r, _ := gin.CreateTestContext(w)
+		r.Request = req
+
+		s.GetAllPackSizeHandler(r)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
 	t.Run("internal server error - service failure", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
@@ -47,7 +59,7 @@ func TestGetAllPackSizeHandler(t *testing.T) {
 		s := &Server{packSizeService: mockService}
 
 		
-		mockService.EXPECT().GetAll(gomock.Any()).Return(nil, errors.New("db error"))
+		mockService.EXPECT().GetAll(gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
 
 	
 		req := httptest.NewRequest(http.MethodGet, "/api/v1/packsizes", nil)
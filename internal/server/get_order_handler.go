@@ -0,0 +1,41 @@
+package server
+
+import (
+	"net/http"
+	"order-pack-calculator/internal/domain/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetOrderHandler godoc
+// @Summary      Get order
+// @Description  Gets a stored order calculation by ID
+// @Tags         orders
+// @Produce      json
+// @Param        id   path      int  true  "Order ID"
+// @Success      200  {object}  dto.OrderResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      429    {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     APIKey
+// @Security     BearerToken
+// @Router       /api/v1/orders/{id} [get]
+func (s *Server) GetOrderHandler(ctx *gin.Context) {
+	var request dto.GetOrderRequest
+	err := ctx.ShouldBindUri(&request)
+	if err != nil {
+		ErrResponse(ctx, "unable to parse request", invalidRequest(err))
+		return
+	}
+
+	response, err := s.orderService.GetByID(ctx, request.ID)
+
+	if err != nil {
+		ErrResponse(ctx, "unable to get order", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, response)
+}
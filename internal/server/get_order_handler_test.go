@@ -0,0 +1,70 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"order-pack-calculator/internal/domain/dto"
+	"order-pack-calculator/mocks"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOrderHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockService := mocks.NewMockOrderService(ctrl)
+		s := &Server{orderService: mockService}
+
+		respBody := &dto.OrderResponse{ID: 1, ProductID: 1, OrderQuantity: 10, TotalItems: 12, TotalPacks: 2}
+		mockService.EXPECT().GetByID(gomock.Any(), int64(1)).Return(respBody, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/orders/1", nil)
+		w := httptest.NewRecorder()
+		r, _ := gin.CreateTestContext(w)
+		r.Request = req
+		r.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		s.GetOrderHandler(r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("bad request - invalid id", func(t *testing.T) {
+		s := &Server{}
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/orders/abc", nil)
+		w := httptest.NewRecorder()
+		r, _ := gin.CreateTestContext(w)
+		r.Request = req
+		r.Params = gin.Params{{Key: "id", Value: "abc"}}
+
+		s.GetOrderHandler(r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("internal server error - service failure", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockService := mocks.NewMockOrderService(ctrl)
+		s := &Server{orderService: mockService}
+
+		mockService.EXPECT().GetByID(gomock.Any(), int64(1)).Return(nil, errors.New("db error"))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/orders/1", nil)
+		w := httptest.NewRecorder()
+		r, _ := gin.CreateTestContext(w)
+		r.Request = req
+		r.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		s.GetOrderHandler(r)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
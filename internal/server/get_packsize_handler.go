@@ -0,0 +1,56 @@
+package server
+
+import (
+	"net/http"
+	"order-pack-calculator/internal/domain/dto"
+	"order-pack-calculator/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetPackSizeHandler godoc
+// @Summary      Get pack size
+// @Description  Gets a pack size by ID. The ETag header carries its version for use in If-Match.
+// @Tags         packsizes
+// @Produce      json
+// @Param        id             path      int     true   "Pack size ID"
+// @Param        If-None-Match  header    string  false  "ETags of cached versions, weak or strong, or *"
+// @Success      200            {object}  dto.PackSizeResponse
+// @Header       200            {string}  ETag  "Version of the pack size"
+// @Success      304            "Not Modified"
+// @Failure      400            {object}  dto.ErrorResponse
+// @Failure      401            {object}  dto.ErrorResponse
+// @Failure      403            {object}  dto.ErrorResponse
+// @Failure      404            {object}  dto.ErrorResponse
+// @Failure      429    {object}  dto.ErrorResponse
+// @Failure      500            {object}  dto.ErrorResponse
+// @Security     APIKey
+// @Security     BearerToken
+// @Router       /api/v1/packsizes/{id} [get]
+func (s *Server) GetPackSizeHandler(ctx *gin.Context) {
+	var request dto.GetPackSizeRequest
+	err := ctx.ShouldBindUri(&request)
+	if err != nil {
+		ErrResponse(ctx, "unable to parse request", invalidRequest(err))
+		return
+	}
+
+	response, err := s.packSizeService.GetByID(ctx, request.ID)
+
+	if err != nil {
+		ErrResponse(ctx, "unable to get pack size", err)
+		return
+	}
+
+	etag := versionETag(response.Version)
+	ctx.Header("ETag", etag)
+	if header := ctx.GetHeader("If-None-Match"); header != "" {
+		matched := ifNoneMatch(header, response.Version)
+		metrics.ObserveCache(metrics.CacheETag, matched)
+		if matched {
+			ctx.Status(http.StatusNotModified)
+			return
+		}
+	}
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"order-pack-calculator/internal/domain/dto"
+	"order-pack-calculator/mocks"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPackSizeHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockService := mocks.NewMockPackSizeService(ctrl)
+		s := &Server{packSizeService: mockService}
+
+		respBody := &dto.PackSizeResponse{ID: 1, ProductID: 1, Size: 10, Active: true, Version: 3}
+		mockService.EXPECT().GetByID(gomock.Any(), int64(1)).Return(respBody, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/packsizes/1", nil)
+		w := httptest.NewRecorder()
+		r, _ := gin.CreateTestContext(w)
+		r.Request = req
+		r.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		s.GetPackSizeHandler(r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+	})
+
+	for name, header := range map[string]struct {
+		ifNoneMatch string
+		status      int
+	}{
+		"not modified":                   {`"3"`, http.StatusNotModified},
+		"not modified - list":            {`"1", "3"`, http.StatusNotModified},
+		"not modified - any":             {`*`, http.StatusNotModified},
+		"not modified - weak entity tag": {`W/"3"`, http.StatusNotModified},
+		"modified - other versions":      {`"1", W/"2"`, http.StatusOK},
+		"modified - malformed":           {`3`, http.StatusOK},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockPackSizeService(ctrl)
+			s := &Server{packSizeService: mockService}
+
+			respBody := &dto.PackSizeResponse{ID: 1, ProductID: 1, Size: 10, Active: true, Version: 3}
+			mockService.EXPECT().GetByID(gomock.Any(), int64(1)).Return(respBody, nil)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/packsizes/1", nil)
+			req.Header.Set("If-None-Match", header.ifNoneMatch)
+			w := httptest.NewRecorder()
+			r, _ := gin.CreateTestContext(w)
+			r.Request = req
+			r.Params = gin.Params{{Key: "id", Value: "1"}}
+
+			s.GetPackSizeHandler(r)
+			r.Writer.WriteHeaderNow()
+			assert.Equal(t, header.status, w.Code)
+			assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+		})
+	}
+
+	t.Run("bad request - invalid id", func(t *testing.T) {
+		s := &Server{}
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/packsizes/abc", nil)
+		w := httptest.NewRecorder()
+		r, _ := gin.CreateTestContext(w)
+		r.Request = req
+		r.Params = gin.Params{{Key: "id", Value: "abc"}}
+
+		s.GetPackSizeHandler(r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("internal server error - service failure", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockService := mocks.NewMockPackSizeService(ctrl)
+		s := &Server{packSizeService: mockService}
+
+		mockService.EXPECT().GetByID(gomock.Any(), int64(1)).Return(nil, errors.New("db error"))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/packsizes/1", nil)
+		w := httptest.NewRecorder()
+		r, _ := gin.CreateTestContext(w)
+		r.Request = req
+		r.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		s.GetPackSizeHandler(r)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -1,18 +1,70 @@
 package server
 
 import (
+	"context"
+	"log/slog"
 	"net/http"
+	"order-pack-calculator/internal/domain/dto"
+	"order-pack-calculator/internal/health"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-// healthHandler godoc
-// @Summary      Health check
-// @Description  Returns the health status of the application
+// isHealthRoute tells whether the route is one of the probes, which are polled too often to be logged or traced
+func isHealthRoute(route string) bool {
+	return strings.HasPrefix(route, "/api/health")
+}
+
+// liveHandler godoc
+// @Summary      Liveness probe
+// @Description  Reports the process is running and serving requests. It does not check the dependencies of the service, a failing dependency never calls for a restart.
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  dto.HealthResponse
+// @Router       /api/health/live [get]
+func (s *Server) liveHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.HealthResponse{Status: string(health.StatusUp)})
+}
+
+// readyHandler godoc
+// @Summary      Readiness probe
+// @Description  Runs the checks of the dependencies of the service: the database and its schema, the idempotency store and the disk of a SQLite database.
+// @Description  Only the status and a short message of each check are reported, their errors are logged.
+// @Description  The service is ready unless a check is down or it is shutting down. Degraded checks are reported without failing the probe.
+// @Description  /api/health is an alias of this route.
 // @Tags         health
 // @Produce      json
-// @Success      200  {object}  map[string]string
-// @Router       /api/health [get]
-func (s *Server) healthHandler(c *gin.Context) {
-	c.JSON(http.StatusOK, s.dbService.Health())
+// @Success      200  {object}  dto.HealthResponse
+// @Failure      503  {object}  dto.HealthResponse
+// @Router       /api/health/ready [get]
+func (s *Server) readyHandler(c *gin.Context) {
+	report := s.health.Run(c)
+	logChecks(c, report)
+	if !report.Ready() {
+		c.JSON(http.StatusServiceUnavailable, dto.HealthResponseFromReport(report))
+		return
+	}
+	c.JSON(http.StatusOK, dto.HealthResponseFromReport(report))
+}
+
+// logChecks logs the checks that are not up with their error and details, which the probe does not report
+func logChecks(ctx context.Context, report health.Report) {
+	for name, result := range report.Checks {
+		level := slog.LevelWarn
+		switch result.Status {
+		case health.StatusUp:
+			continue
+		case health.StatusDegraded:
+			level = slog.LevelInfo
+		}
+		attrs := []slog.Attr{slog.String("check", name), slog.String("status", string(result.Status)), slog.String("message", result.Message)}
+		if result.Err != nil {
+			attrs = append(attrs, slog.Any("error", result.Err))
+		}
+		if len(result.Details) > 0 {
+			attrs = append(attrs, slog.Any("details", result.Details))
+		}
+		slog.LogAttrs(ctx, level, "health check not up", attrs...)
+	}
 }
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"order-pack-calculator/internal/domain/dto"
+	"order-pack-calculator/internal/health"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	disk := health.Up(nil)
+	registry := health.NewRegistry(0)
+	registry.Register("database", func(context.Context) health.Result { return health.Up(map[string]any{"open_connections": 1}) })
+	registry.Register("disk", func(context.Context) health.Result { return disk })
+	handler := (&Server{health: registry}).RegisterRoutes()
+
+	var body string
+	get := func(path string) (int, dto.HealthResponse) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		body = w.Body.String()
+		var response dto.HealthResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w.Code, response
+	}
+
+	t.Run("ready", func(t *testing.T) {
+		for _, path := range []string{"/api/health/ready", "/api/health"} {
+			code, response := get(path)
+			assert.Equal(t, http.StatusOK, code, path)
+			assert.Equal(t, "up", response.Status)
+			assert.Equal(t, dto.HealthCheckResponse{Status: "up"}, response.Checks["database"], "details are not reported")
+		}
+	})
+
+	t.Run("degraded", func(t *testing.T) {
+		disk = health.Degraded("5.0% of the disk is free", nil)
+		t.Cleanup(func() { disk = health.Up(nil) })
+
+		code, response := get("/api/health/ready")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "degraded", response.Status)
+		assert.Equal(t, dto.HealthCheckResponse{Status: "degraded", Message: "5.0% of the disk is free"}, response.Checks["disk"])
+	})
+
+	t.Run("down", func(t *testing.T) {
+		out := captureLogs(t)
+		disk = health.Failed("failed to read the free space of the disk", errors.New("/var/lib/data: permission denied"))
+		t.Cleanup(func() { disk = health.Up(nil) })
+
+		code, response := get("/api/health/ready")
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, "down", response.Status)
+		assert.Equal(t, "up", response.Checks["database"].Status)
+		assert.Equal(t, dto.HealthCheckResponse{Status: "down", Message: "failed to read the free space of the disk"}, response.Checks["disk"])
+		assert.NotContains(t, body, "/var/lib/data", "errors are not reported to the unauthenticated probe")
+
+		// The probe is logged at debug level, with a warning carrying the error of the failing check
+		lines := logLines(out, "health check not up")
+		if assert.Len(t, lines, 1) {
+			assert.Equal(t, "WARN", lines[0]["level"])
+			assert.Equal(t, "disk", lines[0]["check"])
+			assert.Equal(t, "/var/lib/data: permission denied", lines[0]["error"])
+		}
+		if lines := logLines(out, "request served"); assert.Len(t, lines, 1) {
+			assert.Equal(t, "DEBUG", lines[0]["level"])
+		}
+
+		code, _ = get("/api/health/live")
+		assert.Equal(t, http.StatusOK, code, "a failing dependency does not call for a restart")
+	})
+
+	t.Run("shutting down", func(t *testing.T) {
+		registry.Shutdown()
+
+		code, response := get("/api/health/ready")
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, dto.HealthResponse{Status: "down", Message: "shutting down", Checks: response.Checks}, response)
+
+		code, response = get("/api/health/live")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, dto.HealthResponse{Status: "up"}, response)
+	})
+}
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"order-pack-calculator/internal/auth"
+	"order-pack-calculator/internal/domain/dto"
+	errs "order-pack-calculator/internal/domain/errors"
+	"order-pack-calculator/internal/metrics"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// IdempotencyKeyHeader carries the client supplied key identifying retries of the same request
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from a previous request
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+	// maxIdempotencyRouteLength is the size of the route column the keys are stored under
+	maxIdempotencyRouteLength = 255
+)
+
+// idempotent makes a write route safe to retry: the first response sent for an
+// Idempotency-Key is stored and replayed for later requests with the same key, body and If-Match header.
+// Keys are scoped to the principal sending them, so a response is never replayed to another caller.
+// Requests without the header are passed through untouched.
+func (s *Server) idempotent() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := ctx.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			ctx.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			ErrResponse(ctx, "invalid idempotency key", fmt.Errorf("%w: longer than %d characters", errs.ErrInvalidIdempotencyKey, maxIdempotencyKeyLength))
+			ctx.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(ctx.Request.Body)
+		if err != nil {
+			ErrResponse(ctx, "unable to read request", invalidRequest(err))
+			ctx.Abort()
+			return
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		route := idempotencyScope(ctx, ctx.Request.Method+" "+ctx.FullPath())
+		replay, err := s.idempotencyService.Begin(ctx, key, route, requestHash(body, ctx.GetHeader("If-Match")))
+		if err != nil {
+			ErrResponse(ctx, "unable to process idempotency key", err)
+			ctx.Abort()
+			return
+		}
+		metrics.ObserveCache(metrics.CacheIdempotency, replay != nil)
+		if replay != nil {
+			ctx.Header(IdempotentReplayedHeader, "true")
+			ctx.Data(replay.StatusCode, replay.ContentType, replay.Body)
+			ctx.Abort()
+			return
+		}
+
+		defer func() {
+			if r := recover(); r != nil {
+				s.releaseIdempotencyKey(ctx, key, route)
+				panic(r)
+			}
+		}()
+
+		recorder := &bodyRecorder{ResponseWriter: ctx.Writer}
+		ctx.Writer = recorder
+		ctx.Next()
+
+		// Server errors are not stored so the client can retry them
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			s.releaseIdempotencyKey(ctx, key, route)
+			return
+		}
+
+		err = s.idempotencyService.Complete(ctx, key, route, dto.IdempotentResponse{
+			StatusCode:  status,
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "unable to store response for idempotency key", "key", key, "error", err)
+		}
+	}
+}
+
+// idempotencyScope returns the route a key is stored under, prefixed with the subject of the principal when
+// there is one. Subjects too long for the stored route are replaced by their hash.
+func idempotencyScope(ctx *gin.Context, route string) string {
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		return route
+	}
+	subject := principal.Subject
+	if len(subject)+1+len(route) > maxIdempotencyRouteLength {
+		hash := sha256.Sum256([]byte(subject))
+		subject = "sha256:" + hex.EncodeToString(hash[:])
+	}
+	return subject + " " + route
+}
+
+// requestHash identifies the request replayed for a key: its body, and the version it is conditioned on if any
+func requestHash(body []byte, ifMatch string) string {
+	h := sha256.New()
+	if ifMatch != "" {
+		h.Write([]byte("If-Match: " + ifMatch + "\n"))
+	}
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (s *Server) releaseIdempotencyKey(ctx context.Context, key, route string) {
+	if err := s.idempotencyService.Release(ctx, key, route); err != nil {
+		slog.ErrorContext(ctx, "unable to release idempotency key", "key", key, "error", err)
+	}
+}
+
+// purgeExpiredIdempotencyKeys periodically deletes expired idempotency keys
+func (s *Server) purgeExpiredIdempotencyKeys(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		deleted, err := s.idempotencyService.PurgeExpired(context.Background())
+		if err != nil {
+			slog.Error("unable to purge idempotency keys", "error", err)
+			continue
+		}
+		if deleted > 0 {
+			slog.Info("purged expired idempotency keys", "deleted", deleted)
+		}
+	}
+}
+
+// bodyRecorder keeps a copy of the response body written by the handler
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (b *bodyRecorder) Write(data []byte) (int, error) {
+	b.body.Write(data)
+	return b.ResponseWriter.Write(data)
+}
+
+func (b *bodyRecorder) WriteString(data string) (int, error) {
+	b.body.WriteString(data)
+	return b.ResponseWriter.WriteString(data)
+}
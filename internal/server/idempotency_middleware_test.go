@@ -0,0 +1,181 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"order-pack-calculator/internal/auth"
+	"order-pack-calculator/internal/domain/dto"
+	errs "order-pack-calculator/internal/domain/errors"
+	"order-pack-calculator/internal/domain/repositories/memory"
+	"order-pack-calculator/internal/domain/services"
+	"order-pack-calculator/mocks"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const route = "POST /api/v1/packsizes/"
+
+	newRouter := func(s *Server, status int, calls *int) *gin.Engine {
+		r := gin.New()
+		r.POST("/api/v1/packsizes/", s.idempotent(), func(ctx *gin.Context) {
+			*calls++
+			ctx.JSON(status, gin.H{"id": 1})
+		})
+		return r
+	}
+	send := func(r *gin.Engine, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/packsizes/", bytes.NewBufferString(`{"product_id":1,"size":10}`))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("without key", func(t *testing.T) {
+		s := &Server{}
+		calls := 0
+
+		w := send(newRouter(s, http.StatusOK, &calls), "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("first request stores the response", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockService := mocks.NewMockIdempotencyService(ctrl)
+		s := &Server{idempotencyService: mockService}
+		calls := 0
+
+		mockService.EXPECT().Begin(gomock.Any(), "abc", route, gomock.Any()).Return(nil, nil)
+		mockService.EXPECT().Complete(gomock.Any(), "abc", route, dto.IdempotentResponse{
+			StatusCode:  http.StatusOK,
+			ContentType: "application/json; charset=utf-8",
+			Body:        []byte(`{"id":1}`),
+		}).Return(nil)
+
+		w := send(newRouter(s, http.StatusOK, &calls), "abc")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("retry is replayed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockService := mocks.NewMockIdempotencyService(ctrl)
+		s := &Server{idempotencyService: mockService}
+		calls := 0
+
+		mockService.EXPECT().Begin(gomock.Any(), "abc", route, gomock.Any()).
+			Return(&dto.IdempotentResponse{StatusCode: http.StatusOK, ContentType: "application/json", Body: []byte(`{"id":1}`)}, nil)
+
+		w := send(newRouter(s, http.StatusOK, &calls), "abc")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `{"id":1}`, w.Body.String())
+		assert.Equal(t, "true", w.Header().Get(IdempotentReplayedHeader))
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("key reused with a different body", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockService := mocks.NewMockIdempotencyService(ctrl)
+		s := &Server{idempotencyService: mockService}
+		calls := 0
+
+		mockService.EXPECT().Begin(gomock.Any(), "abc", route, gomock.Any()).Return(nil, errs.ErrIdempotencyKeyReused)
+
+		w := send(newRouter(s, http.StatusOK, &calls), "abc")
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("server error releases the key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockService := mocks.NewMockIdempotencyService(ctrl)
+		s := &Server{idempotencyService: mockService}
+		calls := 0
+
+		mockService.EXPECT().Begin(gomock.Any(), "abc", route, gomock.Any()).Return(nil, nil)
+		mockService.EXPECT().Release(gomock.Any(), "abc", route).Return(errors.New("db error"))
+
+		w := send(newRouter(s, http.StatusInternalServerError, &calls), "abc")
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("key too long", func(t *testing.T) {
+		s := &Server{}
+		calls := 0
+
+		w := send(newRouter(s, http.StatusOK, &calls), strings.Repeat("k", maxIdempotencyKeyLength+1))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, 0, calls)
+	})
+}
+
+func TestIdempotencyScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	s := &Server{idempotencyService: services.NewIdempotencyService(memory.NewIdempotencyKeyRepository(), time.Hour)}
+	calls := 0
+	r := gin.New()
+	r.ContextWithFallback = true
+	r.PATCH("/api/v1/packsizes/", func(ctx *gin.Context) {
+		ctx.Request = ctx.Request.WithContext(auth.WithPrincipal(ctx.Request.Context(), auth.Principal{Subject: ctx.GetHeader("X-Subject")}))
+	}, s.idempotent(), func(ctx *gin.Context) {
+		calls++
+		ctx.JSON(http.StatusOK, gin.H{"subject": ctx.GetHeader("X-Subject"), "call": calls})
+	})
+	send := func(subject, ifMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/packsizes/", bytes.NewBufferString(`{"id":1,"active":false}`))
+		req.Header.Set(IdempotencyKeyHeader, "shared")
+		req.Header.Set("X-Subject", subject)
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send("apikey:1", `"3"`)
+	assert.Equal(t, `{"call":1,"subject":"apikey:1"}`, w.Body.String())
+
+	w = send("apikey:1", `"3"`)
+	assert.Equal(t, "true", w.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, `{"call":1,"subject":"apikey:1"}`, w.Body.String())
+
+	// Another principal sending the same key and body is served, not replayed the response of the first
+	w = send("apikey:2", `"3"`)
+	assert.Empty(t, w.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, `{"call":2,"subject":"apikey:2"}`, w.Body.String())
+
+	// The key conditioned on another version is a different request
+	w = send("apikey:1", `"4"`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, 2, calls)
+
+	// Subjects too long for the stored route are hashed
+	w = send(strings.Repeat("s", 300), "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 3, calls)
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"net/http"
+	"order-pack-calculator/internal/domain/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListOrdersHandler godoc
+// @Summary      List orders
+// @Description  Lists stored order calculations, most recent first
+// @Tags         orders
+// @Produce      json
+// @Param        product_id    query     int     false  "Product ID"
+// @Param        status        query     string  false  "Order status" Enums(draft, confirmed, packed, shipped, cancelled)
+// @Param        created_from  query     string  false  "Created at or after (RFC 3339)"
+// @Param        created_to    query     string  false  "Created before (RFC 3339)"
+// @Param        limit         query     int     false  "Maximum number of orders (default 50, max 100)"
+// @Param        offset        query     int     false  "Number of orders to skip"
+// @Success      200           {array}   dto.OrderResponse
+// @Failure      400           {object}  dto.ErrorResponse
+// @Failure      401           {object}  dto.ErrorResponse
+// @Failure      403           {object}  dto.ErrorResponse
+// @Failure      429    {object}  dto.ErrorResponse
+// @Failure      500           {object}  dto.ErrorResponse
+// @Security     APIKey
+// @Security     BearerToken
+// @Router       /api/v1/orders [get]
+func (s *Server) ListOrdersHandler(ctx *gin.Context) {
+	var request dto.ListOrdersRequest
+	err := ctx.ShouldBindQuery(&request)
+	if err != nil {
+		ErrResponse(ctx, "unable to parse request", invalidRequest(err))
+		return
+	}
+
+	response, err := s.orderService.List(ctx, request)
+
+	if err != nil {
+		ErrResponse(ctx, "unable to list orders", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"order-pack-calculator/internal/domain/dto"
+	"order-pack-calculator/mocks"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListOrdersHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockService := mocks.NewMockOrderService(ctrl)
+		s := &Server{orderService: mockService}
+
+		from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+		expected := dto.ListOrdersRequest{ProductID: 1, CreatedFrom: &from, Limit: 10}
+		respBody := []dto.OrderResponse{{ID: 1, ProductID: 1}}
+		mockService.EXPECT().List(gomock.Any(), expected).Return(respBody, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/orders?product_id=1&created_from=2026-10-01T00:00:00Z&limit=10", nil)
+		w := httptest.NewRecorder()
+		r, _ := gin.CreateTestContext(w)
+		r.Request = req
+
+		s.ListOrdersHandler(r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("validation failed - invalid limit", func(t *testing.T) {
+		s := &Server{}
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/orders?limit=1000", nil)
+		w := httptest.NewRecorder()
+		r, _ := gin.CreateTestContext(w)
+		r.Request = req
+
+		s.ListOrdersHandler(r)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("internal server error - service failure", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockService := mocks.NewMockOrderService(ctrl)
+		s := &Server{orderService: mockService}
+
+		mockService.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/orders", nil)
+		w := httptest.NewRecorder()
+		r, _ := gin.CreateTestContext(w)
+		r.Request = req
+
+		s.ListOrdersHandler(r)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
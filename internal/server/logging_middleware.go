@@ -0,0 +1,92 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"order-pack-calculator/internal/auth"
+	"order-pack-calculator/internal/logging"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// RequestIDHeader carries the ID of a request, accepted from the client or generated, and returned in the response
+	RequestIDHeader = "X-Request-ID"
+
+	maxRequestIDLength = 128
+)
+
+// requestID gives every request an ID, put in its context for the log lines and error responses.
+// IDs sent by clients or proxies are kept when they are reasonable, so a request can be followed across services.
+func requestID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = logging.NewRequestID()
+		}
+		ctx.Header(RequestIDHeader, id)
+		ctx.Request = ctx.Request.WithContext(logging.WithRequestID(ctx.Request.Context(), id))
+		ctx.Next()
+	}
+}
+
+// validRequestID accepts IDs of up to 128 letters, digits and -_.:/ characters
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':', c == '/':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// accessLog logs a line per request once it is served. Health checks are only logged at debug level.
+func accessLog() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		// A failing readiness probe answers 503, the probe handler logs the checks to blame
+		case isHealthRoute(ctx.FullPath()):
+			level = slog.LevelDebug
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		}
+		attrs := []slog.Attr{
+			slog.String("method", ctx.Request.Method),
+			slog.String("path", ctx.Request.URL.Path),
+			slog.String("route", ctx.FullPath()),
+			slog.Int("status", status),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.Int("bytes", max(ctx.Writer.Size(), 0)),
+			slog.String("client_ip", ctx.ClientIP()),
+		}
+		if principal, ok := auth.PrincipalFrom(ctx); ok {
+			attrs = append(attrs, slog.String("principal", principal.Subject))
+		}
+		slog.LogAttrs(ctx, level, "request served", attrs...)
+	}
+}
+
+// recovery turns panics into internal error responses, logging them with their stack
+func recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(ctx *gin.Context, recovered any) {
+		slog.ErrorContext(ctx, "panic while serving request", "method", ctx.Request.Method, "path", ctx.Request.URL.Path,
+			"error", fmt.Sprint(recovered), "stack", string(debug.Stack()))
+		ctx.Header("Content-Type", ProblemContentType)
+		ctx.AbortWithStatusJSON(internalProblem.status, newProblemResponse(ctx, internalProblem, "unexpected error"))
+	})
+}
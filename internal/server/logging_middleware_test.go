@@ -0,0 +1,105 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"order-pack-calculator/internal/domain/dto"
+	errs "order-pack-calculator/internal/domain/errors"
+	"order-pack-calculator/internal/logging"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// captureLogs sends the lines logged during the test to the returned buffer
+func captureLogs(t *testing.T) *bytes.Buffer {
+	var out bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(logging.New(&out, logging.Config{Level: slog.LevelDebug, Format: logging.FormatJSON}))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &out
+}
+
+// logLines decodes the JSON lines logged with the given message
+func logLines(out *bytes.Buffer, msg string) []map[string]any {
+	var lines []map[string]any
+	for _, raw := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var line map[string]any
+		if json.Unmarshal([]byte(raw), &line) == nil && line["msg"] == msg {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	out := captureLogs(t)
+
+	r := gin.New()
+	r.ContextWithFallback = true
+	r.Use(requestID(), accessLog(), recovery())
+	r.GET("/api/v1/orders/:id", func(ctx *gin.Context) {
+		if ctx.Param("id") == "panic" {
+			panic("boom")
+		}
+		ErrResponse(ctx, "unable to get order", fmt.Errorf("%w: order id=5", errs.ErrNotFound))
+	})
+	send := func(id, requestID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/orders/"+id, nil)
+		if requestID != "" {
+			req.Header.Set(RequestIDHeader, requestID)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("request id sent by the client", func(t *testing.T) {
+		out.Reset()
+		w := send("5", "abc-123")
+		assert.Equal(t, "abc-123", w.Header().Get(RequestIDHeader))
+
+		var response dto.ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "abc-123", response.RequestID)
+
+		lines := logLines(out, "request served")
+		assert.Len(t, lines, 1)
+		assert.Equal(t, "abc-123", lines[0]["request_id"])
+		assert.Equal(t, "GET", lines[0]["method"])
+		assert.Equal(t, "/api/v1/orders/:id", lines[0]["route"])
+		assert.Equal(t, 404.0, lines[0]["status"])
+		assert.Len(t, logLines(out, "unable to get order"), 1, "client errors are logged at debug level")
+	})
+
+	t.Run("generated request id", func(t *testing.T) {
+		for _, sent := range []string{"", "no spaces allowed", strings.Repeat("a", 129)} {
+			w := send("5", sent)
+			id := w.Header().Get(RequestIDHeader)
+			assert.Len(t, id, 32, sent)
+			assert.Contains(t, w.Body.String(), `"request_id":"`+id+`"`, sent)
+		}
+	})
+
+	t.Run("panics", func(t *testing.T) {
+		out.Reset()
+		w := send("panic", "abc-456")
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), `"code":"internal"`)
+		assert.Contains(t, w.Body.String(), `"request_id":"abc-456"`)
+
+		lines := logLines(out, "panic while serving request")
+		assert.Len(t, lines, 1)
+		assert.Equal(t, "boom", lines[0]["error"])
+		assert.Equal(t, "abc-456", lines[0]["request_id"])
+		assert.Equal(t, "ERROR", logLines(out, "request served")[0]["level"])
+	})
+}
@@ -0,0 +1,23 @@
+package server
+
+import (
+	"order-pack-calculator/internal/metrics"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// instrument records the count and latency of requests by route and status. Requests matching
+// no route are counted together, so unknown paths cannot add series to the metrics.
+func instrument() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.ObserveRequest(ctx.Request.Method, route, ctx.Writer.Status(), time.Since(start))
+	}
+}
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"order-pack-calculator/internal/metrics"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstrument(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(instrument())
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+	r.GET("/instrumented/:id", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	send := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	send("/instrumented/5")
+	send("/instrumented/6")
+	send("/unknown/path")
+
+	w := send("/metrics")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/plain")
+	body, _ := io.ReadAll(w.Body)
+	assert.Contains(t, string(body), `http_requests_total{method="GET",route="/instrumented/:id",status="200"} 2`)
+	// Unmatched paths share a series so clients cannot grow the label set
+	assert.Contains(t, string(body), `http_requests_total{method="GET",route="unmatched",status="404"}`)
+	assert.NotContains(t, string(body), "/unknown/path")
+}
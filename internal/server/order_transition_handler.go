@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"order-pack-calculator/internal/domain/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConfirmOrderHandler godoc
+// @Summary      Confirm order
+// @Description  Confirms a draft order, freezing its pack combination
+// @Tags         orders
+// @Produce      json
+// @Param        id   path      int  true  "Order ID"
+// @Success      200  {object}  dto.OrderResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      409  {object}  dto.ErrorResponse
+// @Failure      429    {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     APIKey
+// @Security     BearerToken
+// @Router       /api/v1/orders/{id}/confirm [post]
+func (s *Server) ConfirmOrderHandler(ctx *gin.Context) {
+	s.transitionOrder(ctx, s.orderService.Confirm)
+}
+
+// PackOrderHandler godoc
+// @Summary      Pack order
+// @Description  Marks a confirmed order as packed
+// @Tags         orders
+// @Produce      json
+// @Param        id   path      int  true  "Order ID"
+// @Success      200  {object}  dto.OrderResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      409  {object}  dto.ErrorResponse
+// @Failure      429    {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     APIKey
+// @Security     BearerToken
+// @Router       /api/v1/orders/{id}/pack [post]
+func (s *Server) PackOrderHandler(ctx *gin.Context) {
+	s.transitionOrder(ctx, s.orderService.Pack)
+}
+
+// ShipOrderHandler godoc
+// @Summary      Ship order
+// @Description  Marks a packed order as shipped
+// @Tags         orders
+// @Produce      json
+// @Param        id   path      int  true  "Order ID"
+// @Success      200  {object}  dto.OrderResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      409  {object}  dto.ErrorResponse
+// @Failure      429    {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     APIKey
+// @Security     BearerToken
+// @Router       /api/v1/orders/{id}/ship [post]
+func (s *Server) ShipOrderHandler(ctx *gin.Context) {
+	s.transitionOrder(ctx, s.orderService.Ship)
+}
+
+// CancelOrderHandler godoc
+// @Summary      Cancel order
+// @Description  Cancels an order that has not been shipped yet
+// @Tags         orders
+// @Produce      json
+// @Param        id   path      int  true  "Order ID"
+// @Success      200  {object}  dto.OrderResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      409  {object}  dto.ErrorResponse
+// @Failure      429    {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     APIKey
+// @Security     BearerToken
+// @Router       /api/v1/orders/{id}/cancel [post]
+func (s *Server) CancelOrderHandler(ctx *gin.Context) {
+	s.transitionOrder(ctx, s.orderService.Cancel)
+}
+
+// transitionOrder binds the order ID and applies the given lifecycle transition
+func (s *Server) transitionOrder(ctx *gin.Context, transition func(context.Context, int64) (*dto.OrderResponse, error)) {
+	var request dto.GetOrderRequest
+	err := ctx.ShouldBindUri(&request)
+	if err != nil {
+		ErrResponse(ctx, "unable to parse request", invalidRequest(err))
+		return
+	}
+
+	response, err := transition(ctx, request.ID)
+
+	if err != nil {
+		ErrResponse(ctx, "unable to update order status", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, response)
+}
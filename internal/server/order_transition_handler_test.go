@@ -0,0 +1,85 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"order-pack-calculator/internal/domain/dto"
+	errs "order-pack-calculator/internal/domain/errors"
+	"order-pack-calculator/mocks"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderTransitionHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRequest := func(w *httptest.ResponseRecorder, id string) *gin.Context {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/"+id+"/confirm", nil)
+		r, _ := gin.CreateTestContext(w)
+		r.Request = req
+		r.Params = gin.Params{{Key: "id", Value: id}}
+		return r
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockService := mocks.NewMockOrderService(ctrl)
+		s := &Server{orderService: mockService}
+
+		mockService.EXPECT().Confirm(gomock.Any(), int64(1)).Return(&dto.OrderResponse{ID: 1, Status: "confirmed"}, nil)
+		mockService.EXPECT().Pack(gomock.Any(), int64(1)).Return(&dto.OrderResponse{ID: 1, Status: "packed"}, nil)
+		mockService.EXPECT().Ship(gomock.Any(), int64(1)).Return(&dto.OrderResponse{ID: 1, Status: "shipped"}, nil)
+		mockService.EXPECT().Cancel(gomock.Any(), int64(1)).Return(&dto.OrderResponse{ID: 1, Status: "cancelled"}, nil)
+
+		for _, handler := range []gin.HandlerFunc{s.ConfirmOrderHandler, s.PackOrderHandler, s.ShipOrderHandler, s.CancelOrderHandler} {
+			w := httptest.NewRecorder()
+			handler(newRequest(w, "1"))
+			assert.Equal(t, http.StatusOK, w.Code)
+		}
+	})
+
+	t.Run("bad request - invalid id", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		s := &Server{orderService: mocks.NewMockOrderService(ctrl)}
+
+		w := httptest.NewRecorder()
+		s.ConfirmOrderHandler(newRequest(w, "abc"))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("conflict - invalid transition", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockService := mocks.NewMockOrderService(ctrl)
+		s := &Server{orderService: mockService}
+
+		mockService.EXPECT().Ship(gomock.Any(), int64(1)).Return(nil, &errs.InvalidTransitionError{OrderID: 1, From: "draft", To: "shipped"})
+
+		w := httptest.NewRecorder()
+		s.ShipOrderHandler(newRequest(w, "1"))
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("internal server error - service failure", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockService := mocks.NewMockOrderService(ctrl)
+		s := &Server{orderService: mockService}
+
+		mockService.EXPECT().Cancel(gomock.Any(), int64(1)).Return(nil, errors.New("db error"))
+
+		w := httptest.NewRecorder()
+		s.CancelOrderHandler(newRequest(w, "1"))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"order-pack-calculator/internal/auth"
+	errs "order-pack-calculator/internal/domain/errors"
+	"order-pack-calculator/internal/ratelimit"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateLimit rejects the requests of clients that exceeded the request limit of the route.
+// Clients are told their limit and what is left of it in RateLimit-* headers.
+func (s *Server) rateLimit() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if s.rateLimiter == nil {
+			ctx.Next()
+			return
+		}
+
+		decision := s.rateLimiter.AllowRequest(clientKey(ctx), ctx.Request.Method+" "+ctx.FullPath())
+		setRateLimitHeaders(ctx, decision)
+		if !decision.Allowed {
+			ErrResponse(ctx, "rate limit exceeded", fmt.Errorf("%w: retry in %ds", errs.ErrRateLimited, retryAfter(decision)))
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// computeQuota charges the order quantity of a calculation to the compute quota of the client,
+// so a few requests for huge quantities cannot hog the solver. Recalculations keeping the
+// quantity of the order are charged the quantity of the stored order. It must run before idempotent
+// so rejections are not stored.
+func (s *Server) computeQuota() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if s.rateLimiter == nil {
+			ctx.Next()
+			return
+		}
+
+		body, err := io.ReadAll(ctx.Request.Body)
+		if err != nil {
+			ErrResponse(ctx, "unable to read request", invalidRequest(err))
+			ctx.Abort()
+			return
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Malformed bodies are left for the handler to report
+		var request struct {
+			OrderQuantity int `json:"order_quantity"`
+		}
+		_ = json.Unmarshal(body, &request)
+		if request.OrderQuantity == 0 && ctx.Param("id") != "" {
+			request.OrderQuantity = s.storedOrderQuantity(ctx)
+		}
+
+		decision := s.rateLimiter.AllowCompute(clientKey(ctx), request.OrderQuantity)
+		if !decision.Allowed {
+			setRateLimitHeaders(ctx, decision)
+			ErrResponse(ctx, "compute quota exceeded", fmt.Errorf("%w: order quantity %d, retry in %ds", errs.ErrQuotaExceeded, request.OrderQuantity, retryAfter(decision)))
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// storedOrderQuantity returns the quantity of the order the request is about, 0 when it cannot be read,
+// the handler then reporting why
+func (s *Server) storedOrderQuantity(ctx *gin.Context) int {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return 0
+	}
+	order, err := s.orderService.GetByID(ctx, id)
+	if err != nil {
+		return 0
+	}
+	return order.OrderQuantity
+}
+
+// clientKey identifies the client a request is counted against: its principal, or its address when anonymous
+func clientKey(ctx *gin.Context) string {
+	if principal, ok := auth.PrincipalFrom(ctx); ok && principal.Subject != "" {
+		return principal.Subject
+	}
+	return "ip:" + ctx.ClientIP()
+}
+
+// setRateLimitHeaders describes the limit applied to the request as the IETF RateLimit header fields do
+func setRateLimitHeaders(ctx *gin.Context, decision ratelimit.Decision) {
+	if !decision.Limited {
+		return
+	}
+	ctx.Header("RateLimit-Limit", strconv.Itoa(decision.Limit))
+	ctx.Header("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+	ctx.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(decision.Reset)))
+	ctx.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", decision.Limit, ceilSeconds(decision.Window)))
+	if !decision.Allowed {
+		ctx.Header("Retry-After", strconv.Itoa(retryAfter(decision)))
+	}
+}
+
+// retryAfter is the number of seconds to wait before retrying a rejected request, at least one
+func retryAfter(decision ratelimit.Decision) int {
+	return max(ceilSeconds(decision.RetryAfter), 1)
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,187 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"order-pack-calculator/internal/auth"
+	"order-pack-calculator/internal/domain/dto"
+	errs "order-pack-calculator/internal/domain/errors"
+	"order-pack-calculator/internal/ratelimit"
+	"order-pack-calculator/mocks"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRateLimiter(t *testing.T, policy string) *ratelimit.Limiter {
+	path := filepath.Join(t.TempDir(), "ratelimits.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(policy), 0o600))
+	p, err := ratelimit.NewPolicy(path)
+	assert.NoError(t, err)
+	return ratelimit.NewLimiter(p)
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	s := &Server{rateLimiter: newTestRateLimiter(t, `
+requests: {rate: 1, burst: 2}
+routes:
+  POST /api/v1/orders/calculate: {rate: 1, burst: 1}
+`)}
+	r := gin.New()
+	r.ContextWithFallback = true
+	v1 := r.Group("/api/v1", func(ctx *gin.Context) {
+		if subject := ctx.GetHeader("X-Subject"); subject != "" {
+			ctx.Request = ctx.Request.WithContext(auth.WithPrincipal(ctx.Request.Context(), auth.Principal{Subject: subject}))
+		}
+	}, s.rateLimit())
+	v1.GET("/orders/", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	v1.POST("/orders/calculate", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	send := func(method, path, subject string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		if subject != "" {
+			req.Header.Set("X-Subject", subject)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(http.MethodGet, "/api/v1/orders/", "apikey:1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "2;w=2", w.Header().Get("RateLimit-Policy"))
+	assert.Empty(t, w.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/api/v1/orders/", "apikey:1").Code)
+	w = send(http.MethodGet, "/api/v1/orders/", "apikey:1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), `"code":"rate_limited"`)
+
+	// Routes with a limit of their own and other clients are counted apart
+	assert.Equal(t, http.StatusOK, send(http.MethodPost, "/api/v1/orders/calculate", "apikey:1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, send(http.MethodPost, "/api/v1/orders/calculate", "apikey:1").Code)
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/api/v1/orders/", "apikey:2").Code)
+
+	// Anonymous clients are limited by address
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/api/v1/orders/", "").Code)
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/api/v1/orders/", "").Code)
+	assert.Equal(t, http.StatusTooManyRequests, send(http.MethodGet, "/api/v1/orders/", "").Code)
+}
+
+func TestRateLimitForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	send := func(r *gin.Engine, forwardedFor string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/orders/", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	newRouter := func(s *Server) *gin.Engine {
+		r := s.newEngine()
+		r.GET("/api/v1/orders/", s.rateLimit(), func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+		return r
+	}
+
+	t.Run("spoofed by an untrusted peer", func(t *testing.T) {
+		r := newRouter(&Server{rateLimiter: newTestRateLimiter(t, `requests: {rate: 1, burst: 2}`)})
+
+		// A new address in every request still counts against the bucket of the peer
+		assert.Equal(t, http.StatusOK, send(r, "203.0.113.1").Code)
+		assert.Equal(t, http.StatusOK, send(r, "203.0.113.2").Code)
+		assert.Equal(t, http.StatusTooManyRequests, send(r, "203.0.113.3").Code)
+	})
+
+	t.Run("set by a trusted proxy", func(t *testing.T) {
+		r := newRouter(&Server{rateLimiter: newTestRateLimiter(t, `requests: {rate: 1, burst: 2}`), trustedProxies: []string{"10.0.0.0/8"}})
+
+		assert.Equal(t, http.StatusOK, send(r, "203.0.113.1").Code)
+		assert.Equal(t, http.StatusOK, send(r, "203.0.113.1").Code)
+		assert.Equal(t, http.StatusTooManyRequests, send(r, "203.0.113.1").Code)
+		assert.Equal(t, http.StatusOK, send(r, "203.0.113.2").Code)
+	})
+}
+
+func TestComputeQuotaMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	s := &Server{rateLimiter: newTestRateLimiter(t, `compute: {rate: 100, burst: 1000}`)}
+	var body string
+	r := gin.New()
+	r.POST("/api/v1/orders/calculate", s.computeQuota(), func(ctx *gin.Context) {
+		// The handler still reads the body
+		content, _ := io.ReadAll(ctx.Request.Body)
+		body = string(content)
+		ctx.Status(http.StatusOK)
+	})
+	send := func(payload string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/calculate", strings.NewReader(payload))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(`{"product_id": 1, "order_quantity": 800}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"product_id": 1, "order_quantity": 800}`, body)
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"), "the headers describe the request limit unless the quota is exceeded")
+
+	w = send(`{"product_id": 1, "order_quantity": 500}`)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1000", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "200", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "3", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), `"code":"quota_exceeded"`)
+
+	// Malformed bodies cost a single token and are left to the handler
+	w = send(`{"order_quantity": "many"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"order_quantity": "many"}`, body)
+}
+
+func TestComputeQuotaRecalculate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	orderService := mocks.NewMockOrderService(ctrl)
+
+	s := &Server{rateLimiter: newTestRateLimiter(t, `compute: {rate: 100, burst: 1000}`), orderService: orderService}
+	r := gin.New()
+	r.POST("/api/v1/orders/:id/recalculate", s.computeQuota(), func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	send := func(id, payload string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/"+id+"/recalculate", strings.NewReader(payload))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Keeping the quantity of the order costs as much as calculating it
+	orderService.EXPECT().GetByID(gomock.Any(), int64(5)).Return(&dto.OrderResponse{ID: 5, OrderQuantity: 800}, nil).Times(2)
+	assert.Equal(t, http.StatusOK, send("5", `{}`).Code)
+	w := send("5", `{}`)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "200", w.Header().Get("RateLimit-Remaining"))
+
+	// A new quantity is charged without reading the order
+	assert.Equal(t, http.StatusOK, send("5", `{"order_quantity": 100}`).Code)
+
+	// Orders that cannot be read cost a single token and are left to the handler
+	orderService.EXPECT().GetByID(gomock.Any(), int64(6)).Return(nil, errs.ErrNotFound)
+	assert.Equal(t, http.StatusOK, send("6", `{}`).Code)
+	assert.Equal(t, http.StatusOK, send("invalid", `{}`).Code)
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"net/http"
+	"order-pack-calculator/internal/domain/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RecalculateOrderHandler godoc
+// @Summary      Recalculate order
+// @Description  Recalculates a draft order against the pack sizes in effect
+// @Tags         orders
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int                          true  "Order ID"
+// @Param        order  body      dto.RecalculateOrderRequest  true  "Recalculation details"
+// @Success      200    {object}  dto.OrderResponse
+// @Failure      400    {object}  dto.ErrorResponse
+// @Failure      401    {object}  dto.ErrorResponse
+// @Failure      403    {object}  dto.ErrorResponse
+// @Failure      404    {object}  dto.ErrorResponse
+// @Failure      409    {object}  dto.ErrorResponse
+// @Failure      413    {object}  dto.ErrorResponse
+// @Failure      422    {object}  dto.ErrorResponse
+// @Failure      429    {object}  dto.ErrorResponse
+// @Failure      500    {object}  dto.ErrorResponse
+// @Security     APIKey
+// @Security     BearerToken
+// @Router       /api/v1/orders/{id}/recalculate [post]
+func (s *Server) RecalculateOrderHandler(ctx *gin.Context) {
+	var uri dto.GetOrderRequest
+	err := ctx.ShouldBindUri(&uri)
+	if err != nil {
+		ErrResponse(ctx, "unable to parse request", invalidRequest(err))
+		return
+	}
+
+	var request dto.RecalculateOrderRequest
+	err = ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ErrResponse(ctx, "unable to parse request", invalidRequest(err))
+		return
+	}
+
+	response, err := s.orderService.Recalculate(ctx, uri.ID, request)
+
+	if err != nil {
+		ErrResponse(ctx, "unable to recalculate order", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"order-pack-calculator/internal/domain/dto"
+	errs "order-pack-calculator/internal/domain/errors"
+	"order-pack-calculator/mocks"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecalculateOrderHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockService := mocks.NewMockOrderService(ctrl)
+		s := &Server{orderService: mockService}
+
+		quantity := 100
+		mockService.EXPECT().Recalculate(gomock.Any(), int64(1), dto.RecalculateOrderRequest{OrderQuantity: &quantity}).
+			Return(&dto.OrderResponse{ID: 1, OrderQuantity: 100}, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/1/recalculate", bytes.NewBufferString(`{"order_quantity":100}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r, _ := gin.CreateTestContext(w)
+		r.Request = req
+		r.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		s.RecalculateOrderHandler(r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("bad request - invalid json", func(t *testing.T) {
+		s := &Server{}
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/1/recalculate", bytes.NewBufferString(`invalid`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r, _ := gin.CreateTestContext(w)
+		r.Request = req
+		r.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		s.RecalculateOrderHandler(r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("conflict - order is not a draft", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockService := mocks.NewMockOrderService(ctrl)
+		s := &Server{orderService: mockService}
+
+		mockService.EXPECT().Recalculate(gomock.Any(), int64(1), gomock.Any()).Return(nil, errs.ErrOrderNotDraft)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/1/recalculate", bytes.NewBufferString(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r, _ := gin.CreateTestContext(w)
+		r.Request = req
+		r.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		s.RecalculateOrderHandler(r)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
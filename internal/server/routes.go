@@ -1,31 +1,73 @@
 package server
 
 import (
+	"log/slog"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	_ "order-pack-calculator/docs"
+	"order-pack-calculator/internal/auth"
+	"order-pack-calculator/internal/metrics"
 )
 
 func (s *Server) RegisterRoutes() http.Handler {
-	r := gin.Default()
+	gin.DebugPrintRouteFunc = func(method, path, handler string, _ int) {
+		slog.Debug("route registered", "method", method, "path", path, "handler", handler)
+	}
+	r := s.newEngine()
+	// Spans are started first so the log lines of a request carry its trace ID
+	r.Use(traceRequests(), requestID(), accessLog(), instrument(), recovery())
+	r.NoRoute(noRouteHandler)
+	r.Use(s.limitRequestBody())
 
 	// Swagger route
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Prometheus scrapes the metrics without credentials, like the health probes
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+
 	api := r.Group("/api")
-	api.GET("/health", s.healthHandler)
+	api.GET("/health", s.readyHandler)
+	api.GET("/health/live", s.liveHandler)
+	api.GET("/health/ready", s.readyHandler)
 	v1 := api.Group("/v1")
+	if s.authenticator != nil {
+		v1.Use(s.authenticate())
+	}
+	// Clients are limited once identified, by principal or else by address
+	v1.Use(s.rateLimit())
 
+	// Every route declares the permission it requires
 	packsizes := v1.Group("/packsizes")
-	packsizes.GET("/", s.GetAllPackSizeHandler)
-	packsizes.POST("/", s.CreatePackSizeHandler)
-	packsizes.PATCH("/", s.UpdatePackSizeHandler)
+	packsizes.GET("/", s.authorize(auth.PermissionReadPackSizes), s.GetAllPackSizeHandler)
+	packsizes.GET("/:id", s.authorize(auth.PermissionReadPackSizes), s.GetPackSizeHandler)
+	packsizes.POST("/", s.authorize(auth.PermissionWritePackSizes), s.idempotent(), s.CreatePackSizeHandler)
+	packsizes.PATCH("/", s.authorize(auth.PermissionWritePackSizes), s.idempotent(), s.UpdatePackSizeHandler)
 
 	orders := v1.Group("/orders")
-	orders.POST("/calculate", s.CalculatePackSizeHandler)
+	orders.POST("/calculate", s.authorize(auth.PermissionCalculate), s.computeQuota(), s.idempotent(), s.CalculatePackSizeHandler)
+	orders.GET("/", s.authorize(auth.PermissionReadOrders), s.ListOrdersHandler)
+	orders.GET("/:id", s.authorize(auth.PermissionReadOrders), s.GetOrderHandler)
+	orders.POST("/:id/confirm", s.authorize(auth.PermissionWriteOrders), s.ConfirmOrderHandler)
+	orders.POST("/:id/pack", s.authorize(auth.PermissionWriteOrders), s.PackOrderHandler)
+	orders.POST("/:id/ship", s.authorize(auth.PermissionWriteOrders), s.ShipOrderHandler)
+	orders.POST("/:id/cancel", s.authorize(auth.PermissionWriteOrders), s.CancelOrderHandler)
+	orders.POST("/:id/recalculate", s.authorize(auth.PermissionWriteOrders), s.computeQuota(), s.RecalculateOrderHandler)
+
+	return r
+}
 
+// newEngine returns a router taking the client address of requests from the headers of the trusted proxies only.
+// Without trusted proxies it is the address of the peer, so clients cannot pick the address they are limited by.
+func (s *Server) newEngine() *gin.Engine {
+	r := gin.New()
+	// Handlers pass the gin context on, it must expose the values of the request context such as the principal
+	r.ContextWithFallback = true
+	if err := r.SetTrustedProxies(s.trustedProxies); err != nil {
+		slog.Error("ignoring invalid TRUSTED_PROXIES", "proxies", s.trustedProxies, "error", err)
+		_ = r.SetTrustedProxies(nil)
+	}
 	return r
 }
@@ -3,34 +3,51 @@ package server
 import (
 	"fmt"
 	"net/http"
-	"os"
-	"strconv"
 	"time"
 
-	_ "github.com/joho/godotenv/autoload"
-
-	"order-pack-calculator/internal/database"
-	"order-pack-calculator/internal/domain/repositories"
+	"order-pack-calculator/internal/app"
+	"order-pack-calculator/internal/auth"
 	"order-pack-calculator/internal/domain/services"
+	"order-pack-calculator/internal/health"
+	"order-pack-calculator/internal/ratelimit"
 )
 
 type Server struct {
 	port int
+	// maxBodyBytes is the largest request body accepted, 0 for no limit
+	maxBodyBytes int64
+	// trustedProxies are the addresses or CIDRs whose X-Forwarded-For and X-Real-IP headers are trusted
+	trustedProxies []string
 
-	dbService       database.Service
+	// health checks the dependencies the readiness of the service depends on
+	health          *health.Registry
 	packSizeService services.PackSizeService
+	orderService    services.OrderService
+
+	idempotencyService services.IdempotencyService
+	// authenticator checks the credentials of API requests, nil when authentication is disabled
+	authenticator *auth.Authenticator
+	// rateLimiter limits the requests and compute of each client, nil when rate limiting is disabled
+	rateLimiter *ratelimit.Limiter
 }
 
-func NewServer() *http.Server {
-	port, _ := strconv.Atoi(os.Getenv("PORT"))
-	dbService := database.New()
-	packSizeRepository := repositories.NewPackSizeRepository(dbService.GetDB())
-	packSizeService := services.NewPackSizeService(packSizeRepository)
+func NewServer(app *app.App) *http.Server {
 	NewServer := &Server{
-		port:      port,
-		dbService: dbService,
+		port:           app.Config.Port,
+		maxBodyBytes:   app.Config.MaxRequestBodyBytes,
+		trustedProxies: app.Config.TrustedProxies,
+		health:         app.Health,
 
-		packSizeService: packSizeService,
+		packSizeService: app.PackSizeService,
+		orderService:    app.OrderService,
+
+		idempotencyService: app.IdempotencyService,
+		authenticator:      app.Authenticator,
+		rateLimiter:        app.RateLimiter,
+	}
+	go NewServer.purgeExpiredIdempotencyKeys(time.Hour)
+	if NewServer.rateLimiter != nil {
+		go NewServer.rateLimiter.Policy().Watch(10 * time.Second)
 	}
 
 	// Declare Server config
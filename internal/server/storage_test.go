@@ -0,0 +1,56 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"order-pack-calculator/internal/app"
+	"order-pack-calculator/internal/domain/dto"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStorage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	app, err := app.New(app.Config{Storage: app.StorageConfig{Kind: "memory", SeedPath: "../../seeds/pack_sizes.yaml"}, IdempotencyTTL: time.Hour})
+	assert.NoError(t, err)
+	s := &Server{
+		health:             app.Health,
+		packSizeService:    app.PackSizeService,
+		orderService:       app.OrderService,
+		idempotencyService: app.IdempotencyService,
+	}
+	handler := s.RegisterRoutes()
+
+	t.Run("calculate and fetch the order", func(t *testing.T) {
+		body, _ := json.Marshal(dto.CalculatePackSizesRequest{ProductID: 1, OrderQuantity: 500})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/calculate", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Idempotency-Key", "memory-storage")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var calculated dto.OptimalPackSizesResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &calculated))
+		assert.Equal(t, 500, calculated.TotalItems)
+
+		req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/orders/%d", calculated.OrderID), nil)
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("health", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/health", nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
@@ -0,0 +1,18 @@
+package server
+
+import (
+	"order-pack-calculator/internal/tracing"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// traceRequests serves every request in a server span named after its route, continuing the trace of the
+// caller when the request carries a W3C traceparent header. Health checks, metrics and the documentation are not traced.
+func traceRequests() gin.HandlerFunc {
+	return otelgin.Middleware(tracing.ServiceName, otelgin.WithGinFilter(func(ctx *gin.Context) bool {
+		route := ctx.FullPath()
+		return !isHealthRoute(route) && route != "/metrics" && !strings.HasPrefix(route, "/swagger/")
+	}))
+}
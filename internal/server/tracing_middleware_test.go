@@ -0,0 +1,56 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestTracingMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	})
+	out := captureLogs(t)
+
+	r := gin.New()
+	r.ContextWithFallback = true
+	r.Use(traceRequests(), requestID(), accessLog())
+	r.GET("/api/health", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	r.GET("/api/v1/orders/:id", func(ctx *gin.Context) { ctx.Status(http.StatusNotFound) })
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders/5", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/health", nil))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1, "health checks are not traced")
+	span := spans[0]
+	assert.Equal(t, "/api/v1/orders/:id", span.Name())
+	assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String(), "the trace of the caller is continued")
+	assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+	assert.Contains(t, span.Attributes(), attribute.Int("http.status_code", http.StatusNotFound))
+
+	lines := logLines(out, "request served")
+	assert.Len(t, lines, 2)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", lines[0]["trace_id"])
+	assert.Equal(t, span.SpanContext().SpanID().String(), lines[0]["span_id"])
+	assert.NotContains(t, lines[1], "trace_id")
+}
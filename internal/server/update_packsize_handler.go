@@ -9,28 +9,48 @@ import (
 
 // UpdatePackSizeHandler godoc
 // @Summary      Update pack sizes
-// @Description  Updates existing pack sizes
+// @Description  Updates existing pack sizes. Send the ETag of the pack size in If-Match to reject the update if it was modified meanwhile. If-Match is only supported by this PATCH route, with strong comparison: weak tags never match.
 // @Tags         packsizes
 // @Accept       json
 // @Produce      json
-// @Param        packSize  body      dto.UpdatePackSizeRequest  true  "Updated pack size details"
-// @Success      200       "OK"
+// @Param        packSize         body      dto.UpdatePackSizeRequest      true   "Updated pack size details"
+// @Param        If-Match         header    string                         false  "ETags of the versions being updated, or *"
+// @Param        Idempotency-Key  header    string                         false  "Key identifying retries of the same request"
+// @Success      200       {object}  dto.PackSizeResponse
+// @Header       200       {string}  ETag  "Version of the updated pack size"
 // @Failure      400       {object}  dto.ErrorResponse
+// @Failure      401       {object}  dto.ErrorResponse
+// @Failure      403       {object}  dto.ErrorResponse
+// @Failure      404       {object}  dto.ErrorResponse
+// @Failure      409       {object}  dto.ErrorResponse
+// @Failure      412       {object}  dto.ErrorResponse
+// @Failure      413    {object}  dto.ErrorResponse
+// @Failure      422       {object}  dto.ErrorResponse
+// @Failure      429    {object}  dto.ErrorResponse
 // @Failure      500       {object}  dto.ErrorResponse
+// @Security     APIKey
+// @Security     BearerToken
 // @Router       /api/v1/packsizes [patch]
 func (s *Server) UpdatePackSizeHandler(ctx *gin.Context) {
 	var request dto.UpdatePackSizeRequest
-	err := ctx.BindJSON(&request)
+	err := ctx.ShouldBindJSON(&request)
 	if err != nil {
-		ErrResponse(ctx, "unable to parse request", err)
+		ErrResponse(ctx, "unable to parse request", invalidRequest(err))
 		return
 	}
 
-	err = s.packSizeService.Update(ctx, request)
+	request.Versions, err = ifMatchVersions(ctx)
+	if err != nil {
+		ErrResponse(ctx, "unable to update pack sizes", err)
+		return
+	}
+
+	response, err := s.packSizeService.Update(ctx, request)
 
 	if err != nil {
 		ErrResponse(ctx, "unable to update pack sizes", err)
 		return
 	}
-	ctx.Status(http.StatusOK)
+	ctx.Header("ETag", versionETag(response.Version))
+	ctx.JSON(http.StatusOK, response)
 }
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"order-pack-calculator/internal/domain/dto"
+	errs "order-pack-calculator/internal/domain/errors"
 	"order-pack-calculator/mocks"
 	"testing"
 
@@ -28,7 +29,7 @@ func TestUpdatePackSizeHandler(t *testing.T) {
 		size := 15
 		active := true
 		reqBody := dto.UpdatePackSizeRequest{ID: 1, Size: &size, Active: &active}
-		mockService.EXPECT().Update(gomock.Any(), reqBody).Return(nil)
+		mockService.EXPECT().Update(gomock.Any(), reqBody).Return(&dto.PackSizeResponse{ID: 1, Size: size, Active: active, Version: 2}, nil)
 
 		bodyBytes, _ := json.Marshal(reqBody)
 		req := httptest.NewRequest(http.MethodPatch, "/api/v1/packsizes", bytes.NewReader(bodyBytes))
@@ -39,6 +40,131 @@ func TestUpdatePackSizeHandler(t *testing.T) {
 
 		s.UpdatePackSizeHandler(r)
 		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("success - if match", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockService := mocks.NewMockPackSizeService(ctrl)
+		s := &Server{packSizeService: mockService}
+
+		size := 15
+		reqBody := dto.UpdatePackSizeRequest{ID: 1, Size: &size}
+		expected := reqBody
+		expected.Versions = []int64{1, 3}
+		mockService.EXPECT().Update(gomock.Any(), expected).Return(&dto.PackSizeResponse{ID: 1, Size: size, Version: 2}, nil)
+
+		bodyBytes, _ := json.Marshal(reqBody)
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/packsizes", bytes.NewReader(bodyBytes))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1", W/"2", "3"`)
+		w := httptest.NewRecorder()
+		r, _ := gin.CreateTestContext(w)
+		r.Request = req
+
+		s.UpdatePackSizeHandler(r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("precondition failed - unknown entity tag", func(t *testing.T) {
+		s := &Server{}
+
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/packsizes", bytes.NewBuffer([]byte(`{"id":1,"size":15}`)))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"abc"`)
+		w := httptest.NewRecorder()
+		r, _ := gin.CreateTestContext(w)
+		r.Request = req
+
+		s.UpdatePackSizeHandler(r)
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	})
+
+	t.Run("success - clear validity", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockService := mocks.NewMockPackSizeService(ctrl)
+		s := &Server{packSizeService: mockService}
+
+		reqBody := dto.UpdatePackSizeRequest{ID: 1, ValidTo: dto.SetTime(nil)}
+		mockService.EXPECT().Update(gomock.Any(), reqBody).Return(&dto.PackSizeResponse{ID: 1, Version: 2}, nil)
+
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/packsizes", bytes.NewBuffer([]byte(`{"id":1,"valid_to":null}`)))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r, _ := gin.CreateTestContext(w)
+		r.Request = req
+
+		s.UpdatePackSizeHandler(r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("success - any version", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockService := mocks.NewMockPackSizeService(ctrl)
+		s := &Server{packSizeService: mockService}
+
+		size := 15
+		reqBody := dto.UpdatePackSizeRequest{ID: 1, Size: &size}
+		mockService.EXPECT().Update(gomock.Any(), reqBody).Return(&dto.PackSizeResponse{ID: 1, Size: size, Version: 2}, nil)
+
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/packsizes", bytes.NewBuffer([]byte(`{"id":1,"size":15}`)))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", "*")
+		w := httptest.NewRecorder()
+		r, _ := gin.CreateTestContext(w)
+		r.Request = req
+
+		s.UpdatePackSizeHandler(r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	for name, ifMatch := range map[string]string{
+		"weak entity tag":         `W/"1"`,
+		"only weak entity tags":   `W/"1", W/"2"`,
+		"unquoted entity tag":     `1`,
+		"unterminated entity tag": `"1`,
+		"missing comma":           `"1" "2"`,
+	} {
+		t.Run("precondition failed - "+name, func(t *testing.T) {
+			s := &Server{}
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/v1/packsizes", bytes.NewBuffer([]byte(`{"id":1,"size":15}`)))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("If-Match", ifMatch)
+			w := httptest.NewRecorder()
+			r, _ := gin.CreateTestContext(w)
+			r.Request = req
+
+			s.UpdatePackSizeHandler(r)
+			assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+		})
+	}
+
+	t.Run("precondition failed - stale version", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockService := mocks.NewMockPackSizeService(ctrl)
+		s := &Server{packSizeService: mockService}
+
+		mockService.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil, errs.ErrPreconditionFailed)
+
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/packsizes", bytes.NewBuffer([]byte(`{"id":1,"size":15}`)))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1"`)
+		w := httptest.NewRecorder()
+		r, _ := gin.CreateTestContext(w)
+		r.Request = req
+
+		s.UpdatePackSizeHandler(r)
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
 	})
 
 	t.Run("bad request - invalid json", func(t *testing.T) {
@@ -63,7 +189,7 @@ func TestUpdatePackSizeHandler(t *testing.T) {
 
 		size := 15
 		reqBody := dto.UpdatePackSizeRequest{ID: 1, Size: &size}
-		mockService.EXPECT().Update(gomock.Any(), reqBody).Return(errors.New("update failed"))
+		mockService.EXPECT().Update(gomock.Any(), reqBody).Return(nil, errors.New("update failed"))
 
 		bodyBytes, _ := json.Marshal(reqBody)
 		req := httptest.NewRequest(http.MethodPatch, "/api/v1/packsizes", bytes.NewReader(bodyBytes))
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	errs "order-pack-calculator/internal/domain/errors"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	// Validation errors name fields as clients send them rather than after the Go struct fields
+	if validate, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		validate.RegisterTagNameFunc(requestFieldName)
+	}
+}
+
+// requestFieldName returns the name of a request field in the JSON body, the query or the path
+func requestFieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form", "uri"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// bindingFieldErrors lists the fields whose value broke a binding rule or could not be decoded
+func bindingFieldErrors(err error) []errs.FieldError {
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		fields := make([]errs.FieldError, 0, len(validationErrors))
+		for _, fe := range validationErrors {
+			fields = append(fields, errs.FieldError{Field: fe.Field(), Rule: fe.Tag(), Message: ruleMessage(fe)})
+		}
+		return fields
+	}
+
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) && typeError.Field != "" {
+		return []errs.FieldError{{Field: typeError.Field, Rule: "type", Message: "must be " + typeName(typeError.Type)}}
+	}
+	return nil
+}
+
+// ruleMessage explains the binding rule a value broke
+func ruleMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return "must be at least " + fe.Param()
+	case "max":
+		return "must be at most " + fe.Param()
+	case "oneof":
+		return "must be one of " + strings.Join(strings.Fields(fe.Param()), ", ")
+	case "gtefield":
+		return "must be greater than or equal to " + snakeCase(fe.Param())
+	default:
+		return fmt.Sprintf("does not satisfy %s", fe.Tag())
+	}
+}
+
+// typeName names the type expected by a request field
+func typeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
+
+// snakeCase converts the name of a Go struct field referred to by a rule, e.g. MinSize, to the name of the request field
+func snakeCase(name string) string {
+	var (
+		b         strings.Builder
+		lowerPrev bool
+	)
+	for _, r := range name {
+		if unicode.IsUpper(r) && lowerPrev {
+			b.WriteByte('_')
+		}
+		lowerPrev = unicode.IsLower(r)
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"order-pack-calculator/internal/domain/dto"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBindingFieldErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(s *Server) gin.HandlerFunc
+		method  string
+		target  string
+		body    string
+		want    []dto.FieldError
+	}{
+		{
+			name:    "missing fields",
+			handler: func(s *Server) gin.HandlerFunc { return s.CalculatePackSizeHandler },
+			method:  http.MethodPost,
+			target:  "/api/v1/orders/calculate",
+			body:    `{"order_quantity":0}`,
+			want: []dto.FieldError{
+				{Field: "product_id", Rule: "required", Message: "is required"},
+				{Field: "order_quantity", Rule: "required", Message: "is required"},
+			},
+		},
+		{
+			name:    "value below the minimum",
+			handler: func(s *Server) gin.HandlerFunc { return s.CreatePackSizeHandler },
+			method:  http.MethodPost,
+			target:  "/api/v1/packsizes",
+			body:    `{"product_id":1,"size":-5}`,
+			want:    []dto.FieldError{{Field: "size", Rule: "min", Message: "must be at least 1"}},
+		},
+		{
+			name:    "value of the wrong type",
+			handler: func(s *Server) gin.HandlerFunc { return s.CalculatePackSizeHandler },
+			method:  http.MethodPost,
+			target:  "/api/v1/orders/calculate",
+			body:    `{"product_id":"one","order_quantity":10}`,
+			want:    []dto.FieldError{{Field: "product_id", Rule: "type", Message: "must be an integer"}},
+		},
+		{
+			name:    "query parameters",
+			handler: func(s *Server) gin.HandlerFunc { return s.GetAllPackSizeHandler },
+			method:  http.MethodGet,
+			target:  "/api/v1/packsizes?sort=weight&min_size=10&max_size=5",
+			want: []dto.FieldError{
+				{Field: "max_size", Rule: "gtefield", Message: "must be greater than or equal to min_size"},
+				{Field: "sort", Rule: "oneof", Message: "must be one of id, product_id, size"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request = req
+
+			tt.handler(&Server{})(ctx)
+
+			// Field errors are reported like the limits of the service are
+			assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+			var response dto.ErrorResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, "validation_failed", response.Code)
+			assert.Equal(t, tt.want, response.Errors)
+		})
+	}
+}
+
+func TestMalformedRequest(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/calculate", bytes.NewBufferString(`{"product_id":1,`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+
+	(&Server{}).CalculatePackSizeHandler(ctx)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response dto.ErrorResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "invalid_request", response.Code)
+	assert.Empty(t, response.Errors)
+}
+
+func TestSnakeCase(t *testing.T) {
+	assert.Equal(t, "min_size", snakeCase("MinSize"))
+	assert.Equal(t, "product_id", snakeCase("ProductID"))
+	assert.Equal(t, "size", snakeCase("Size"))
+}
@@ -0,0 +1,186 @@
+// Package tracing sets up OpenTelemetry tracing: the exporter spans are sent to and the W3C trace
+// context propagation that lets a trace follow a request across services.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName names the service in the spans it exports, unless OTEL_SERVICE_NAME is set
+const ServiceName = "order-pack-calculator"
+
+// Exporters spans can be sent to
+const (
+	ExporterNone    = "none"
+	ExporterOTLP    = "otlp"
+	ExporterConsole = "console"
+	ExporterFile    = "file"
+)
+
+// OTLP protocols
+const (
+	ProtocolHTTP = "http/protobuf"
+	ProtocolGRPC = "grpc"
+)
+
+// Config is the configuration of tracing
+type Config struct {
+	// Exporter is ExporterNone, ExporterOTLP, ExporterConsole or ExporterFile
+	Exporter string
+	// Protocol is the OTLP protocol, ProtocolHTTP or ProtocolGRPC
+	Protocol string
+	// File is the file ExporterFile appends spans to
+	File string
+}
+
+// ConfigFromEnv reads the configuration from OTEL_TRACES_EXPORTER (none by default, otlp, console or file),
+// OTEL_EXPORTER_OTLP_TRACES_PROTOCOL or OTEL_EXPORTER_OTLP_PROTOCOL (http/protobuf by default, or grpc)
+// and TRACES_FILE. The OTLP exporters read their endpoint, headers and timeout from the OTEL_EXPORTER_OTLP_*
+// variables themselves, and the sampler is read from OTEL_TRACES_SAMPLER.
+func ConfigFromEnv() (Config, error) {
+	config := Config{Exporter: ExporterNone, Protocol: ProtocolHTTP, File: os.Getenv("TRACES_FILE")}
+	switch exporter := strings.ToLower(os.Getenv("OTEL_TRACES_EXPORTER")); exporter {
+	case "", ExporterNone:
+	case ExporterOTLP, ExporterConsole, ExporterFile:
+		config.Exporter = exporter
+	default:
+		return Config{Exporter: ExporterNone}, fmt.Errorf("invalid OTEL_TRACES_EXPORTER %q, expected none, otlp, console or file", exporter)
+	}
+
+	protocol := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL")
+	if protocol == "" {
+		protocol = os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+	}
+	switch protocol {
+	case "", ProtocolHTTP:
+	case ProtocolGRPC:
+		config.Protocol = ProtocolGRPC
+	default:
+		return Config{Exporter: ExporterNone}, fmt.Errorf("invalid OTLP protocol %q, expected http/protobuf or grpc", protocol)
+	}
+
+	if config.Exporter == ExporterFile && config.File == "" {
+		return Config{Exporter: ExporterNone}, errors.New("TRACES_FILE is required by the file exporter")
+	}
+	return config, nil
+}
+
+// New returns a tracer provider batching spans to the configured exporter, nil for ExporterNone.
+// It must be shut down to flush the spans left in the batch.
+func New(ctx context.Context, config Config) (*sdktrace.TracerProvider, error) {
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+	switch config.Exporter {
+	case "", ExporterNone:
+		return nil, nil
+	case ExporterOTLP:
+		if config.Protocol == ProtocolGRPC {
+			exporter, err = otlptracegrpc.New(ctx)
+		} else {
+			exporter, err = otlptracehttp.New(ctx)
+		}
+	case ExporterConsole:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterFile:
+		exporter, err = newFileExporter(config.File)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", config.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s trace exporter: %w", config.Exporter, err)
+	}
+
+	// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES override the attributes set here
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(ServiceName)),
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe the service: %w", err)
+	}
+	return sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res)), nil
+}
+
+// Setup propagates the W3C trace context and baggage of incoming and outgoing requests and sends
+// spans to the exporter configured in the environment. An invalid configuration is logged and
+// leaves spans unexported. The function returned flushes the spans left and must be called before exiting.
+func Setup() func() {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	config, err := ConfigFromEnv()
+	if err != nil {
+		slog.Warn("ignoring invalid tracing configuration", "error", err)
+	}
+	provider, err := New(context.Background(), config)
+	if err != nil {
+		slog.Warn("traces will not be exported", "error", err)
+	}
+	if provider == nil {
+		return func() {}
+	}
+	otel.SetTracerProvider(provider)
+	slog.Debug("exporting traces", "exporter", config.Exporter)
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			slog.Warn("failed to flush traces", "error", err)
+		}
+	}
+}
+
+// RecordError marks the span as failed by err, when there is one
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// newFileExporter returns an exporter appending spans to the file as JSON
+func newFileExporter(path string) (sdktrace.SpanExporter, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return closingExporter{exporter, file}, nil
+}
+
+// closingExporter closes the file spans are written to when it is shut down
+type closingExporter struct {
+	sdktrace.SpanExporter
+	file io.Closer
+}
+
+func (e closingExporter) Shutdown(ctx context.Context) error {
+	return errors.Join(e.SpanExporter.Shutdown(ctx), e.file.Close())
+}
@@ -0,0 +1,99 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("OTEL_TRACES_EXPORTER", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", "")
+	t.Setenv("TRACES_FILE", "")
+	config, err := ConfigFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, Config{Exporter: ExporterNone, Protocol: ProtocolHTTP}, config)
+
+	t.Setenv("OTEL_TRACES_EXPORTER", "OTLP")
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "grpc")
+	config, err = ConfigFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, Config{Exporter: ExporterOTLP, Protocol: ProtocolGRPC}, config)
+
+	// The protocol of traces prevails over the one of every signal
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", "http/protobuf")
+	config, err = ConfigFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, ProtocolHTTP, config.Protocol)
+
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", "http/json")
+	config, err = ConfigFromEnv()
+	assert.Error(t, err)
+	assert.Equal(t, ExporterNone, config.Exporter)
+
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", "")
+	t.Setenv("OTEL_TRACES_EXPORTER", "file")
+	_, err = ConfigFromEnv()
+	assert.ErrorContains(t, err, "TRACES_FILE")
+
+	t.Setenv("TRACES_FILE", "traces.json")
+	config, err = ConfigFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, Config{Exporter: ExporterFile, Protocol: ProtocolGRPC, File: "traces.json"}, config)
+
+	t.Setenv("OTEL_TRACES_EXPORTER", "jaeger")
+	config, err = ConfigFromEnv()
+	assert.Error(t, err)
+	assert.Equal(t, ExporterNone, config.Exporter)
+}
+
+func TestNew(t *testing.T) {
+	provider, err := New(context.Background(), Config{Exporter: ExporterNone})
+	assert.NoError(t, err)
+	assert.Nil(t, provider)
+
+	t.Setenv("OTEL_SERVICE_NAME", "")
+	path := filepath.Join(t.TempDir(), "traces.json")
+	provider, err = New(context.Background(), Config{Exporter: ExporterFile, File: path})
+	assert.NoError(t, err)
+	_, span := provider.Tracer("test").Start(context.Background(), "calculate")
+	span.End()
+	// Shutting down flushes the batch and closes the file
+	assert.NoError(t, provider.Shutdown(context.Background()))
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), `"Name":"calculate"`)
+	assert.Contains(t, string(content), `"Value":"order-pack-calculator"`)
+
+	_, err = New(context.Background(), Config{Exporter: ExporterFile, File: filepath.Join(path, "missing", "traces.json")})
+	assert.Error(t, err)
+}
+
+func TestRecordError(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	_, span := tracer.Start(context.Background(), "ok")
+	RecordError(span, nil)
+	span.End()
+	_, span = tracer.Start(context.Background(), "failed")
+	RecordError(span, errors.New("db error"))
+	span.End()
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Empty(t, spans[0].Events())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "db error", spans[1].Status().Description)
+	assert.Equal(t, "exception", spans[1].Events()[0].Name)
+}
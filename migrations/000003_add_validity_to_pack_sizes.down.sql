@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS pack_sizes_product_validity_idx;
+
+ALTER TABLE pack_sizes
+	DROP COLUMN IF EXISTS valid_to,
+	DROP COLUMN IF EXISTS valid_from;
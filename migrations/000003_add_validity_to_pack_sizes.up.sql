@@ -0,0 +1,5 @@
+ALTER TABLE pack_sizes
+	ADD COLUMN IF NOT EXISTS valid_from timestamptz NULL,
+	ADD COLUMN IF NOT EXISTS valid_to timestamptz NULL;
+
+CREATE INDEX IF NOT EXISTS pack_sizes_product_validity_idx ON pack_sizes (product_id, valid_from, valid_to);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS order_packs;
+DROP TABLE IF EXISTS orders;
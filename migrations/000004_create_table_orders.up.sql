@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS orders (
+	id bigserial NOT NULL,
+	product_id bigint NOT NULL,
+	order_quantity bigint NOT NULL,
+	total_items bigint NOT NULL,
+	total_packs bigint NOT NULL,
+	objective varchar(64) NOT NULL,
+	pack_sizes jsonb NOT NULL,
+	as_of timestamptz NOT NULL,
+	created_at timestamptz DEFAULT now() NOT NULL,
+	CONSTRAINT orders_pkey PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS orders_product_created_idx ON orders (product_id, created_at);
+
+CREATE TABLE IF NOT EXISTS order_packs (
+	order_id bigint NOT NULL,
+	"size" bigint NOT NULL,
+	count bigint NOT NULL,
+	CONSTRAINT order_packs_pkey PRIMARY KEY (order_id, "size"),
+	CONSTRAINT order_packs_order_id_fkey FOREIGN KEY (order_id) REFERENCES orders (id) ON DELETE CASCADE
+);
@@ -0,0 +1,9 @@
+ALTER TABLE orders
+	DROP CONSTRAINT IF EXISTS orders_status_check;
+
+ALTER TABLE orders
+	DROP COLUMN IF EXISTS cancelled_at,
+	DROP COLUMN IF EXISTS shipped_at,
+	DROP COLUMN IF EXISTS packed_at,
+	DROP COLUMN IF EXISTS confirmed_at,
+	DROP COLUMN IF EXISTS status;
@@ -0,0 +1,9 @@
+ALTER TABLE orders
+	ADD COLUMN IF NOT EXISTS status varchar(16) DEFAULT 'draft' NOT NULL,
+	ADD COLUMN IF NOT EXISTS confirmed_at timestamptz NULL,
+	ADD COLUMN IF NOT EXISTS packed_at timestamptz NULL,
+	ADD COLUMN IF NOT EXISTS shipped_at timestamptz NULL,
+	ADD COLUMN IF NOT EXISTS cancelled_at timestamptz NULL;
+
+ALTER TABLE orders
+	ADD CONSTRAINT orders_status_check CHECK (status IN ('draft', 'confirmed', 'packed', 'shipped', 'cancelled'));
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+	idempotency_key varchar(255) NOT NULL,
+	route varchar(255) NOT NULL,
+	request_hash char(64) NOT NULL,
+	status_code int NULL,
+	content_type varchar(255) NULL,
+	response_body bytea NULL,
+	created_at timestamptz DEFAULT now() NOT NULL,
+	expires_at timestamptz NOT NULL,
+	CONSTRAINT idempotency_keys_pkey PRIMARY KEY (idempotency_key, route)
+);
+
+CREATE INDEX IF NOT EXISTS idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
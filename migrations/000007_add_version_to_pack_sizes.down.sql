@@ -0,0 +1,2 @@
+ALTER TABLE pack_sizes
+	DROP COLUMN IF EXISTS version;
@@ -0,0 +1,2 @@
+ALTER TABLE pack_sizes
+	ADD COLUMN IF NOT EXISTS version bigint DEFAULT 1 NOT NULL;
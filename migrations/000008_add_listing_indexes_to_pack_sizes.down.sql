@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS pack_sizes_size_idx;
+DROP INDEX IF EXISTS pack_sizes_product_size_idx;
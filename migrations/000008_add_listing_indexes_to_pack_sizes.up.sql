@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS pack_sizes_product_size_idx ON pack_sizes (product_id, size, id);
+CREATE INDEX IF NOT EXISTS pack_sizes_size_idx ON pack_sizes (size, id);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+	id bigserial NOT NULL,
+	name varchar(255) NOT NULL,
+	prefix varchar(32) NOT NULL,
+	key_hash char(64) NOT NULL,
+	created_at timestamptz DEFAULT now() NOT NULL,
+	revoked_at timestamptz NULL,
+	CONSTRAINT api_keys_pkey PRIMARY KEY (id),
+	CONSTRAINT api_keys_key_hash_key UNIQUE (key_hash)
+);
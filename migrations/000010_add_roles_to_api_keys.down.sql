@@ -0,0 +1,3 @@
+ALTER TABLE api_keys
+	DROP COLUMN IF EXISTS roles,
+	DROP COLUMN IF EXISTS product_ids;
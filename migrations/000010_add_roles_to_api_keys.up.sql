@@ -0,0 +1,6 @@
+ALTER TABLE api_keys
+	ADD COLUMN IF NOT EXISTS roles jsonb DEFAULT '[]' NOT NULL,
+	ADD COLUMN IF NOT EXISTS product_ids jsonb DEFAULT '[]' NOT NULL;
+
+-- Keys created before roles existed could call every route
+UPDATE api_keys SET roles = '["admin"]';
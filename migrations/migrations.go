@@ -0,0 +1,24 @@
+// Package migrations embeds the SQL migrations of every storage backend, so the binary can apply them itself.
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed *.sql
+var postgres embed.FS
+
+//go:embed sqlite/*.sql
+var sqlite embed.FS
+
+// Postgres returns the migrations of the postgres backend
+func Postgres() fs.FS {
+	return postgres
+}
+
+// SQLite returns the migrations of the sqlite backend
+func SQLite() fs.FS {
+	migrations, _ := fs.Sub(sqlite, "sqlite")
+	return migrations
+}
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS pack_sizes;
\ No newline at end of file
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS pack_sizes (
+	id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+	product_id bigint NOT NULL,
+	"size" bigint NOT NULL,
+	active bool DEFAULT true NOT NULL
+);
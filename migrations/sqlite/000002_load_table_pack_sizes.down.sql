@@ -0,0 +1 @@
+DELETE FROM pack_sizes;
\ No newline at end of file
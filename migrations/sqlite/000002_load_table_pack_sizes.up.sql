@@ -0,0 +1,3 @@
+INSERT INTO pack_sizes (product_id, "size") VALUES (1, 23);
+INSERT INTO pack_sizes (product_id, "size") VALUES (1, 31);
+INSERT INTO pack_sizes (product_id, "size") VALUES (1, 53);
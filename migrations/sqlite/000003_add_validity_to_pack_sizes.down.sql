@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS pack_sizes_product_validity_idx;
+
+ALTER TABLE pack_sizes DROP COLUMN valid_to;
+ALTER TABLE pack_sizes DROP COLUMN valid_from;
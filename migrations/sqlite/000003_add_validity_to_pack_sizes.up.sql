@@ -0,0 +1,4 @@
+ALTER TABLE pack_sizes ADD COLUMN valid_from timestamp NULL;
+ALTER TABLE pack_sizes ADD COLUMN valid_to timestamp NULL;
+
+CREATE INDEX IF NOT EXISTS pack_sizes_product_validity_idx ON pack_sizes (product_id, valid_from, valid_to);
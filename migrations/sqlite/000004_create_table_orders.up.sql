@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS orders (
+	id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+	product_id bigint NOT NULL,
+	order_quantity bigint NOT NULL,
+	total_items bigint NOT NULL,
+	total_packs bigint NOT NULL,
+	objective varchar(64) NOT NULL,
+	pack_sizes text NOT NULL,
+	as_of timestamp NOT NULL,
+	created_at timestamp DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS orders_product_created_idx ON orders (product_id, created_at);
+
+CREATE TABLE IF NOT EXISTS order_packs (
+	order_id bigint NOT NULL,
+	"size" bigint NOT NULL,
+	count bigint NOT NULL,
+	CONSTRAINT order_packs_pkey PRIMARY KEY (order_id, "size"),
+	CONSTRAINT order_packs_order_id_fkey FOREIGN KEY (order_id) REFERENCES orders (id) ON DELETE CASCADE
+);
@@ -0,0 +1,5 @@
+ALTER TABLE orders DROP COLUMN cancelled_at;
+ALTER TABLE orders DROP COLUMN shipped_at;
+ALTER TABLE orders DROP COLUMN packed_at;
+ALTER TABLE orders DROP COLUMN confirmed_at;
+ALTER TABLE orders DROP COLUMN status;
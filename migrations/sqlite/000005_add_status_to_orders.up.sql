@@ -0,0 +1,6 @@
+ALTER TABLE orders ADD COLUMN status varchar(16) DEFAULT 'draft' NOT NULL
+	CONSTRAINT orders_status_check CHECK (status IN ('draft', 'confirmed', 'packed', 'shipped', 'cancelled'));
+ALTER TABLE orders ADD COLUMN confirmed_at timestamp NULL;
+ALTER TABLE orders ADD COLUMN packed_at timestamp NULL;
+ALTER TABLE orders ADD COLUMN shipped_at timestamp NULL;
+ALTER TABLE orders ADD COLUMN cancelled_at timestamp NULL;
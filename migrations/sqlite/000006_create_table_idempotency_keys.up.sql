@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+	idempotency_key varchar(255) NOT NULL,
+	route varchar(255) NOT NULL,
+	request_hash char(64) NOT NULL,
+	status_code int NULL,
+	content_type varchar(255) NULL,
+	response_body blob NULL,
+	created_at timestamp DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')) NOT NULL,
+	expires_at timestamp NOT NULL,
+	CONSTRAINT idempotency_keys_pkey PRIMARY KEY (idempotency_key, route)
+);
+
+CREATE INDEX IF NOT EXISTS idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
@@ -0,0 +1 @@
+ALTER TABLE pack_sizes DROP COLUMN version;
@@ -0,0 +1 @@
+ALTER TABLE pack_sizes ADD COLUMN version bigint DEFAULT 1 NOT NULL;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+	id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+	name varchar(255) NOT NULL,
+	prefix varchar(32) NOT NULL,
+	key_hash char(64) NOT NULL,
+	created_at timestamp DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')) NOT NULL,
+	revoked_at timestamp NULL,
+	CONSTRAINT api_keys_key_hash_key UNIQUE (key_hash)
+);
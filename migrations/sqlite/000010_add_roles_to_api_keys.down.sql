@@ -0,0 +1,2 @@
+ALTER TABLE api_keys DROP COLUMN roles;
+ALTER TABLE api_keys DROP COLUMN product_ids;
@@ -0,0 +1,5 @@
+ALTER TABLE api_keys ADD COLUMN roles text DEFAULT '[]' NOT NULL;
+ALTER TABLE api_keys ADD COLUMN product_ids text DEFAULT '[]' NOT NULL;
+
+-- Keys created before roles existed could call every route
+UPDATE api_keys SET roles = '["admin"]';
@@ -0,0 +1,246 @@
+// Package client is a typed Go client for the order pack calculator HTTP API.
+//
+// Every route of the API has a method taking and returning the API's own request and response
+// types. Error responses are returned as *APIError, which can be matched with errors.Is against
+// the sentinel errors of this package, e.g. errors.Is(err, client.ErrNotFound).
+//
+// Reads are retried on network errors and on 429, 502, 503 and 504 responses. Pack size writes
+// and calculations are sent with an Idempotency-Key, generated once per call, so they are retried
+// the same way without being applied twice. Order transitions are never retried.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"order-pack-calculator/internal/logging"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const (
+	idempotencyKeyHeader = "Idempotency-Key"
+	apiKeyHeader         = "X-API-Key"
+	requestIDHeader      = "X-Request-ID"
+	defaultRetryBackoff  = 100 * time.Millisecond
+)
+
+// Options configures a Client. The zero value is ready to use.
+type Options struct {
+	// HTTPClient sends the requests, http.DefaultClient when nil
+	HTTPClient *http.Client
+	// Timeout bounds each attempt of a request, retries get a new one. Zero means no timeout besides the context's.
+	Timeout time.Duration
+	// MaxRetries is how many times a failed request that is safe to retry is sent again. Zero disables retries.
+	MaxRetries int
+	// RetryBackoff is the wait before the first retry, doubled before each following one.
+	// Defaults to 100ms. A Retry-After header sent by the server takes precedence.
+	RetryBackoff time.Duration
+	// APIKey authenticates the requests with an API key, created with the apikeys command of the server
+	APIKey string
+	// BearerToken authenticates the requests with a JWT, used when APIKey is empty
+	BearerToken string
+}
+
+// Client calls the API served at a base URL. It is safe for concurrent use.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	options    Options
+}
+
+// New returns a Client for the API served at baseURL, e.g. http://localhost:8080.
+func New(baseURL string, options Options) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL %q: %w", baseURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme must be http or https", baseURL)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+
+	httpClient := options.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	if options.RetryBackoff <= 0 {
+		options.RetryBackoff = defaultRetryBackoff
+	}
+	return &Client{baseURL: u, httpClient: httpClient, options: options}, nil
+}
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey returns a context making the write it is passed to use the given Idempotency-Key
+// instead of a generated one, e.g. to retry a calculation across restarts of the caller.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// WithRequestID returns a context making the requests it is passed to be sent with the given X-Request-ID, so that they
+// can be found in the logs of the service. Contexts of requests served by the service carry their ID already.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return logging.WithRequestID(ctx, id)
+}
+
+// Health returns the readiness of the API and the result of each of its checks. A service that is not ready
+// answers with an *APIError of status 503, whose Detail is the report.
+func (c *Client) Health(ctx context.Context) (*HealthResponse, error) {
+	var health HealthResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/api/health/ready", retry: true}, &health); err != nil {
+		return nil, err
+	}
+	return &health, nil
+}
+
+// Live returns the liveness of the API, up as long as the process serves requests. Unlike Health,
+// it checks none of the dependencies of the service.
+func (c *Client) Live(ctx context.Context) (*HealthResponse, error) {
+	var health HealthResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/api/health/live", retry: true}, &health); err != nil {
+		return nil, err
+	}
+	return &health, nil
+}
+
+// request is an API call, sent once per attempt
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	body   any
+	// retry tells whether the call is safe to send again
+	retry bool
+}
+
+// idempotent makes the request a write that is safe to retry
+func (r request) idempotent(ctx context.Context) (request, error) {
+	key, _ := ctx.Value(idempotencyKeyContextKey{}).(string)
+	if key == "" {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return r, fmt.Errorf("failed to generate idempotency key: %w", err)
+		}
+		key = hex.EncodeToString(b)
+	}
+	if r.header == nil {
+		r.header = http.Header{}
+	}
+	r.header.Set(idempotencyKeyHeader, key)
+	r.retry = true
+	return r, nil
+}
+
+// do sends the request, retrying it when allowed, and decodes the response into out
+func (c *Client) do(ctx context.Context, r request, out any) error {
+	var body []byte
+	if r.body != nil {
+		var err error
+		if body, err = json.Marshal(r.body); err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+
+	u := *c.baseURL
+	u.Path += r.path
+	u.RawQuery = r.query.Encode()
+
+	for attempt := 0; ; attempt++ {
+		retry, wait, err := c.attempt(ctx, r, u.String(), body, out)
+		if err == nil || !r.retry || !retry || attempt >= c.options.MaxRetries {
+			return err
+		}
+
+		if wait == 0 {
+			wait = c.options.RetryBackoff << attempt
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt sends the request once. On failure it reports whether the request may be retried
+// and how long the server asked to wait first.
+func (c *Client) attempt(ctx context.Context, r request, u string, body []byte, out any) (bool, time.Duration, error) {
+	attemptCtx := ctx
+	if c.options.Timeout > 0 {
+		var cancel context.CancelFunc
+		attemptCtx, cancel = context.WithTimeout(ctx, c.options.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(attemptCtx, r.method, u, bytes.NewReader(body))
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to build request: %w", err)
+	}
+	for name, values := range r.header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	switch {
+	case c.options.APIKey != "":
+		req.Header.Set(apiKeyHeader, c.options.APIKey)
+	case c.options.BearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+c.options.BearerToken)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if id := logging.RequestIDFrom(ctx); id != "" {
+		req.Header.Set(requestIDHeader, id)
+	}
+	// The server continues the trace of the span carried by ctx, as a traceparent header
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// The caller's context ending is final, anything else, including the attempt timing out, may be transient
+		return ctx.Err() == nil, 0, fmt.Errorf("%s %s: %w", r.method, r.path, err)
+	}
+	defer resp.Body.Close()
+
+	payload, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return true, 0, fmt.Errorf("%s %s: failed to read response: %w", r.method, r.path, err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := newAPIError(resp.StatusCode, payload)
+		if id := resp.Header.Get(requestIDHeader); id != "" {
+			apiErr.RequestID = id
+		}
+		return apiErr.temporary(), retryAfter(resp.Header.Get("Retry-After")), apiErr
+	}
+	if out != nil {
+		if err := json.Unmarshal(payload, out); err != nil {
+			return false, 0, fmt.Errorf("%s %s: failed to decode response: %w", r.method, r.path, err)
+		}
+	}
+	return false, 0, nil
+}
+
+// retryAfter parses a Retry-After header given in seconds
+func retryAfter(header string) time.Duration {
+	seconds, err := strconv.Atoi(header)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,361 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"order-pack-calculator/internal/app"
+	"order-pack-calculator/internal/domain/dto"
+	"order-pack-calculator/internal/server"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newTestClient returns a client of the API served with in-memory storage
+func newTestClient(t *testing.T) *Client {
+	gin.SetMode(gin.TestMode)
+
+	a, err := app.New(app.Config{Storage: app.StorageConfig{Kind: "memory", SeedPath: "../../seeds/pack_sizes.yaml"}, IdempotencyTTL: time.Hour})
+	assert.NoError(t, err)
+	key, err := a.APIKeyService.Create(context.Background(), dto.CreateAPIKeyRequest{Name: "client test", Roles: []string{"admin"}})
+	assert.NoError(t, err)
+	ts := httptest.NewServer(server.NewServer(a).Handler)
+	t.Cleanup(ts.Close)
+
+	c, err := New(ts.URL, Options{APIKey: key.Key})
+	assert.NoError(t, err)
+	return c
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t)
+
+	t.Run("live", func(t *testing.T) {
+		live, err := c.Live(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, "up", live.Status)
+		assert.Empty(t, live.Checks)
+	})
+
+	t.Run("health", func(t *testing.T) {
+		health, err := c.Health(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, "up", health.Status)
+		assert.Contains(t, health.Checks, "cache")
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		anonymous, _ := New(c.baseURL.String(), Options{})
+		_, err := anonymous.ListOrders(ctx, ListOrdersRequest{})
+		assert.ErrorIs(t, err, ErrUnauthenticated)
+
+		anonymous, _ = New(c.baseURL.String(), Options{BearerToken: "a.b.c"})
+		_, err = anonymous.ListOrders(ctx, ListOrdersRequest{})
+		assert.ErrorIs(t, err, ErrUnauthenticated)
+	})
+
+	t.Run("pack sizes", func(t *testing.T) {
+		created, err := c.CreatePackSize(ctx, CreatePackSizeRequest{ProductID: 42, Size: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, 10, created.Size)
+
+		fetched, err := c.GetPackSize(ctx, created.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, created, fetched)
+
+		size := 12
+		updated, err := c.UpdatePackSize(ctx, UpdatePackSizeRequest{ID: created.ID, Size: &size, Versions: []int64{fetched.Version}})
+		assert.NoError(t, err)
+		assert.Equal(t, 12, updated.Size)
+
+		_, err = c.UpdatePackSize(ctx, UpdatePackSizeRequest{ID: created.ID, Size: &size, Versions: []int64{fetched.Version}})
+		assert.ErrorIs(t, err, ErrPreconditionFailed)
+
+		page, err := c.ListPackSizes(ctx, ListPackSizesRequest{ProductID: 42, MinSize: 11})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), page.Total)
+		assert.Equal(t, []PackSizeResponse{*updated}, page.Items)
+
+		_, err = c.GetPackSize(ctx, 999999)
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		_, err = c.CreatePackSize(ctx, CreatePackSizeRequest{ProductID: 42})
+		assert.ErrorIs(t, err, ErrValidation)
+		var apiErr *APIError
+		assert.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, []FieldError{{Field: "size", Rule: "required", Message: "is required"}}, apiErr.Fields)
+	})
+
+	t.Run("orders", func(t *testing.T) {
+		calculated, err := c.CalculatePacks(ctx, CalculatePackSizesRequest{ProductID: 1, OrderQuantity: 263})
+		assert.NoError(t, err)
+		assert.Equal(t, 263, calculated.TotalItems)
+		assert.Equal(t, []PackDetail{{Size: 31, Count: 7}, {Size: 23, Count: 2}}, calculated.PackCombination)
+
+		quantity := 500
+		recalculated, err := c.RecalculateOrder(ctx, calculated.OrderID, RecalculateOrderRequest{OrderQuantity: &quantity})
+		assert.NoError(t, err)
+		assert.Equal(t, 500, recalculated.OrderQuantity)
+
+		for _, transition := range []func(context.Context, int64) (*OrderResponse, error){c.ConfirmOrder, c.PackOrder, c.ShipOrder} {
+			_, err := transition(ctx, calculated.OrderID)
+			assert.NoError(t, err)
+		}
+
+		order, err := c.GetOrder(ctx, calculated.OrderID)
+		assert.NoError(t, err)
+		assert.Equal(t, "shipped", order.Status)
+
+		_, err = c.CancelOrder(ctx, calculated.OrderID)
+		assert.ErrorIs(t, err, ErrConflict)
+		assert.ErrorIs(t, err, ErrInvalidTransition)
+
+		_, err = c.RecalculateOrder(ctx, calculated.OrderID, RecalculateOrderRequest{})
+		assert.ErrorIs(t, err, ErrOrderNotDraft)
+
+		orders, err := c.ListOrders(ctx, ListOrdersRequest{ProductID: 1, Status: "shipped"})
+		assert.NoError(t, err)
+		assert.Len(t, orders, 1)
+		assert.Equal(t, order.ID, orders[0].ID)
+
+		_, err = c.GetOrder(ctx, 999999)
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.NotErrorIs(t, err, ErrConflict)
+
+		var apiErr *APIError
+		assert.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, "not_found", apiErr.Code)
+		assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+		assert.NotEmpty(t, apiErr.RequestID)
+	})
+
+	t.Run("request id from the context", func(t *testing.T) {
+		_, err := c.GetOrder(WithRequestID(ctx, "client-test-1"), 999999)
+		var apiErr *APIError
+		assert.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, "client-test-1", apiErr.RequestID)
+	})
+
+	t.Run("idempotency key from the context", func(t *testing.T) {
+		keyed := WithIdempotencyKey(ctx, "client-test")
+		first, err := c.CalculatePacks(keyed, CalculatePackSizesRequest{ProductID: 1, OrderQuantity: 10})
+		assert.NoError(t, err)
+		second, err := c.CalculatePacks(keyed, CalculatePackSizesRequest{ProductID: 1, OrderQuantity: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, first.OrderID, second.OrderID)
+
+		_, err = c.CalculatePacks(keyed, CalculatePackSizesRequest{ProductID: 1, OrderQuantity: 11})
+		assert.ErrorIs(t, err, ErrIdempotencyKeyReused)
+	})
+}
+
+// flaky serves the given statuses one request at a time, then 200 responses, and records the requests
+type flaky struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+}
+
+func (f *flaky) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests = append(f.requests, r)
+	if len(f.statuses) > 0 {
+		status := f.statuses[0]
+		f.statuses = f.statuses[1:]
+		w.WriteHeader(status)
+		w.Write([]byte("upstream unavailable"))
+		return
+	}
+	w.Write([]byte(`{"order_id":1,"pack_combination":[],"total_items":0,"total_packs":0}`))
+}
+
+func TestRetries(t *testing.T) {
+	ctx := context.Background()
+	newClient := func(t *testing.T, handler http.Handler, options Options) *Client {
+		ts := httptest.NewServer(handler)
+		t.Cleanup(ts.Close)
+		if options.RetryBackoff == 0 {
+			options.RetryBackoff = time.Millisecond
+		}
+		c, err := New(ts.URL, options)
+		assert.NoError(t, err)
+		return c
+	}
+
+	t.Run("reads are retried", func(t *testing.T) {
+		f := &flaky{statuses: []int{http.StatusServiceUnavailable, http.StatusBadGateway}}
+		c := newClient(t, f, Options{MaxRetries: 2})
+
+		_, err := c.GetOrder(ctx, 1)
+		assert.NoError(t, err)
+		assert.Len(t, f.requests, 3)
+	})
+
+	t.Run("retries are bounded", func(t *testing.T) {
+		f := &flaky{statuses: []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable}}
+		c := newClient(t, f, Options{MaxRetries: 1})
+
+		_, err := c.GetOrder(ctx, 1)
+		var apiErr *APIError
+		assert.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+		assert.Equal(t, "upstream unavailable", apiErr.Detail)
+		assert.Len(t, f.requests, 2)
+	})
+
+	t.Run("calculations are retried with the same idempotency key", func(t *testing.T) {
+		f := &flaky{statuses: []int{http.StatusTooManyRequests}}
+		c := newClient(t, f, Options{MaxRetries: 3})
+
+		_, err := c.CalculatePacks(ctx, CalculatePackSizesRequest{ProductID: 1, OrderQuantity: 10})
+		assert.NoError(t, err)
+		assert.Len(t, f.requests, 2)
+		key := f.requests[0].Header.Get(idempotencyKeyHeader)
+		assert.NotEmpty(t, key)
+		assert.Equal(t, key, f.requests[1].Header.Get(idempotencyKeyHeader))
+	})
+
+	t.Run("transitions are not retried", func(t *testing.T) {
+		f := &flaky{statuses: []int{http.StatusServiceUnavailable}}
+		c := newClient(t, f, Options{MaxRetries: 3})
+
+		_, err := c.ConfirmOrder(ctx, 1)
+		assert.Error(t, err)
+		assert.Len(t, f.requests, 1)
+	})
+
+	t.Run("client errors are not retried", func(t *testing.T) {
+		f := &flaky{statuses: []int{http.StatusBadRequest}}
+		c := newClient(t, f, Options{MaxRetries: 3})
+
+		_, err := c.GetOrder(ctx, 1)
+		assert.Error(t, err)
+		assert.Len(t, f.requests, 1)
+	})
+
+	t.Run("attempts time out", func(t *testing.T) {
+		var (
+			mu       sync.Mutex
+			attempts int
+		)
+		slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			attempts++
+			first := attempts == 1
+			mu.Unlock()
+			if first {
+				<-r.Context().Done()
+				return
+			}
+			w.Write([]byte(`{"id":1}`))
+		})
+		c := newClient(t, slow, Options{Timeout: 50 * time.Millisecond, MaxRetries: 1})
+
+		order, err := c.GetOrder(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), order.ID)
+	})
+
+	t.Run("a cancelled context stops retrying", func(t *testing.T) {
+		f := &flaky{statuses: []int{http.StatusServiceUnavailable}}
+		c := newClient(t, f, Options{MaxRetries: 3, RetryBackoff: time.Hour})
+
+		ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		_, err := c.GetOrder(ctx, 1)
+		assert.Error(t, err)
+		assert.Len(t, f.requests, 1)
+	})
+}
+
+func TestTraceContext(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator()) })
+
+	var traceparent string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.Write([]byte(`{"status": "up"}`))
+	}))
+	defer ts.Close()
+	c, err := New(ts.URL, Options{})
+	assert.NoError(t, err)
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled,
+	}))
+	_, err = c.Health(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", traceparent)
+
+	_, err = c.Health(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, traceparent, "requests outside of a span start a trace of their own")
+}
+
+func TestHealthNotReady(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/health/ready", r.URL.Path)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"status": "down", "message": "shutting down"}`))
+	}))
+	defer ts.Close()
+	c, err := New(ts.URL, Options{})
+	assert.NoError(t, err)
+
+	health, err := c.Health(context.Background())
+	assert.Nil(t, health)
+	var apiErr *APIError
+	if assert.ErrorAs(t, err, &apiErr) {
+		assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+		assert.Contains(t, apiErr.Detail, "shutting down")
+	}
+}
+
+func TestAPIErrorIs(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    *APIError
+		target error
+		want   bool
+	}{
+		{name: "404", err: &APIError{StatusCode: http.StatusNotFound}, target: ErrNotFound, want: true},
+		{name: "code", err: &APIError{StatusCode: http.StatusConflict, Code: "order_not_draft"}, target: ErrOrderNotDraft, want: true},
+		{name: "any 409 is a conflict", err: &APIError{StatusCode: http.StatusConflict, Code: "order_not_draft"}, target: ErrConflict, want: true},
+		{name: "412", err: &APIError{StatusCode: http.StatusPreconditionFailed}, target: ErrPreconditionFailed, want: true},
+		{name: "forbidden", err: &APIError{StatusCode: http.StatusForbidden, Code: "forbidden"}, target: ErrForbidden, want: true},
+		{name: "request too large", err: &APIError{StatusCode: http.StatusRequestEntityTooLarge, Code: "request_too_large"}, target: ErrRequestTooLarge, want: true},
+		{name: "quota exceeded", err: &APIError{StatusCode: http.StatusTooManyRequests, Code: "quota_exceeded"}, target: ErrQuotaExceeded, want: true},
+		{name: "other code", err: &APIError{StatusCode: http.StatusBadRequest, Code: "invalid_request"}, target: ErrNotFound, want: false},
+		{name: "unknown code", err: &APIError{StatusCode: http.StatusBadRequest, Code: "boom"}, target: ErrInvalidRequest, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, errors.Is(tt.err, tt.target))
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	_, err := New("localhost:8080", Options{})
+	assert.Error(t, err)
+
+	c, err := New("http://localhost:8080/", Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080", c.baseURL.String())
+}
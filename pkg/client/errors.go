@@ -0,0 +1,118 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	errs "order-pack-calculator/internal/domain/errors"
+)
+
+// Errors reported by the API. They are the errors of the service itself, so they match
+// the errors returned by its Go packages as well.
+var (
+	// ErrUnauthenticated matches 401 responses: the API key or token is missing, unknown, revoked or expired
+	ErrUnauthenticated = errs.ErrUnauthenticated
+	// ErrForbidden matches 403 responses: the roles of the caller do not allow the route, or the product is not one of its products
+	ErrForbidden = errs.ErrForbidden
+	// ErrNotFound matches 404 responses
+	ErrNotFound = errs.ErrNotFound
+	// ErrConflict matches every 409 response: the request conflicts with the current state of a resource
+	ErrConflict = errs.ErrConflict
+	// ErrPreconditionFailed matches 412 responses: the pack size changed since the version sent in If-Match
+	ErrPreconditionFailed = errs.ErrPreconditionFailed
+	// ErrRequestTooLarge matches 413 responses: the request body exceeds the limit of the service
+	ErrRequestTooLarge = errs.ErrRequestTooLarge
+	// ErrRateLimited matches 429 responses to clients exceeding their request limit
+	ErrRateLimited = errs.ErrRateLimited
+	// ErrQuotaExceeded matches 429 responses to clients exceeding their compute quota
+	ErrQuotaExceeded = errs.ErrQuotaExceeded
+
+	// ErrInvalidRequest matches malformed requests, which could not be decoded
+	ErrInvalidRequest = errs.ErrInvalidRequest
+	// ErrValidation matches requests with invalid fields, breaking the rules of the request or a limit of the service
+	ErrValidation = errs.ErrValidation
+
+	ErrInvalidValidityPeriod    = errs.ErrInvalidValidityPeriod
+	ErrInvalidTransition        = errs.ErrInvalidTransition
+	ErrOrderNotDraft            = errs.ErrOrderNotDraft
+	ErrInvalidCursor            = errs.ErrInvalidCursor
+	ErrInvalidIdempotencyKey    = errs.ErrInvalidIdempotencyKey
+	ErrIdempotencyKeyReused     = errs.ErrIdempotencyKeyReused
+	ErrIdempotencyKeyInProgress = errs.ErrIdempotencyKeyInProgress
+)
+
+// codes maps the problem codes of the API to the errors they report
+var codes = map[string]error{
+	"request_too_large":           ErrRequestTooLarge,
+	"invalid_request":             ErrInvalidRequest,
+	"validation_failed":           ErrValidation,
+	"invalid_cursor":              ErrInvalidCursor,
+	"invalid_idempotency_key":     ErrInvalidIdempotencyKey,
+	"unauthenticated":             ErrUnauthenticated,
+	"forbidden":                   ErrForbidden,
+	"not_found":                   ErrNotFound,
+	"invalid_transition":          ErrInvalidTransition,
+	"order_not_draft":             ErrOrderNotDraft,
+	"idempotency_key_in_progress": ErrIdempotencyKeyInProgress,
+	"conflict":                    ErrConflict,
+	"precondition_failed":         ErrPreconditionFailed,
+	"invalid_validity_period":     ErrInvalidValidityPeriod,
+	"idempotency_key_reused":      ErrIdempotencyKeyReused,
+	"rate_limited":                ErrRateLimited,
+	"quota_exceeded":              ErrQuotaExceeded,
+}
+
+// APIError is an error response of the API
+type APIError struct {
+	StatusCode int
+	// Code, Title and Detail are the fields of the problem details body, Code being empty
+	// and Title and Detail the status text and the raw body when the response is not one.
+	Code   string
+	Title  string
+	Detail string
+	// Fields lists the invalid fields of a request that failed validation
+	Fields []FieldError
+	// RequestID identifies the request in the logs of the service
+	RequestID string
+}
+
+func newAPIError(statusCode int, body []byte) *APIError {
+	var response ErrorResponse
+	if err := json.Unmarshal(body, &response); err != nil || response.Code == "" {
+		return &APIError{StatusCode: statusCode, Title: http.StatusText(statusCode), Detail: strings.TrimSpace(string(body))}
+	}
+	return &APIError{StatusCode: statusCode, Code: response.Code, Title: response.Title, Detail: response.Detail, Fields: response.Errors, RequestID: response.RequestID}
+}
+
+func (e *APIError) Error() string {
+	if e.Detail == "" {
+		return fmt.Sprintf("%s (status %d)", e.Title, e.StatusCode)
+	}
+	return fmt.Sprintf("%s (status %d): %s", e.Title, e.StatusCode, e.Detail)
+}
+
+// Is matches the status of the response, then the error reported by its code.
+func (e *APIError) Is(target error) bool {
+	switch {
+	case target == ErrNotFound && e.StatusCode == http.StatusNotFound,
+		target == ErrConflict && e.StatusCode == http.StatusConflict,
+		target == ErrPreconditionFailed && e.StatusCode == http.StatusPreconditionFailed:
+		return true
+	default:
+		err, ok := codes[e.Code]
+		return ok && err == target
+	}
+}
+
+// temporary tells whether the same request may succeed later
+func (e *APIError) temporary() bool {
+	switch e.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		// The first request with the same Idempotency-Key is still running
+		return e.Is(ErrIdempotencyKeyInProgress)
+	}
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// CalculatePacks calculates the packs of an order and stores it as a draft order.
+func (c *Client) CalculatePacks(ctx context.Context, calculate CalculatePackSizesRequest) (*OptimalPackSizesResponse, error) {
+	r, err := request{method: http.MethodPost, path: "/api/v1/orders/calculate", body: calculate}.idempotent(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var solution OptimalPackSizesResponse
+	if err := c.do(ctx, r, &solution); err != nil {
+		return nil, err
+	}
+	return &solution, nil
+}
+
+// ListOrders returns stored orders, most recent first.
+func (c *Client) ListOrders(ctx context.Context, list ListOrdersRequest) ([]OrderResponse, error) {
+	query := url.Values{}
+	setInt(query, "product_id", list.ProductID)
+	setString(query, "status", list.Status)
+	if list.CreatedFrom != nil {
+		query.Set("created_from", list.CreatedFrom.Format(time.RFC3339))
+	}
+	if list.CreatedTo != nil {
+		query.Set("created_to", list.CreatedTo.Format(time.RFC3339))
+	}
+	setInt(query, "limit", list.Limit)
+	setInt(query, "offset", list.Offset)
+
+	var orders []OrderResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/orders/", query: query, retry: true}, &orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// GetOrder returns the order with the given ID.
+func (c *Client) GetOrder(ctx context.Context, ID int64) (*OrderResponse, error) {
+	var order OrderResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/api/v1/orders/%d", ID), retry: true}, &order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// ConfirmOrder moves a draft order to confirmed, freezing its pack combination.
+func (c *Client) ConfirmOrder(ctx context.Context, ID int64) (*OrderResponse, error) {
+	return c.transition(ctx, ID, "confirm")
+}
+
+// PackOrder moves a confirmed order to packed.
+func (c *Client) PackOrder(ctx context.Context, ID int64) (*OrderResponse, error) {
+	return c.transition(ctx, ID, "pack")
+}
+
+// ShipOrder moves a packed order to shipped.
+func (c *Client) ShipOrder(ctx context.Context, ID int64) (*OrderResponse, error) {
+	return c.transition(ctx, ID, "ship")
+}
+
+// CancelOrder cancels an order that is not shipped yet.
+func (c *Client) CancelOrder(ctx context.Context, ID int64) (*OrderResponse, error) {
+	return c.transition(ctx, ID, "cancel")
+}
+
+// transition moves an order along its lifecycle. A transition the order does not allow fails with ErrInvalidTransition.
+func (c *Client) transition(ctx context.Context, ID int64, action string) (*OrderResponse, error) {
+	var order OrderResponse
+	if err := c.do(ctx, request{method: http.MethodPost, path: fmt.Sprintf("/api/v1/orders/%d/%s", ID, action)}, &order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// RecalculateOrder calculates a draft order again against the current pack sizes, or those of recalculate.AsOf.
+// It fails with ErrOrderNotDraft once the order is confirmed.
+func (c *Client) RecalculateOrder(ctx context.Context, ID int64, recalculate RecalculateOrderRequest) (*OrderResponse, error) {
+	var order OrderResponse
+	if err := c.do(ctx, request{method: http.MethodPost, path: fmt.Sprintf("/api/v1/orders/%d/recalculate", ID), body: recalculate}, &order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ListPackSizes returns a page of pack sizes. Pass the NextCursor of a page as Cursor to get the following one.
+func (c *Client) ListPackSizes(ctx context.Context, list ListPackSizesRequest) (*PackSizePageResponse, error) {
+	query := url.Values{}
+	setInt(query, "product_id", list.ProductID)
+	if list.Active != nil {
+		query.Set("active", strconv.FormatBool(*list.Active))
+	}
+	setInt(query, "min_size", list.MinSize)
+	setInt(query, "max_size", list.MaxSize)
+	setString(query, "sort", list.Sort)
+	setString(query, "order", list.Order)
+	setInt(query, "limit", list.Limit)
+	setString(query, "cursor", list.Cursor)
+
+	var page PackSizePageResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/api/v1/packsizes/", query: query, retry: true}, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// GetPackSize returns the pack size with the given ID.
+func (c *Client) GetPackSize(ctx context.Context, ID int64) (*PackSizeResponse, error) {
+	var pack PackSizeResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: fmt.Sprintf("/api/v1/packsizes/%d", ID), retry: true}, &pack); err != nil {
+		return nil, err
+	}
+	return &pack, nil
+}
+
+// CreatePackSize adds a pack size to a product.
+func (c *Client) CreatePackSize(ctx context.Context, create CreatePackSizeRequest) (*PackSizeResponse, error) {
+	r, err := request{method: http.MethodPost, path: "/api/v1/packsizes/", body: create}.idempotent(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pack PackSizeResponse
+	if err := c.do(ctx, r, &pack); err != nil {
+		return nil, err
+	}
+	return &pack, nil
+}
+
+// UpdatePackSize changes a pack size. When update.Versions is set it is sent as If-Match,
+// and the update fails with ErrPreconditionFailed if the pack size is at none of them.
+func (c *Client) UpdatePackSize(ctx context.Context, update UpdatePackSizeRequest) (*PackSizeResponse, error) {
+	r, err := request{method: http.MethodPatch, path: "/api/v1/packsizes/", body: update}.idempotent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(update.Versions) > 0 {
+		tags := make([]string, len(update.Versions))
+		for i, version := range update.Versions {
+			tags[i] = strconv.Quote(strconv.FormatInt(version, 10))
+		}
+		r.header.Set("If-Match", strings.Join(tags, ", "))
+	}
+
+	var pack PackSizeResponse
+	if err := c.do(ctx, r, &pack); err != nil {
+		return nil, err
+	}
+	return &pack, nil
+}
+
+func setInt(query url.Values, key string, value int) {
+	if value != 0 {
+		query.Set(key, strconv.Itoa(value))
+	}
+}
+
+func setString(query url.Values, key, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
@@ -0,0 +1,25 @@
+package client
+
+import "order-pack-calculator/internal/domain/dto"
+
+// The request and response types of the API, aliased so they can be used outside this module
+type (
+	ErrorResponse = dto.ErrorResponse
+	FieldError    = dto.FieldError
+
+	HealthResponse      = dto.HealthResponse
+	HealthCheckResponse = dto.HealthCheckResponse
+
+	CreatePackSizeRequest = dto.CreatePackSizeRequest
+	UpdatePackSizeRequest = dto.UpdatePackSizeRequest
+	ListPackSizesRequest  = dto.ListPackSizesRequest
+	PackSizeResponse      = dto.PackSizeResponse
+	PackSizePageResponse  = dto.PackSizePageResponse
+
+	CalculatePackSizesRequest = dto.CalculatePackSizesRequest
+	OptimalPackSizesResponse  = dto.OptimalPackSizesResponse
+	PackDetail                = dto.PackDetail
+	ListOrdersRequest         = dto.ListOrdersRequest
+	RecalculateOrderRequest   = dto.RecalculateOrderRequest
+	OrderResponse             = dto.OrderResponse
+)
@@ -0,0 +1,47 @@
+package packing_test
+
+import (
+	"errors"
+	"fmt"
+
+	"order-pack-calculator/pkg/packing"
+)
+
+func ExampleSolve() {
+	result, err := packing.Solve([]int{250, 500, 1000, 2000, 5000}, 12001, packing.Options{})
+	if err != nil {
+		panic(err)
+	}
+
+	for _, pack := range result.Packs {
+		fmt.Printf("%d x %d\n", pack.Count, pack.Size)
+	}
+	fmt.Printf("%d items in %d packs\n", result.TotalItems, result.TotalPacks)
+	// Output:
+	// 2 x 5000
+	// 1 x 2000
+	// 1 x 250
+	// 12250 items in 4 packs
+}
+
+func ExampleSolve_overfill() {
+	// 10 items cannot be made up of packs of 6 and 8, so 12 are sent
+	result, err := packing.Solve([]int{6, 8}, 10, packing.Options{})
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("%+v\n", result)
+	// Output:
+	// {Packs:[{Size:6 Count:2}] TotalItems:12 TotalPacks:2}
+}
+
+func ExampleOptions_maxQuantity() {
+	_, err := packing.Solve([]int{23, 31, 53}, 5000000, packing.Options{MaxQuantity: 1000000})
+
+	fmt.Println(errors.Is(err, packing.ErrQuantityTooLarge))
+	fmt.Println(err)
+	// Output:
+	// true
+	// packing: quantity too large: 5000000 exceeds the maximum of 1000000
+}
@@ -0,0 +1,278 @@
+// Package packing finds how to fulfil an order with whole packs of the available sizes.
+//
+// Packs cannot be broken, so an order is fulfilled by sending at least the ordered quantity.
+// Solve sends as few items as possible and, among the combinations sending that many items,
+// uses as few packs as possible.
+//
+// The package has no dependencies besides the standard library and holds no state,
+// so it is safe for concurrent use.
+package packing
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+var (
+	// ErrInvalidQuantity is returned when the quantity to pack is not positive
+	ErrInvalidQuantity = errors.New("packing: quantity must be positive")
+	// ErrNoPackSizes is returned when no pack size is given
+	ErrNoPackSizes = errors.New("packing: no pack sizes")
+	// ErrInvalidPackSize is returned when a pack size is not positive
+	ErrInvalidPackSize = errors.New("packing: pack sizes must be positive")
+	// ErrQuantityTooLarge is returned when the quantity exceeds Options.MaxQuantity, or is too large
+	// for the memory Solve allows itself given the pack sizes
+	ErrQuantityTooLarge = errors.New("packing: quantity too large")
+)
+
+// Pack is a number of packs of the same size
+type Pack struct {
+	Size  int `json:"size"`
+	Count int `json:"count"`
+}
+
+// Result is the combination of packs fulfilling an order
+type Result struct {
+	// Packs holds one entry per size used, largest size first
+	Packs []Pack `json:"packs"`
+	// TotalItems is the number of items sent, at least the quantity ordered
+	TotalItems int `json:"total_items"`
+	// TotalPacks is the number of packs sent
+	TotalPacks int `json:"total_packs"`
+}
+
+// Options tunes Solve. The zero value is ready to use.
+type Options struct {
+	// MaxQuantity makes Solve reject larger quantities with ErrQuantityTooLarge. Zero means no limit
+	// besides the memory bound of Solve. Solve may still need time proportional to the quantity,
+	// so callers taking quantities from untrusted input should set it.
+	MaxQuantity int
+	// Stats, when set, receives how the quantity was solved
+	Stats *Stats
+}
+
+// Methods Solve works out results with
+const (
+	// MethodTable finds the fewest packs of every total up to the quantity plus the largest size
+	MethodTable = "table"
+	// MethodPeriodic finds the fewest packs of every remainder modulo the largest size, for large quantities
+	MethodPeriodic = "periodic"
+)
+
+// Stats describes how Solve worked out a result, e.g. for monitoring
+type Stats struct {
+	// Method is MethodTable or MethodPeriodic
+	Method string
+	// TableSize is the number of totals or remainders the method kept track of, which its memory is proportional to
+	TableSize int
+}
+
+// Solve returns the combination of packs of the given sizes that fulfils quantity. It guarantees that:
+//
+//   - TotalItems is the smallest total of at least quantity items that the sizes can make up,
+//   - TotalPacks is the smallest number of packs making up TotalItems,
+//   - Packs only lists sizes that are used, largest first, and adds up to TotalItems and TotalPacks,
+//   - the result only depends on the set of sizes, not on their order or repetitions,
+//   - sizes is not modified.
+//
+// Up to a million items or so, it runs in O((quantity + largest size) × number of sizes) time and memory
+// proportional to the quantity. Larger quantities are solved in time and memory depending on the largest
+// size only, as long as it is no more than the square root of the quantity and about a million. Other
+// quantities would need more memory than that and are rejected with ErrQuantityTooLarge, as are those
+// whose total could overflow an int.
+func Solve(sizes []int, quantity int, options Options) (Result, error) {
+	if quantity < 1 {
+		return Result{}, fmt.Errorf("%w, got %d", ErrInvalidQuantity, quantity)
+	}
+	if options.MaxQuantity > 0 && quantity > options.MaxQuantity {
+		return Result{}, fmt.Errorf("%w: %d exceeds the maximum of %d", ErrQuantityTooLarge, quantity, options.MaxQuantity)
+	}
+	if len(sizes) == 0 {
+		return Result{}, ErrNoPackSizes
+	}
+
+	// Sizes are deduplicated and ordered, largest first, so ties are always broken the same way
+	sizes = slices.Clone(sizes)
+	slices.Sort(sizes)
+	slices.Reverse(sizes)
+	sizes = slices.Compact(sizes)
+	if sizes[len(sizes)-1] < 1 {
+		return Result{}, fmt.Errorf("%w, got %d", ErrInvalidPackSize, sizes[len(sizes)-1])
+	}
+
+	// The total sent exceeds quantity by less than the largest size, it must not overflow
+	if quantity > math.MaxInt-sizes[0] {
+		return Result{}, fmt.Errorf("%w: %d items cannot be packed in sizes of up to %d", ErrQuantityTooLarge, quantity, sizes[0])
+	}
+
+	// Quantities too large for the table of solveTable are solved by solvePeriodic when they can,
+	// and rejected when neither fits in tableLimit
+	stats := Stats{Method: MethodTable, TableSize: quantity + sizes[0]}
+	if stats.TableSize-1 > tableLimit {
+		if !periodic(sizes[0], quantity) || sizes[0] > tableLimit {
+			return Result{}, fmt.Errorf("%w: %d items in sizes of up to %d need a table of more than %d entries",
+				ErrQuantityTooLarge, quantity, sizes[0], tableLimit)
+		}
+		stats = Stats{Method: MethodPeriodic, TableSize: sizes[0]}
+	}
+	if options.Stats != nil {
+		*options.Stats = stats
+	}
+	if stats.Method == MethodPeriodic {
+		return solvePeriodic(sizes, quantity), nil
+	}
+	return solveTable(sizes, quantity), nil
+}
+
+// tableLimit is the number of totals above which solveTable is avoided when possible,
+// its table taking about 12 bytes per total
+const tableLimit = 1 << 20
+
+// solveTable finds the fewest packs making up every total up to quantity + largest size.
+// sizes must be deduplicated and ordered, largest first.
+func solveTable(sizes []int, quantity int) Result {
+	// A total of quantity + largest size or more is never optimal: removing one of its packs
+	// would still leave at least quantity items
+	limit := quantity + sizes[0] - 1
+
+	// packs[i] is the fewest packs making up exactly i items, 0 when i cannot be made up (except for 0 itself).
+	// last[i] is the index of the size of the last pack added to make up i items.
+	packs := make([]int, limit+1)
+	last := make([]int32, limit+1)
+	for i := 0; i <= limit; i++ {
+		if i > 0 && packs[i] == 0 {
+			continue
+		}
+		for s, size := range sizes {
+			next := i + size
+			if next > limit {
+				continue
+			}
+			if packs[next] == 0 || packs[i]+1 < packs[next] {
+				packs[next] = packs[i] + 1
+				last[next] = int32(s)
+			}
+		}
+	}
+
+	total := quantity
+	for packs[total] == 0 {
+		total++
+	}
+
+	counts := make([]int, len(sizes))
+	for i := total; i > 0; i -= sizes[last[i]] {
+		counts[last[i]]++
+	}
+	return newResult(sizes, counts)
+}
+
+// periodic reports whether quantity is at least (largest - 1)², beyond which the optimal
+// combinations repeat themselves, one more pack of the largest size every largest items.
+//
+// Any combination is made of packs of the largest size L and of other packs whose total is
+// congruent to the total of the combination modulo L. The fewest other packs reaching a
+// given remainder form a chain of at most L - 1 packs smaller than L, so past (L - 1)² items
+// every total that is a multiple of the greatest common divisor of the sizes can be made up,
+// and the other packs of its best combination do not depend on the total, only on its remainder.
+func periodic(largest, quantity int) bool {
+	return largest-1 <= quantity/max(largest-1, 1)
+}
+
+// solvePeriodic solves quantities for which periodic holds, in O(L × number of sizes × log L)
+// time and O(L × number of sizes) memory, L being the largest size. sizes must be deduplicated
+// and ordered, largest first.
+func solvePeriodic(sizes []int, quantity int) Result {
+	largest := sizes[0]
+	divisor := largest
+	for _, size := range sizes[1:] {
+		divisor = gcd(divisor, size)
+	}
+	total := (quantity + divisor - 1) / divisor * divisor
+	target := total % largest
+
+	// Replacing items of largest size packs with a pack of size s costs largest - s items worth of packs,
+	// so the remainder is best reached by the smaller packs of least total cost. The costs are found
+	// with Dijkstra's algorithm over the remainders modulo largest, starting from 0.
+	// cost[r] is the least cost reaching remainder r, -1 until reached, and last[r] the index of the size of the last pack.
+	cost := make([]int, largest)
+	last := make([]int32, largest)
+	for r := range cost {
+		cost[r] = -1
+	}
+	cost[0] = 0
+	queue := &remainderQueue{{}}
+	for queue.Len() > 0 {
+		current := heap.Pop(queue).(remainder)
+		if current.cost > cost[current.r] {
+			continue
+		}
+		if current.r == target {
+			break
+		}
+		for s := 1; s < len(sizes); s++ {
+			next := (current.r + sizes[s]) % largest
+			nextCost := current.cost + largest - sizes[s]
+			if cost[next] < 0 || nextCost < cost[next] {
+				cost[next] = nextCost
+				last[next] = int32(s)
+				heap.Push(queue, remainder{r: next, cost: nextCost})
+			}
+		}
+	}
+
+	// The smaller packs are found walking back from the target to 0, the largest ones make up the rest
+	counts := make([]int, len(sizes))
+	items := 0
+	for r := target; r != 0; r = (r - sizes[last[r]] + largest) % largest {
+		counts[last[r]]++
+		items += sizes[last[r]]
+	}
+	counts[0] = (total - items) / largest
+	return newResult(sizes, counts)
+}
+
+// newResult lists the packs of a combination given as a count per size, sizes being ordered largest first
+func newResult(sizes []int, counts []int) Result {
+	var result Result
+	for s, count := range counts {
+		if count > 0 {
+			result.Packs = append(result.Packs, Pack{Size: sizes[s], Count: count})
+			result.TotalItems += sizes[s] * count
+			result.TotalPacks += count
+		}
+	}
+	return result
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// remainder is an entry of the queue of solvePeriodic
+type remainder struct {
+	r    int
+	cost int
+}
+
+// remainderQueue orders remainders by cost, then by value so that ties are broken the same way every time
+type remainderQueue []remainder
+
+func (q remainderQueue) Len() int { return len(q) }
+func (q remainderQueue) Less(i, j int) bool {
+	return q[i].cost < q[j].cost || q[i].cost == q[j].cost && q[i].r < q[j].r
+}
+func (q remainderQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *remainderQueue) Push(x any)   { *q = append(*q, x.(remainder)) }
+func (q *remainderQueue) Pop() any {
+	old := *q
+	x := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return x
+}
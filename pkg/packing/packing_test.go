@@ -0,0 +1,252 @@
+package packing
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		sizes    []int
+		quantity int
+		want     Result
+	}{
+		{
+			name:     "large quantity",
+			sizes:    []int{23, 31, 53},
+			quantity: 500000,
+			want:     Result{Packs: []Pack{{Size: 53, Count: 9429}, {Size: 31, Count: 7}, {Size: 23, Count: 2}}, TotalItems: 500000, TotalPacks: 9438},
+		},
+		{
+			name:     "sends more items than ordered when the quantity cannot be made up",
+			sizes:    []int{6, 8},
+			quantity: 10,
+			want:     Result{Packs: []Pack{{Size: 6, Count: 2}}, TotalItems: 12, TotalPacks: 2},
+		},
+		{
+			name:     "mixes sizes",
+			sizes:    []int{3, 7},
+			quantity: 10,
+			want:     Result{Packs: []Pack{{Size: 7, Count: 1}, {Size: 3, Count: 1}}, TotalItems: 10, TotalPacks: 2},
+		},
+		{
+			name:     "prefers fewer packs for the same number of items",
+			sizes:    []int{5, 10, 20},
+			quantity: 20,
+			want:     Result{Packs: []Pack{{Size: 20, Count: 1}}, TotalItems: 20, TotalPacks: 1},
+		},
+		{
+			name:     "prefers fewer items over fewer packs",
+			sizes:    []int{250, 500, 1000, 2000, 5000},
+			quantity: 501,
+			want:     Result{Packs: []Pack{{Size: 500, Count: 1}, {Size: 250, Count: 1}}, TotalItems: 750, TotalPacks: 2},
+		},
+		{
+			name:     "quantity smaller than every size",
+			sizes:    []int{250, 500},
+			quantity: 1,
+			want:     Result{Packs: []Pack{{Size: 250, Count: 1}}, TotalItems: 250, TotalPacks: 1},
+		},
+		{
+			name:     "repeated sizes",
+			sizes:    []int{7, 3, 7, 3},
+			quantity: 10,
+			want:     Result{Packs: []Pack{{Size: 7, Count: 1}, {Size: 3, Count: 1}}, TotalItems: 10, TotalPacks: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Solve(tt.sizes, tt.quantity, Options{})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSolveErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		sizes    []int
+		quantity int
+		options  Options
+		want     error
+	}{
+		{name: "zero quantity", sizes: []int{5}, quantity: 0, want: ErrInvalidQuantity},
+		{name: "negative quantity", sizes: []int{5}, quantity: -3, want: ErrInvalidQuantity},
+		{name: "no sizes", sizes: nil, quantity: 5, want: ErrNoPackSizes},
+		{name: "zero size", sizes: []int{5, 0}, quantity: 5, want: ErrInvalidPackSize},
+		{name: "negative size", sizes: []int{-5}, quantity: 5, want: ErrInvalidPackSize},
+		{name: "quantity above the maximum", sizes: []int{5}, quantity: 11, options: Options{MaxQuantity: 10}, want: ErrQuantityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Solve(tt.sizes, tt.quantity, tt.options)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+
+	t.Run("quantity at the maximum", func(t *testing.T) {
+		_, err := Solve([]int{5}, 10, Options{MaxQuantity: 10})
+		assert.NoError(t, err)
+	})
+}
+
+func TestSolveIsIndependentOfSizeOrder(t *testing.T) {
+	sizes := []int{4, 6, 9, 10, 15}
+	want, err := Solve(sizes, 37, Options{})
+	assert.NoError(t, err)
+
+	for _, permutation := range [][]int{{15, 10, 9, 6, 4}, {9, 4, 15, 6, 10}, {10, 6, 4, 15, 9, 4}} {
+		got, err := Solve(permutation, 37, Options{})
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+}
+
+func TestSolveDoesNotModifySizes(t *testing.T) {
+	sizes := []int{31, 23, 53, 23}
+	_, err := Solve(sizes, 263, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{31, 23, 53, 23}, sizes)
+}
+
+func TestSolveHugeQuantity(t *testing.T) {
+	// A table of 2^31 totals would take about 25 GiB
+	got, err := Solve([]int{23, 31, 53}, 1<<31, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, Result{Packs: []Pack{{Size: 53, Count: 40518554}, {Size: 31, Count: 7}, {Size: 23, Count: 3}}, TotalItems: 1 << 31, TotalPacks: 40518564}, got)
+
+	got, err = Solve([]int{250, 500, 1000}, 1<<31+1, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, Result{Packs: []Pack{{Size: 1000, Count: 2147483}, {Size: 500, Count: 1}, {Size: 250, Count: 1}}, TotalItems: 2147483750, TotalPacks: 2147485}, got)
+}
+
+func TestSolveQuantityTooLarge(t *testing.T) {
+	for _, test := range []struct {
+		sizes    []int
+		quantity int
+	}{
+		// The total would overflow
+		{[]int{5}, math.MaxInt},
+		{[]int{3, 5}, math.MaxInt - 2},
+		// A table of 2^31 totals would take about 25 GiB, and the quantity is too small for the periodic solution
+		{[]int{50000}, 1 << 31},
+		// The periodic solution would need a table as large as the largest size
+		{[]int{1 << 32, 3}, 1 << 62},
+		// A single pack larger than the table limit
+		{[]int{1 << 40}, 1},
+	} {
+		var stats Stats
+		_, err := Solve(test.sizes, test.quantity, Options{Stats: &stats})
+		assert.ErrorIs(t, err, ErrQuantityTooLarge, "%v %d", test.sizes, test.quantity)
+		assert.Zero(t, stats)
+	}
+
+	// The largest quantities solvable without overflowing
+	got, err := Solve([]int{5}, math.MaxInt-5, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, (math.MaxInt-5)/5*5+5, got.TotalItems)
+}
+
+func TestSolveStats(t *testing.T) {
+	var stats Stats
+	_, err := Solve([]int{23, 31, 53}, 500, Options{Stats: &stats})
+	assert.NoError(t, err)
+	assert.Equal(t, Stats{Method: MethodTable, TableSize: 553}, stats)
+
+	_, err = Solve([]int{23, 31, 53}, 1<<31, Options{Stats: &stats})
+	assert.NoError(t, err)
+	assert.Equal(t, Stats{Method: MethodPeriodic, TableSize: 53}, stats)
+}
+
+func TestSolvePeriodicMatchesSolveTable(t *testing.T) {
+	for _, sizes := range [][]int{{53, 31, 23}, {10, 6, 4}, {1000, 500, 250}, {97, 89, 3}, {12, 8}, {7}, {40, 39, 38, 2}} {
+		largest := sizes[0]
+		for _, quantity := range []int{(largest - 1) * (largest - 1), (largest-1)*(largest-1) + 1, largest * largest, largest*largest + 17, 5 * largest * largest} {
+			if quantity < 1 || !periodic(largest, quantity) {
+				continue
+			}
+			want := solveTable(sizes, quantity)
+			got := solvePeriodic(sizes, quantity)
+			assert.Equal(t, want.TotalItems, got.TotalItems, "%v %d", sizes, quantity)
+			assert.Equal(t, want.TotalPacks, got.TotalPacks, "%v %d", sizes, quantity)
+			assert.Equal(t, newResult(sizes, countsOf(sizes, got.Packs)), got, "%v %d", sizes, quantity)
+		}
+	}
+}
+
+func countsOf(sizes []int, packs []Pack) []int {
+	counts := make([]int, len(sizes))
+	for _, p := range packs {
+		counts[slices.Index(sizes, p.Size)] = p.Count
+	}
+	return counts
+}
+
+func FuzzSolve(f *testing.F) {
+	f.Add(uint16(500), uint8(23), uint8(31), uint8(53))
+	f.Add(uint16(10), uint8(6), uint8(8), uint8(8))
+	f.Add(uint16(1), uint8(250), uint8(1), uint8(0))
+	f.Add(uint16(999), uint8(0), uint8(0), uint8(0))
+
+	f.Fuzz(func(t *testing.T, q uint16, a, b, c uint8) {
+		// Quantities are kept small enough for the brute force to be quick
+		quantity := int(q % 2000)
+		sizes := []int{int(a), int(b), int(c)}
+		got, err := Solve(sizes, quantity, Options{})
+		if quantity == 0 || slices.Contains(sizes, 0) {
+			if err == nil {
+				t.Fatalf("Solve(%v, %d) should fail", sizes, quantity)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("Solve(%v, %d): %v", sizes, quantity, err)
+		}
+
+		var items, packs int
+		for i, p := range got.Packs {
+			if !slices.Contains(sizes, p.Size) || p.Count < 1 {
+				t.Fatalf("Solve(%v, %d) returned an invalid pack %+v", sizes, quantity, p)
+			}
+			if i > 0 && p.Size >= got.Packs[i-1].Size {
+				t.Fatalf("Solve(%v, %d) returned packs out of order: %+v", sizes, quantity, got.Packs)
+			}
+			items += p.Size * p.Count
+			packs += p.Count
+		}
+		if items != got.TotalItems || packs != got.TotalPacks {
+			t.Fatalf("Solve(%v, %d) totals %d items in %d packs, packs add up to %d items in %d packs",
+				sizes, quantity, got.TotalItems, got.TotalPacks, items, packs)
+		}
+
+		wantItems, wantPacks := bruteForce(sizes, quantity)
+		if got.TotalItems != wantItems || got.TotalPacks != wantPacks {
+			t.Fatalf("Solve(%v, %d) sends %d items in %d packs, want %d items in %d packs",
+				sizes, quantity, got.TotalItems, got.TotalPacks, wantItems, wantPacks)
+		}
+	})
+}
+
+// bruteForce tries every combination of three sizes and returns the fewest items of at least
+// quantity, then the fewest packs making them up
+func bruteForce(sizes []int, quantity int) (int, int) {
+	bestItems, bestPacks := -1, -1
+	for i := 0; i*sizes[0] < quantity+sizes[0]; i++ {
+		for j := 0; i*sizes[0]+j*sizes[1] < quantity+sizes[1]; j++ {
+			rest := quantity - i*sizes[0] - j*sizes[1]
+			k := max(0, (rest+sizes[2]-1)/sizes[2])
+			items, packs := i*sizes[0]+j*sizes[1]+k*sizes[2], i+j+k
+			if bestItems < 0 || items < bestItems || items == bestItems && packs < bestPacks {
+				bestItems, bestPacks = items, packs
+			}
+		}
+	}
+	return bestItems, bestPacks
+}